@@ -32,6 +32,15 @@ type ConsistencyError struct {
 	Events []event.Event
 	// EventIndex is the index of the event that caused the Error.
 	EventIndex int
+
+	allowSkippedVersions bool
+}
+
+// ConsistencyOption is an option for ValidateConsistency.
+type ConsistencyOption func(*consistencyConfig)
+
+type consistencyConfig struct {
+	allowSkippedVersions bool
 }
 
 // ConsistencyKind is the kind of inconsistency.
@@ -69,7 +78,15 @@ func IsConsistencyError(err error) bool {
 // The first event e in events that is invalid causes Validate to return an
 // *Error containing the Kind of inconsistency and the event that caused the
 // inconsistency.
-func ValidateConsistency[Data any, Events ~[]event.Of[Data]](a Aggregate, events Events) error {
+//
+// Use the AllowSkippedVersions option to accept gaps between the versions of
+// the events.
+func ValidateConsistency[Data any, Events ~[]event.Of[Data]](a Aggregate, events Events, opts ...ConsistencyOption) error {
+	var cfg consistencyConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	id, name, _ := a.Aggregate()
 	version := currentVersion(a)
 	cv := version
@@ -99,12 +116,13 @@ func ValidateConsistency[Data any, Events ~[]event.Of[Data]](a Aggregate, events
 				EventIndex: i,
 			}
 		}
-		if ev != cv+1 {
+		if (cfg.allowSkippedVersions && ev <= cv) || (!cfg.allowSkippedVersions && ev != cv+1) {
 			return &ConsistencyError{
-				Kind:       InconsistentVersion,
-				Aggregate:  a,
-				Events:     aevents,
-				EventIndex: i,
+				Kind:                 InconsistentVersion,
+				Aggregate:            a,
+				Events:               aevents,
+				EventIndex:           i,
+				allowSkippedVersions: cfg.allowSkippedVersions,
 			}
 		}
 		if hasPrev {
@@ -121,11 +139,28 @@ func ValidateConsistency[Data any, Events ~[]event.Of[Data]](a Aggregate, events
 		}
 		prev = evt
 		hasPrev = true
-		cv++
+		cv = ev
 	}
 	return nil
 }
 
+// AllowSkippedVersions returns a ConsistencyOption that relaxes the version
+// check of ValidateConsistency. When enabled, the versions of the events do
+// not have to be consecutive, so events may be missing from the validated
+// events, for example because they were filtered out. The following
+// invariants are still enforced:
+//
+//   - every event belongs to the validated aggregate (same id and name)
+//   - events[0].AggregateVersion() is greater than a.AggregateVersion()
+//   - the versions of the events are strictly increasing, which also rules out
+//     duplicate versions
+//   - the time of every event is after the time of the previous event
+func AllowSkippedVersions(v bool) ConsistencyOption {
+	return func(cfg *consistencyConfig) {
+		cfg.allowSkippedVersions = v
+	}
+}
+
 // Event return the first event that caused an inconsistency.
 func (err *ConsistencyError) Event() event.Event {
 	if err.EventIndex < 0 || err.EventIndex >= len(err.Events) {
@@ -166,6 +201,12 @@ func (err *ConsistencyError) Error() string {
 			evt.Name(), aname, name,
 		)
 	case InconsistentVersion:
+		if err.allowSkippedVersions {
+			return fmt.Sprintf(
+				"consistency: %q event has invalid AggregateVersion. want=greater than %d got=%d",
+				evt.Name(), err.previousVersion(), v,
+			)
+		}
 		return fmt.Sprintf(
 			"consistency: %q event has invalid AggregateVersion. want=%d got=%d",
 			evt.Name(), currentVersion(err.Aggregate)+1+err.EventIndex, v,
@@ -180,6 +221,14 @@ func (err *ConsistencyError) Error() string {
 	}
 }
 
+func (err *ConsistencyError) previousVersion() int {
+	if err.EventIndex > 0 {
+		_, _, v := err.Events[err.EventIndex-1].Aggregate()
+		return v
+	}
+	return currentVersion(err.Aggregate)
+}
+
 // IsConsistencyError implements error.Is.
 func (err *ConsistencyError) IsConsistencyError() bool {
 	return true
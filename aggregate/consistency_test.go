@@ -197,6 +197,75 @@ func TestValidate_version(t *testing.T) {
 	}
 }
 
+func TestValidate_allowSkippedVersions(t *testing.T) {
+	aggregateID := uuid.New()
+	now := xtime.Now()
+
+	newEvent := func(v int, offset time.Duration) event.Event {
+		return event.New[any]("foo", test.FooEventData{A: "foo"}, event.Aggregate(aggregateID, "foo", v), event.Time(now.Add(offset)))
+	}
+
+	tests := []struct {
+		name      string
+		events    []event.Event
+		wantIndex int
+	}{
+		{
+			name:      "gaps",
+			events:    []event.Event{newEvent(2, 0), newEvent(3, time.Nanosecond), newEvent(7, time.Millisecond)},
+			wantIndex: -1,
+		},
+		{
+			name:      "version too low",
+			events:    []event.Event{newEvent(0, 0), newEvent(2, time.Nanosecond)},
+			wantIndex: 0,
+		},
+		{
+			name:      "duplicate version",
+			events:    []event.Event{newEvent(1, 0), newEvent(3, time.Nanosecond), newEvent(3, time.Millisecond)},
+			wantIndex: 2,
+		},
+		{
+			name:      "decreasing version",
+			events:    []event.Event{newEvent(1, 0), newEvent(5, time.Nanosecond), newEvent(4, time.Millisecond)},
+			wantIndex: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := aggregate.New("foo", aggregateID)
+
+			err := aggregate.ValidateConsistency(a, tt.events, aggregate.AllowSkippedVersions(true))
+
+			if tt.wantIndex < 0 {
+				if err != nil {
+					t.Fatalf("expected validation to succeed; got %v", err)
+				}
+				return
+			}
+
+			var cerr *aggregate.ConsistencyError
+			if !errors.As(err, &cerr) {
+				t.Fatalf("ValidateConsistency() should return a %T; got %T", cerr, err)
+			}
+
+			if cerr.Kind != aggregate.InconsistentVersion {
+				t.Errorf("Kind should be %v; got %v", aggregate.InconsistentVersion, cerr.Kind)
+			}
+
+			if cerr.EventIndex != tt.wantIndex {
+				t.Errorf("EventIndex should be %d; got %d", tt.wantIndex, cerr.EventIndex)
+			}
+		})
+	}
+
+	gaps := []event.Event{newEvent(1, 0), newEvent(3, time.Nanosecond)}
+	if err := aggregate.ValidateConsistency(aggregate.New("foo", aggregateID), gaps); err == nil {
+		t.Fatalf("ValidateConsistency() should fail for version gaps by default")
+	}
+}
+
 func TestValidate_time(t *testing.T) {
 	id := uuid.New()
 	now := xtime.Now()
@@ -18,9 +18,10 @@ type History interface {
 
 // ApplyHistory applies an event stream to an aggregate to reconstruct its state.
 // If the aggregate implements Committer, a.RecordChange(events) and a.Commit()
-// are called before returning.
-func ApplyHistory[Events ~[]event.Of[any]](a Aggregate, events Events) error {
-	if err := ValidateConsistency(a, events); err != nil {
+// are called before returning. The consistency of the events is validated
+// using ValidateConsistency, which is configured by the given opts.
+func ApplyHistory[Events ~[]event.Of[any]](a Aggregate, events Events, opts ...ConsistencyOption) error {
+	if err := ValidateConsistency(a, events, opts...); err != nil {
 		return fmt.Errorf("validate consistency: %w", err)
 	}
 
@@ -71,6 +71,23 @@ type Repository interface {
 	Delete(ctx context.Context, a Aggregate) error
 }
 
+// An ExistenceChecker is a Repository that can check whether an aggregate
+// exists without fetching its events. The Repository that is returned by
+// repository.New implements ExistenceChecker. ExistenceChecker is not part of
+// the Repository interface, so that existing implementations of Repository
+// stay valid:
+//
+//	var repo aggregate.Repository
+//	if checker, ok := repo.(aggregate.ExistenceChecker); ok {
+//		exists, err := checker.Exists(context.TODO(), "foo", id)
+//		// handle err
+//	}
+type ExistenceChecker interface {
+	// Exists returns whether the event store contains events of the aggregate
+	// with the given name and id, without fetching its complete event stream.
+	Exists(ctx context.Context, name string, id uuid.UUID) (bool, error)
+}
+
 // TypedAggregate is a type constraint for aggregates of a TypedRepository.
 type TypedAggregate interface {
 	model.Model[uuid.UUID]
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/google/uuid"
 	"github.com/modernice/goes/aggregate"
 	"github.com/modernice/goes/aggregate/query"
 	"github.com/modernice/goes/aggregate/snapshot"
@@ -36,6 +37,7 @@ type Repository struct {
 	afterInsert    []func(context.Context, aggregate.Aggregate) error
 	onFailedInsert []func(context.Context, aggregate.Aggregate, error) error
 	onDelete       []func(context.Context, aggregate.Aggregate) error
+	withDeleted    bool
 }
 
 // WithSnapshots returns an Option that add a Snapshot Store to a Repository.
@@ -65,6 +67,22 @@ func WithSnapshots(store snapshot.Store, s snapshot.Schedule) Option {
 	}
 }
 
+// UseSnapshots returns an Option that adds a Snapshot Store to a Repository and
+// makes the Repository save a Snapshot of an aggregate whenever the version of
+// the aggregate crosses a multiple of every. UseSnapshots is a shorthand for
+//
+//	repository.WithSnapshots(store, snapshot.Every(every))
+//
+// If every is 0 or negative, the Snapshot Store is only used to fetch
+// aggregates and no Snapshots are made.
+func UseSnapshots(store snapshot.Store, every int) Option {
+	var schedule snapshot.Schedule
+	if every > 0 {
+		schedule = snapshot.Every(every)
+	}
+	return WithSnapshots(store, schedule)
+}
+
 // ModifyQueries returns an Option that adds mods as Query modifiers to a
 // Repository. When the Repository builds a Query, it is passed to every
 // modifier before the event store is queried.
@@ -74,6 +92,16 @@ func ModifyQueries(mods ...func(ctx context.Context, q aggregate.Query, prev eve
 	}
 }
 
+// WithSoftDeleted returns an Option that specifies if Repository.Query should
+// return the Histories of soft-deleted aggregates. Soft-deleted aggregates are
+// excluded from query results by default. WithSoftDeleted does not affect
+// Fetch, which always fails with ErrDeleted for soft-deleted aggregates.
+func WithSoftDeleted(v bool) Option {
+	return func(r *Repository) {
+		r.withDeleted = v
+	}
+}
+
 // BeforeInsert returns an Option that adds fn as a hook to a Repository. fn is
 // called before the changes to an aggregate are inserted into the event store.
 func BeforeInsert(fn func(context.Context, aggregate.Aggregate) error) Option {
@@ -273,7 +301,9 @@ func (r *Repository) queryEvents(ctx context.Context, q equery.Query) ([]event.E
 
 // FetchVersion does the same as r.Fetch, but only fetches events up until the
 // given version v. If the event store has no event for the provided aggregate
-// with the requested version, ErrVersionNotFound is returned.
+// with the requested version, ErrVersionNotFound is returned. If the Repository
+// has a snapshot store, the aggregate is first restored from the latest
+// snapshot at or below version v.
 func (r *Repository) FetchVersion(ctx context.Context, a aggregate.Aggregate, v int) error {
 	if v < 0 {
 		v = 0
@@ -321,6 +351,19 @@ func (r *Repository) fetchVersion(ctx context.Context, a aggregate.Aggregate, v
 	return nil
 }
 
+// Exists returns whether the event store contains events of the aggregate with
+// the given name and id. Exists counts the events of the aggregate instead of
+// fetching them, so aggregates whose early events were deleted or pruned are
+// reported as existing, too. Exists also returns true for soft-deleted
+// aggregates. Exists implements aggregate.ExistenceChecker.
+func (r *Repository) Exists(ctx context.Context, name string, id uuid.UUID) (bool, error) {
+	count, err := r.store.Count(ctx, equery.New(equery.Aggregate(name, id)))
+	if err != nil {
+		return false, fmt.Errorf("count events: %w", err)
+	}
+	return count > 0, nil
+}
+
 // Delete deletes an aggregate by deleting its events from the event store.
 func (r *Repository) Delete(ctx context.Context, a aggregate.Aggregate) error {
 	id, name, _ := a.Aggregate()
@@ -368,8 +411,11 @@ func (r *Repository) Delete(ctx context.Context, a aggregate.Aggregate) error {
 }
 
 // Query queries the event store for events that match the given Query and
-// returns a stream of aggregate Histories and errors. Use the returned
-// Histories to build the current state of the queried aggregates:
+// returns a stream of aggregate Histories and errors. The events are sorted by
+// aggregate, which allows the Histories to be built without buffering the
+// events of all aggregates. Soft-deleted aggregates are excluded unless the
+// Repository was created with WithSoftDeleted. Use the returned Histories to
+// build the current state of the queried aggregates:
 //
 //	var r *Repository
 //	str, errs, err := r.Query(context.TODO(), query.New(...))
@@ -392,8 +438,11 @@ func (r *Repository) Query(ctx context.Context, q aggregate.Query) (<-chan aggre
 		return nil, nil, fmt.Errorf("make query options: %w", err)
 	}
 
-	events, errs, err := r.store.Query(ctx, eq)
+	qctx, cancel := context.WithCancel(ctx)
+
+	events, errs, err := r.store.Query(qctx, eq)
 	if err != nil {
+		cancel()
 		return nil, nil, fmt.Errorf("query events: %w", err)
 	}
 
@@ -403,6 +452,8 @@ func (r *Repository) Query(ctx context.Context, q aggregate.Query) (<-chan aggre
 		stream.Errors(errs),
 		stream.Grouped(true),
 		stream.Sorted(true),
+		stream.WithSoftDeleted(r.withDeleted),
+		stream.CancelUpstream(cancel),
 	)
 
 	return out, outErrors, nil
@@ -19,6 +19,7 @@ import (
 	"github.com/modernice/goes/aggregate/test"
 	"github.com/modernice/goes/event"
 	"github.com/modernice/goes/event/eventstore"
+	equery "github.com/modernice/goes/event/query"
 	"github.com/modernice/goes/event/query/version"
 	etest "github.com/modernice/goes/event/test"
 	"github.com/modernice/goes/helper/pick"
@@ -29,6 +30,7 @@ import (
 
 var (
 	_ aggregate.Repository                       = (*repository.Repository)(nil)
+	_ aggregate.ExistenceChecker                 = (*repository.Repository)(nil)
 	_ aggregate.TypedRepository[*aggregate.Base] = (*repository.TypedRepository[*aggregate.Base])(nil)
 )
 
@@ -114,6 +116,55 @@ func TestRepository_Save_Snapshot(t *testing.T) {
 	}
 }
 
+func TestUseSnapshots(t *testing.T) {
+	snapstore := snapshot.NewStore()
+	r := repository.New(eventstore.New(), repository.UseSnapshots(snapstore, 3))
+
+	foo := &mockAggregate{Base: aggregate.New("foo", uuid.New())}
+
+	tests := []struct {
+		events int
+		want   []int
+	}{
+		{events: 2, want: nil},
+		{events: 2, want: []int{4}},
+		{events: 1, want: []int{4}},
+		{events: 1, want: []int{4, 6}},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < tt.events; i++ {
+			aggregate.Next(foo, "foo", etest.FooEventData{})
+		}
+
+		if err := r.Save(context.Background(), foo); err != nil {
+			t.Fatalf("Save() failed with %q", err)
+		}
+
+		str, errs, err := snapstore.Query(context.Background(), squery.New(
+			squery.ID(foo.AggregateID()),
+			squery.SortBy(aggregate.SortVersion, aggregate.SortAsc),
+		))
+		if err != nil {
+			t.Fatalf("Query() failed with %q", err)
+		}
+
+		snaps, err := streams.Drain(context.Background(), str, errs)
+		if err != nil {
+			t.Fatalf("Drain() failed with %q", err)
+		}
+
+		var versions []int
+		for _, snap := range snaps {
+			versions = append(versions, snap.AggregateVersion())
+		}
+
+		if !reflect.DeepEqual(versions, tt.want) {
+			t.Fatalf("at version %d, snapshots should exist for versions %v; got %v", foo.AggregateVersion(), tt.want, versions)
+		}
+	}
+}
+
 func TestRepository_Fetch(t *testing.T) {
 	aggregateID := uuid.New()
 
@@ -206,6 +257,83 @@ func TestRepository_FetchVersion(t *testing.T) {
 	}
 }
 
+func TestRepository_FetchVersion_intermediateState(t *testing.T) {
+	aggregateID := uuid.New()
+
+	org := test.NewFoo(aggregateID)
+	for _, a := range []string{"v1", "v2", "v3", "v4", "v5"} {
+		aggregate.Next(org, "foo", etest.FooEventData{A: a})
+	}
+
+	r := repository.New(eventstore.New())
+	if err := r.Save(context.Background(), org); err != nil {
+		t.Fatalf("Save() failed with %q", err)
+	}
+
+	newFoo := func(state *string) *test.Foo {
+		return test.NewFoo(aggregateID, test.ApplyEventFunc("foo", func(evt event.Event) {
+			*state = evt.Data().(etest.FooEventData).A
+		}))
+	}
+
+	var intermediate string
+	if err := r.FetchVersion(context.Background(), newFoo(&intermediate), 3); err != nil {
+		t.Fatalf("FetchVersion() failed with %q", err)
+	}
+
+	var latest string
+	if err := r.Fetch(context.Background(), newFoo(&latest)); err != nil {
+		t.Fatalf("Fetch() failed with %q", err)
+	}
+
+	if intermediate != "v3" {
+		t.Errorf("state at version %d should be %q; got %q", 3, "v3", intermediate)
+	}
+
+	if latest != "v5" {
+		t.Errorf("latest state should be %q; got %q", "v5", latest)
+	}
+}
+
+func TestRepository_FetchVersion_snapshot(t *testing.T) {
+	aggregateID := uuid.New()
+	estore := eventstore.New()
+	snapstore := snapshot.NewStore()
+	r := repository.New(estore, repository.WithSnapshots(snapstore, nil))
+
+	events := xevent.Make("foo", etest.FooEventData{}, 5, xevent.ForAggregate(aggregate.New("foo", aggregateID)))
+	if err := estore.Insert(context.Background(), events...); err != nil {
+		t.Fatalf("Insert() failed with %q", err)
+	}
+
+	for _, v := range []int{2, 4} {
+		a := &mockAggregate{
+			Base:      aggregate.New("foo", aggregateID, aggregate.Version(v)),
+			mockState: mockState{A: fmt.Sprintf("snapshot %d", v)},
+		}
+		snap, err := snapshot.New(a)
+		if err != nil {
+			t.Fatalf("snapshot.New() failed with %q", err)
+		}
+		if err := snapstore.Save(context.Background(), snap); err != nil {
+			t.Fatalf("Save() failed with %q", err)
+		}
+	}
+
+	foo := &mockAggregate{Base: aggregate.New("foo", aggregateID)}
+	if err := r.FetchVersion(context.Background(), foo, 3); err != nil {
+		t.Fatalf("FetchVersion() failed with %q", err)
+	}
+
+	if foo.AggregateVersion() != 3 {
+		t.Errorf("aggregate should have version %d; got %d", 3, foo.AggregateVersion())
+	}
+
+	if foo.A != "snapshot 2" {
+		t.Errorf("aggregate should be built from the latest snapshot at or below version %d; got state %q", 3, foo.A)
+	}
+}
+
 func TestRepository_FetchVersion_zeroOrNegative(t *testing.T) {
 	aggregateName := "foo"
 	aggregateID := uuid.New()
@@ -317,6 +445,86 @@ func TestRepository_Delete(t *testing.T) {
 	}
 }
 
+func TestRepository_Exists(t *testing.T) {
+	foo := test.NewFoo(uuid.New())
+	aggregate.Next(foo, "foo", etest.FooEventData{A: "foo"})
+	aggregate.Next(foo, "foo", etest.FooEventData{A: "foo"})
+
+	r := repository.New(eventstore.New())
+	if err := r.Save(context.Background(), foo); err != nil {
+		t.Fatalf("Save() failed with %q", err)
+	}
+
+	exists, err := r.Exists(context.Background(), "foo", foo.AggregateID())
+	if err != nil {
+		t.Fatalf("Exists() failed with %q", err)
+	}
+
+	if !exists {
+		t.Errorf("Exists() should return %v for an existing aggregate; got %v", true, exists)
+	}
+
+	exists, err = r.Exists(context.Background(), "foo", uuid.New())
+	if err != nil {
+		t.Fatalf("Exists() failed with %q", err)
+	}
+
+	if exists {
+		t.Errorf("Exists() should return %v for a non-existing aggregate; got %v", false, exists)
+	}
+
+	exists, err = r.Exists(context.Background(), "bar", foo.AggregateID())
+	if err != nil {
+		t.Fatalf("Exists() failed with %q", err)
+	}
+
+	if exists {
+		t.Errorf("Exists() should return %v for an aggregate with another name; got %v", false, exists)
+	}
+}
+
+func TestRepository_Exists_withoutFirstEvent(t *testing.T) {
+	foo := test.NewFoo(uuid.New())
+	aggregate.Next(foo, "foo", etest.FooEventData{A: "foo"})
+	aggregate.Next(foo, "foo", etest.FooEventData{A: "foo"})
+
+	store := eventstore.New()
+	r := repository.New(store)
+	if err := r.Save(context.Background(), foo); err != nil {
+		t.Fatalf("Save() failed with %q", err)
+	}
+
+	if _, err := store.DeleteQuery(context.Background(), equery.New(
+		equery.Aggregate("foo", foo.AggregateID()),
+		equery.AggregateVersion(version.Exact(1)),
+	)); err != nil {
+		t.Fatalf("DeleteQuery() failed with %q", err)
+	}
+
+	exists, err := r.Exists(context.Background(), "foo", foo.AggregateID())
+	if err != nil {
+		t.Fatalf("Exists() failed with %q", err)
+	}
+
+	if !exists {
+		t.Errorf("Exists() should return %v for an aggregate without its first event; got %v", true, exists)
+	}
+}
+
+func TestRepository_Exists_error(t *testing.T) {
+	mockError := errors.New("mock error")
+	r := repository.New(failingCountStore{Store: eventstore.New(), err: mockError})
+
+	exists, err := r.Exists(context.Background(), "foo", uuid.New())
+	if !errors.Is(err, mockError) {
+		t.Fatalf("Exists() should fail with %q; got %v", mockError, err)
+	}
+
+	if exists {
+		t.Errorf("Exists() should return %v on error; got %v", false, exists)
+	}
+}
+
 func TestRepository_Query_name(t *testing.T) {
 	foos, _ := xaggregate.Make(3, xaggregate.Name("foo"))
 	bars, _ := xaggregate.Make(3, xaggregate.Name("bar"))
@@ -820,3 +1028,28 @@ func (a *mockAggregate) MarshalSnapshot() ([]byte, error) {
 func (a *mockAggregate) UnmarshalSnapshot(p []byte) error {
 	return gob.NewDecoder(bytes.NewReader(p)).Decode(&a.mockState)
 }
+
+// failingCountStore fails every Count with err.
+type failingCountStore struct {
+	event.Store
+	err error
+}
+
+func (s failingCountStore) Count(context.Context, event.Query) (int, error) {
+	return 0, s.err
+}
+
+// failingQueryStore closes the event stream of a query before it reports err.
+type failingQueryStore struct {
+	event.Store
+	err error
+}
+
+func (s failingQueryStore) Query(context.Context, event.Query) (<-chan event.Event, <-chan error, error) {
+	str := make(chan event.Event)
+	close(str)
+	errs := make(chan error, 1)
+	errs <- s.err
+	close(errs)
+	return str, errs, nil
+}
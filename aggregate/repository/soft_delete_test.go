@@ -105,6 +105,39 @@ func TestRepository_Query_SoftDelete(t *testing.T) {
 	}
 }
 
+func TestRepository_Query_WithSoftDeleted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	estore := eventstore.New()
+	r := repository.New(estore, repository.WithSoftDeleted(true))
+
+	foo := test.NewFoo(uuid.New())
+	bar := test.NewFoo(uuid.New())
+
+	aggregate.Next(foo, "foo", etest.FooEventData{}).Any()
+
+	aggregate.Next(bar, "foo", etest.FooEventData{}).Any()
+	aggregate.Next(bar, "soft_deleted", softDeletedEvent{}).Any()
+
+	r.Save(ctx, foo)
+	r.Save(ctx, bar)
+
+	str, errs, err := r.Query(ctx, query.New())
+	if err != nil {
+		t.Fatalf("Query() failed with %v", err)
+	}
+
+	histories, err := streams.Drain(ctx, str, errs)
+	if err != nil {
+		t.Fatalf("drain histories: %v", err)
+	}
+
+	if len(histories) != 2 {
+		t.Fatalf("soft-deleted aggregates should be returned; got %d aggregates", len(histories))
+	}
+}
+
 type softDeletedEvent struct{}
 
 func (softDeletedEvent) SoftDelete() bool { return true }
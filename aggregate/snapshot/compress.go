@@ -0,0 +1,161 @@
+package snapshot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// compressionHeader prefixes the raw state of compressed snapshots. The header
+// is followed by the name of the Compressor, a zero byte and the compressed
+// state.
+var compressionHeader = []byte("\x00goes:compressed:")
+
+// ErrCompressorNotRegistered is returned by New if a snapshot is compressed
+// or decompressed by a Compressor that has not been registered with
+// RegisterCompressor.
+var ErrCompressorNotRegistered = errors.New("compressor not registered")
+
+var (
+	compressorsMux sync.RWMutex
+	compressors    = make(map[string]Compressor)
+)
+
+// A Compressor compresses and decompresses the encoded state of snapshots.
+// Compressors are identified by their name, which is stored alongside the
+// compressed state so that snapshots can be decompressed transparently.
+type Compressor interface {
+	// Name returns the name of the Compressor.
+	Name() string
+
+	// Compress compresses the given bytes.
+	Compress([]byte) ([]byte, error)
+
+	// Decompress decompresses the given bytes.
+	Decompress([]byte) ([]byte, error)
+}
+
+func init() {
+	RegisterCompressor(Gzip())
+}
+
+// RegisterCompressor registers a Compressor so that snapshots that were
+// compressed by it can be decompressed. Snapshots that are loaded from a Store
+// can only be decompressed if their Compressor has been registered. The Gzip
+// Compressor is registered by default.
+func RegisterCompressor(c Compressor) {
+	compressorsMux.Lock()
+	defer compressorsMux.Unlock()
+	compressors[c.Name()] = c
+}
+
+// Compressed returns an Option that compresses the state of a snapshot using
+// the given Compressor. The Compressor must be registered once with
+// RegisterCompressor, e.g. in an init function, so that the snapshot can be
+// decompressed when it is loaded from a Store. New fails with an error that
+// unwraps to ErrCompressorNotRegistered if it is not.
+//
+// The State of a compressed snapshot still returns the decompressed state. Use
+// Raw to get the compressed bytes that should be persisted by a Store.
+func Compressed(c Compressor) Option {
+	return func(s *snapshot) {
+		s.compressor = c
+	}
+}
+
+// Raw returns the encoded state of the given Snapshot as it should be persisted
+// by a Store. If the Snapshot is compressed, Raw returns the compressed state,
+// prefixed by a header that identifies the used Compressor. Otherwise Raw
+// returns s.State().
+func Raw(s Snapshot) []byte {
+	if r, ok := s.(interface{ Raw() []byte }); ok {
+		return r.Raw()
+	}
+	return s.State()
+}
+
+func compress(c Compressor, b []byte) ([]byte, error) {
+	name := c.Name()
+	if _, ok := compressor(name); !ok {
+		return nil, fmt.Errorf("%w [name=%v]", ErrCompressorNotRegistered, name)
+	}
+
+	compressed, err := c.Compress(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	raw := make([]byte, 0, len(compressionHeader)+len(name)+1+len(compressed))
+	raw = append(raw, compressionHeader...)
+	raw = append(raw, name...)
+	raw = append(raw, 0)
+	return append(raw, compressed...), nil
+}
+
+// decompress decompresses the given raw state. If b is not compressed,
+// decompress returns b and a nil Compressor.
+func decompress(b []byte) ([]byte, Compressor, error) {
+	if !bytes.HasPrefix(b, compressionHeader) {
+		return b, nil, nil
+	}
+
+	rest := b[len(compressionHeader):]
+	i := bytes.IndexByte(rest, 0)
+	if i < 0 {
+		return nil, nil, fmt.Errorf("missing compressor name")
+	}
+	name := string(rest[:i])
+
+	c, ok := compressor(name)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w [name=%v]", ErrCompressorNotRegistered, name)
+	}
+
+	state, err := c.Decompress(rest[i+1:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return state, c, nil
+}
+
+func compressor(name string) (Compressor, bool) {
+	compressorsMux.RLock()
+	defer compressorsMux.RUnlock()
+	c, ok := compressors[name]
+	return c, ok
+}
+
+type gzipCompressor struct{}
+
+// Gzip returns a Compressor that uses compress/gzip.
+func Gzip() Compressor {
+	return gzipCompressor{}
+}
+
+func (gzipCompressor) Name() string {
+	return "gzip"
+}
+
+func (gzipCompressor) Compress(b []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(b); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gzipCompressor) Decompress(b []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
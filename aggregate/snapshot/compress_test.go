@@ -0,0 +1,135 @@
+package snapshot_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/aggregate/snapshot"
+)
+
+func TestCompressed(t *testing.T) {
+	a := &mockSnapshotter{
+		Base: aggregate.New("foo", uuid.New()),
+		mockState: mockState{
+			A: true,
+			B: -10,
+			C: "foo",
+		},
+	}
+
+	b, err := snapshot.Marshal(a)
+	if err != nil {
+		t.Fatalf("Marshal shouldn't fail; failed with %q", err)
+	}
+
+	snap, err := snapshot.New(a, snapshot.Compressed(snapshot.Gzip()))
+	if err != nil {
+		t.Fatalf("New shouldn't fail; failed with %q", err)
+	}
+
+	if !bytes.Equal(snap.State(), b) {
+		t.Errorf("State should return the decompressed state %v; got %v", b, snap.State())
+	}
+
+	raw := snapshot.Raw(snap)
+	if bytes.Equal(raw, b) {
+		t.Fatalf("Raw should return the compressed state; got the uncompressed state")
+	}
+
+	loaded, err := snapshot.New(aggregate.New("foo", a.AggregateID()), snapshot.Data(raw))
+	if err != nil {
+		t.Fatalf("New shouldn't fail; failed with %q", err)
+	}
+
+	if !bytes.Equal(loaded.State(), b) {
+		t.Errorf("State should return the decompressed state %v; got %v", b, loaded.State())
+	}
+
+	unmarshaled := &mockSnapshotter{Base: aggregate.New("foo", uuid.New())}
+	if err := snapshot.Unmarshal(loaded, unmarshaled); err != nil {
+		t.Fatalf("Unmarshal shouldn't fail; failed with %q", err)
+	}
+
+	if unmarshaled.mockState != a.mockState {
+		t.Errorf("unmarshaled state differs from original. want=%v got=%v", a.mockState, unmarshaled.mockState)
+	}
+}
+
+func TestCompressed_uncompressedData(t *testing.T) {
+	a := &mockSnapshotter{
+		Base: aggregate.New("foo", uuid.New()),
+		mockState: mockState{
+			A: true,
+			B: -10,
+			C: "foo",
+		},
+	}
+
+	uncompressed, err := snapshot.New(a)
+	if err != nil {
+		t.Fatalf("New shouldn't fail; failed with %q", err)
+	}
+
+	raw := snapshot.Raw(uncompressed)
+	if !bytes.Equal(raw, uncompressed.State()) {
+		t.Fatalf("Raw of an uncompressed Snapshot should return its State %v; got %v", uncompressed.State(), raw)
+	}
+
+	loaded, err := snapshot.New(
+		aggregate.New("foo", a.AggregateID()),
+		snapshot.Data(raw),
+		snapshot.Compressed(snapshot.Gzip()),
+	)
+	if err != nil {
+		t.Fatalf("New shouldn't fail; failed with %q", err)
+	}
+
+	unmarshaled := &mockSnapshotter{Base: aggregate.New("foo", uuid.New())}
+	if err := snapshot.Unmarshal(loaded, unmarshaled); err != nil {
+		t.Fatalf("Unmarshal shouldn't fail; failed with %q", err)
+	}
+
+	if unmarshaled.mockState != a.mockState {
+		t.Errorf("unmarshaled state differs from original. want=%v got=%v", a.mockState, unmarshaled.mockState)
+	}
+}
+
+func TestCompressed_notRegistered(t *testing.T) {
+	a := &mockSnapshotter{
+		Base:      aggregate.New("foo", uuid.New()),
+		mockState: mockState{C: "foo"},
+	}
+
+	c := mockCompressor{name: "mock-" + uuid.NewString()}
+
+	if _, err := snapshot.New(a, snapshot.Compressed(c)); !errors.Is(err, snapshot.ErrCompressorNotRegistered) {
+		t.Fatalf("New should fail with %q for an unregistered Compressor; got %v", snapshot.ErrCompressorNotRegistered, err)
+	}
+
+	snapshot.RegisterCompressor(c)
+
+	snap, err := snapshot.New(a, snapshot.Compressed(c))
+	if err != nil {
+		t.Fatalf("New shouldn't fail; failed with %q", err)
+	}
+
+	loaded, err := snapshot.New(aggregate.New("foo", a.AggregateID()), snapshot.Data(snapshot.Raw(snap)))
+	if err != nil {
+		t.Fatalf("New shouldn't fail; failed with %q", err)
+	}
+
+	if !bytes.Equal(loaded.State(), snap.State()) {
+		t.Errorf("State should return the decompressed state %v; got %v", snap.State(), loaded.State())
+	}
+}
+
+type mockCompressor struct{ name string }
+
+func (c mockCompressor) Name() string { return c.name }
+
+func (mockCompressor) Compress(b []byte) ([]byte, error) { return b, nil }
+
+func (mockCompressor) Decompress(b []byte) ([]byte, error) { return b, nil }
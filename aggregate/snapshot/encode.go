@@ -80,6 +80,7 @@ type Target interface {
 // encoding.BinaryMarshaler, a.UnmarshalBinary() is returned and if a implements
 // encoding.TextUnmarshaler, a.UnmarshalText() is returned. If a implements none
 // of these interfaces, encoding/gob is used to unmarshal the snapshot.
+// Compressed snapshots are decompressed transparently.
 func Unmarshal(s Snapshot, a Target) error {
 	a.SetVersion(s.AggregateVersion())
 
@@ -3,6 +3,7 @@ package snapshot
 import (
 	"context"
 	"errors"
+	"sort"
 	"sync"
 	stdtime "time"
 
@@ -97,7 +98,7 @@ func (s *store) Query(ctx context.Context, q Query) (<-chan Snapshot, <-chan err
 			}
 		}
 	}
-	snaps = SortMulti(snaps, q.Sortings()...)
+	snaps = Paginate(q, SortMulti(snaps, q.Sortings()...))
 
 	out, outErrs := make(chan Snapshot), make(chan error)
 
@@ -116,6 +117,22 @@ func (s *store) Query(ctx context.Context, q Query) (<-chan Snapshot, <-chan err
 	return out, outErrs, nil
 }
 
+func (s *store) Count(_ context.Context, q Query) (int, error) {
+	s.Lock()
+	defer s.Unlock()
+	var count int
+	for _, idsnaps := range s.snaps {
+		for _, vsnaps := range idsnaps {
+			for _, snap := range vsnaps {
+				if Test(q, snap) {
+					count++
+				}
+			}
+		}
+	}
+	return count, nil
+}
+
 func (s *store) Delete(_ context.Context, snap Snapshot) error {
 	snaps := s.get(snap.AggregateName(), snap.AggregateID())
 	s.Lock()
@@ -124,6 +141,45 @@ func (s *store) Delete(_ context.Context, snap Snapshot) error {
 	return nil
 }
 
+func (s *store) Prune(_ context.Context, name string, id uuid.UUID, keep int) (int, error) {
+	if keep < 0 {
+		return 0, ErrNegativeKeep
+	}
+	s.Lock()
+	defer s.Unlock()
+	return s.prune(s.snaps[name][id], keep), nil
+}
+
+func (s *store) PruneAll(_ context.Context, keep int) (int, error) {
+	if keep < 0 {
+		return 0, ErrNegativeKeep
+	}
+	s.Lock()
+	defer s.Unlock()
+	var deleted int
+	for _, idsnaps := range s.snaps {
+		for _, vsnaps := range idsnaps {
+			deleted += s.prune(vsnaps, keep)
+		}
+	}
+	return deleted, nil
+}
+
+func (s *store) prune(snaps map[int]Snapshot, keep int) int {
+	if len(snaps) <= keep {
+		return 0
+	}
+	versions := make([]int, 0, len(snaps))
+	for v := range snaps {
+		versions = append(versions, v)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+	for _, v := range versions[keep:] {
+		delete(snaps, v)
+	}
+	return len(versions) - keep
+}
+
 func (s *store) get(name string, id uuid.UUID) map[int]Snapshot {
 	s.Lock()
 	defer s.Unlock()
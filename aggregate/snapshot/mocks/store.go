@@ -10,8 +10,10 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
+	aggregate "github.com/modernice/goes/aggregate"
 	snapshot "github.com/modernice/goes/aggregate/snapshot"
 	time "github.com/modernice/goes/event/query/time"
+	version "github.com/modernice/goes/event/query/version"
 )
 
 // MockStore is a mock of Store interface.
@@ -37,6 +39,21 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// Count mocks base method.
+func (m *MockStore) Count(arg0 context.Context, arg1 snapshot.Query) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockStoreMockRecorder) Count(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockStore)(nil).Count), arg0, arg1)
+}
+
 // Delete mocks base method.
 func (m *MockStore) Delete(arg0 context.Context, arg1 snapshot.Snapshot) error {
 	m.ctrl.T.Helper()
@@ -81,6 +98,36 @@ func (mr *MockStoreMockRecorder) Limit(arg0, arg1, arg2, arg3 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Limit", reflect.TypeOf((*MockStore)(nil).Limit), arg0, arg1, arg2, arg3)
 }
 
+// Prune mocks base method.
+func (m *MockStore) Prune(ctx context.Context, name string, id uuid.UUID, keep int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prune", ctx, name, id, keep)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Prune indicates an expected call of Prune.
+func (mr *MockStoreMockRecorder) Prune(ctx, name, id, keep interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prune", reflect.TypeOf((*MockStore)(nil).Prune), ctx, name, id, keep)
+}
+
+// PruneAll mocks base method.
+func (m *MockStore) PruneAll(ctx context.Context, keep int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneAll", ctx, keep)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneAll indicates an expected call of PruneAll.
+func (mr *MockStoreMockRecorder) PruneAll(ctx, keep interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneAll", reflect.TypeOf((*MockStore)(nil).PruneAll), ctx, keep)
+}
+
 // Query mocks base method.
 func (m *MockStore) Query(arg0 context.Context, arg1 snapshot.Query) (<-chan snapshot.Snapshot, <-chan error, error) {
 	m.ctrl.T.Helper()
@@ -149,6 +196,76 @@ func (m *MockQuery) EXPECT() *MockQueryMockRecorder {
 	return m.recorder
 }
 
+// IDs mocks base method.
+func (m *MockQuery) IDs() []uuid.UUID {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IDs")
+	ret0, _ := ret[0].([]uuid.UUID)
+	return ret0
+}
+
+// IDs indicates an expected call of IDs.
+func (mr *MockQueryMockRecorder) IDs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IDs", reflect.TypeOf((*MockQuery)(nil).IDs))
+}
+
+// Limit mocks base method.
+func (m *MockQuery) Limit() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Limit")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// Limit indicates an expected call of Limit.
+func (mr *MockQueryMockRecorder) Limit() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Limit", reflect.TypeOf((*MockQuery)(nil).Limit))
+}
+
+// Names mocks base method.
+func (m *MockQuery) Names() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Names")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Names indicates an expected call of Names.
+func (mr *MockQueryMockRecorder) Names() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Names", reflect.TypeOf((*MockQuery)(nil).Names))
+}
+
+// Offset mocks base method.
+func (m *MockQuery) Offset() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Offset")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// Offset indicates an expected call of Offset.
+func (mr *MockQueryMockRecorder) Offset() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Offset", reflect.TypeOf((*MockQuery)(nil).Offset))
+}
+
+// Sortings mocks base method.
+func (m *MockQuery) Sortings() []aggregate.SortOptions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sortings")
+	ret0, _ := ret[0].([]aggregate.SortOptions)
+	return ret0
+}
+
+// Sortings indicates an expected call of Sortings.
+func (mr *MockQueryMockRecorder) Sortings() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sortings", reflect.TypeOf((*MockQuery)(nil).Sortings))
+}
+
 // Times mocks base method.
 func (m *MockQuery) Times() time.Constraints {
 	m.ctrl.T.Helper()
@@ -162,3 +279,17 @@ func (mr *MockQueryMockRecorder) Times() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Times", reflect.TypeOf((*MockQuery)(nil).Times))
 }
+
+// Versions mocks base method.
+func (m *MockQuery) Versions() version.Constraints {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Versions")
+	ret0, _ := ret[0].(version.Constraints)
+	return ret0
+}
+
+// Versions indicates an expected call of Versions.
+func (mr *MockQueryMockRecorder) Versions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Versions", reflect.TypeOf((*MockQuery)(nil).Versions))
+}
@@ -12,7 +12,9 @@ import (
 type Query struct {
 	query.Query
 
-	times time.Constraints
+	times  time.Constraints
+	limit  int
+	offset int
 }
 
 type Option func(*builder)
@@ -66,6 +68,17 @@ func SortByMulti(sorts ...aggregate.SortOptions) Option {
 	}
 }
 
+// Paginate returns an Option that paginates the result of a Query. After the
+// Snapshots have been filtered and sorted, the first offset Snapshots are
+// skipped and at most limit Snapshots are returned. A limit of 0 means no
+// limit.
+func Paginate(limit, offset int) Option {
+	return func(b *builder) {
+		b.limit = limit
+		b.offset = offset
+	}
+}
+
 // New returns a Query from opts.
 func New(opts ...Option) Query {
 	var b builder
@@ -77,6 +90,17 @@ func (q Query) Times() time.Constraints {
 	return q.times
 }
 
+// Limit returns the maximum number of Snapshots the Query returns. A limit of
+// 0 means no limit.
+func (q Query) Limit() int {
+	return q.limit
+}
+
+// Offset returns the number of Snapshots the Query skips.
+func (q Query) Offset() int {
+	return q.offset
+}
+
 func (b *builder) build(opts ...Option) Query {
 	for _, opt := range opts {
 		opt(b)
@@ -0,0 +1,55 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/event/query"
+	"github.com/modernice/goes/event/query/version"
+	"github.com/modernice/goes/helper/streams"
+)
+
+// Rebuild rebuilds the state of the aggregate a from the latest snapshot of
+// the aggregate and the events that were published after the snapshot was
+// taken.
+//
+// If a implements Target and snaps contains a snapshot of the aggregate, the
+// latest snapshot is unmarshaled into a (see Unmarshal) and only the events
+// with a version higher than the version of the snapshot are fetched from the
+// event store and applied to a. If a does not implement Target, or if no
+// snapshot of the aggregate can be loaded from snaps, all events of the
+// aggregate are applied.
+func Rebuild(ctx context.Context, a aggregate.Aggregate, snaps Store, events event.Store) error {
+	if t, ok := a.(Target); ok {
+		id, name, _ := a.Aggregate()
+		if snap, err := snaps.Latest(ctx, name, id); err == nil && snap != nil {
+			if err := Unmarshal(snap, t); err != nil {
+				return fmt.Errorf("unmarshal snapshot: %w", err)
+			}
+		}
+	}
+
+	id, name, _ := a.Aggregate()
+	str, errs, err := events.Query(ctx, query.New(
+		query.AggregateName(name),
+		query.AggregateID(id),
+		query.AggregateVersion(version.Min(aggregate.UncommittedVersion(a)+1)),
+		query.SortBy(event.SortAggregateVersion, event.SortAsc),
+	))
+	if err != nil {
+		return fmt.Errorf("query events: %w", err)
+	}
+
+	history, err := streams.Drain(ctx, str, errs)
+	if err != nil {
+		return fmt.Errorf("drain events: %w", err)
+	}
+
+	if err := aggregate.ApplyHistory(a, history); err != nil {
+		return fmt.Errorf("apply history: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package snapshot_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/aggregate/snapshot"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/goes/event/test"
+	"github.com/modernice/goes/internal/xevent"
+)
+
+type rebuildAggregate struct {
+	*mockSnapshotter
+
+	applied []event.Event
+}
+
+func newRebuildAggregate(id uuid.UUID) *rebuildAggregate {
+	return &rebuildAggregate{mockSnapshotter: &mockSnapshotter{Base: aggregate.New("foo", id)}}
+}
+
+func (a *rebuildAggregate) ApplyEvent(evt event.Event) {
+	a.applied = append(a.applied, evt)
+}
+
+func TestRebuild(t *testing.T) {
+	ctx := context.Background()
+	estore := eventstore.New()
+	sstore := snapshot.NewStore()
+
+	id := uuid.New()
+	events := xevent.Make("foo", test.FooEventData{}, 10, xevent.ForAggregate(aggregate.New("foo", id)))
+	if err := estore.Insert(ctx, events...); err != nil {
+		t.Fatalf("insert events: %v", err)
+	}
+
+	a := newRebuildAggregate(id)
+	a.mockState = mockState{A: true, B: 6, C: "foo"}
+	a.SetVersion(6)
+
+	snap, err := snapshot.New(a)
+	if err != nil {
+		t.Fatalf("New() failed with %q", err)
+	}
+
+	if err := sstore.Save(ctx, snap); err != nil {
+		t.Fatalf("save snapshot: %v", err)
+	}
+
+	rebuilt := newRebuildAggregate(id)
+	if err := snapshot.Rebuild(ctx, rebuilt, sstore, estore); err != nil {
+		t.Fatalf("Rebuild() failed with %q", err)
+	}
+
+	if rebuilt.mockState != a.mockState {
+		t.Errorf("snapshot state should be unmarshaled. want=%v got=%v", a.mockState, rebuilt.mockState)
+	}
+
+	test.AssertEqualEvents(t, events[6:], rebuilt.applied)
+
+	if v := rebuilt.AggregateVersion(); v != 10 {
+		t.Errorf("aggregate should be at version %d; is at version %d", 10, v)
+	}
+}
+
+func TestRebuild_withoutSnapshot(t *testing.T) {
+	ctx := context.Background()
+	estore := eventstore.New()
+	sstore := snapshot.NewStore()
+
+	id := uuid.New()
+	events := xevent.Make("foo", test.FooEventData{}, 10, xevent.ForAggregate(aggregate.New("foo", id)))
+	if err := estore.Insert(ctx, events...); err != nil {
+		t.Fatalf("insert events: %v", err)
+	}
+
+	a := newRebuildAggregate(id)
+	if err := snapshot.Rebuild(ctx, a, sstore, estore); err != nil {
+		t.Fatalf("Rebuild() failed with %q", err)
+	}
+
+	if a.mockState != (mockState{}) {
+		t.Errorf("state should be empty; got %v", a.mockState)
+	}
+
+	test.AssertEqualEvents(t, events, a.applied)
+
+	if v := a.AggregateVersion(); v != 10 {
+		t.Errorf("aggregate should be at version %d; is at version %d", 10, v)
+	}
+}
@@ -1,7 +1,11 @@
 package snapshot
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/helper/pick"
 )
 
 // A Schedule determines if an aggregate is scheduled to be snapshotted.
@@ -32,3 +36,72 @@ func Every(n int) Schedule {
 func (fn scheduleFunc) Test(a aggregate.Aggregate) bool {
 	return fn(a)
 }
+
+// A Threshold is a Schedule that triggers snapshots of an aggregate after a
+// fixed number of events.
+type Threshold struct {
+	every int
+}
+
+// NewSchedule returns a Threshold that triggers a snapshot of an aggregate
+// every nth event of that aggregate. A Threshold can be passed to a
+// repository as a Schedule, in which case it behaves like Every(n), or it can
+// be used to take snapshots manually using ShouldSnapshot and Trigger.
+func NewSchedule(n int) *Threshold {
+	return &Threshold{every: n}
+}
+
+// Test implements Schedule. Test returns true if the uncommitted changes of
+// the aggregate cross a multiple of n (see Every).
+func (s *Threshold) Test(a aggregate.Aggregate) bool {
+	if s.every <= 0 {
+		return false
+	}
+	return Every(s.every).Test(a)
+}
+
+// ShouldSnapshot returns whether an aggregate at version currentVersion should
+// be snapshotted, given that its latest snapshot was taken at version
+// lastSnapshotVersion. An aggregate without a snapshot has a
+// lastSnapshotVersion of 0. ShouldSnapshot returns true if at least n events
+// have been applied since the latest snapshot.
+func (s *Threshold) ShouldSnapshot(currentVersion, lastSnapshotVersion int) bool {
+	if s.every <= 0 {
+		return false
+	}
+	return currentVersion-lastSnapshotVersion >= s.every
+}
+
+// Trigger fetches the current state of the aggregate a from the repository and
+// saves a new snapshot of it into the store if ShouldSnapshot returns true for
+// the current version of the aggregate and the version of its latest snapshot.
+// a must be a new instance of the aggregate that only has its name and id set.
+// If the latest snapshot cannot be loaded from the store, the aggregate is
+// treated as if it had no snapshot.
+func (s *Threshold) Trigger(ctx context.Context, repo aggregate.Repository, store Store, a aggregate.Aggregate) error {
+	id, name, _ := a.Aggregate()
+
+	var last int
+	if snap, err := store.Latest(ctx, name, id); err == nil && snap != nil {
+		last = snap.AggregateVersion()
+	}
+
+	if err := repo.Fetch(ctx, a); err != nil {
+		return fmt.Errorf("fetch aggregate: %w [name=%v, id=%v]", err, name, id)
+	}
+
+	if !s.ShouldSnapshot(pick.AggregateVersion(a), last) {
+		return nil
+	}
+
+	snap, err := New(a)
+	if err != nil {
+		return fmt.Errorf("make snapshot: %w [name=%v, id=%v]", err, name, id)
+	}
+
+	if err := store.Save(ctx, snap); err != nil {
+		return fmt.Errorf("save snapshot: %w [name=%v, id=%v]", err, name, id)
+	}
+
+	return nil
+}
@@ -1,12 +1,15 @@
 package snapshot_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/aggregate/repository"
 	"github.com/modernice/goes/aggregate/snapshot"
+	"github.com/modernice/goes/event/eventstore"
 	"github.com/modernice/goes/event/test"
 	"github.com/modernice/goes/internal/xevent"
 )
@@ -118,3 +121,74 @@ func TestEvery(t *testing.T) {
 		})
 	}
 }
+
+func TestThreshold_ShouldSnapshot(t *testing.T) {
+	tests := []struct {
+		current int
+		last    int
+		want    bool
+	}{
+		{current: 0, last: 0, want: false},
+		{current: 2, last: 0, want: false},
+		{current: 3, last: 0, want: true},
+		{current: 4, last: 0, want: true},
+		{current: 5, last: 3, want: false},
+		{current: 6, last: 3, want: true},
+		{current: 10, last: 3, want: true},
+	}
+
+	s := snapshot.NewSchedule(3)
+	for _, tt := range tests {
+		if got := s.ShouldSnapshot(tt.current, tt.last); got != tt.want {
+			t.Errorf("ShouldSnapshot(%d, %d) should return %v; got %v", tt.current, tt.last, tt.want, got)
+		}
+	}
+}
+
+func TestThreshold_Trigger(t *testing.T) {
+	ctx := context.Background()
+	estore := eventstore.New()
+	repo := repository.New(estore)
+	sstore := snapshot.NewStore()
+	s := snapshot.NewSchedule(3)
+
+	id := uuid.New()
+	events := xevent.Make("foo", test.FooEventData{}, 5, xevent.ForAggregate(aggregate.New("foo", id)))
+
+	// no previous snapshot, less than n events
+	if err := estore.Insert(ctx, events[:2]...); err != nil {
+		t.Fatalf("insert events: %v", err)
+	}
+	if err := s.Trigger(ctx, repo, sstore, newRebuildAggregate(id)); err != nil {
+		t.Fatalf("Trigger() failed with %q", err)
+	}
+	if _, err := sstore.Latest(ctx, "foo", id); err != snapshot.ErrNotFound {
+		t.Fatalf("no snapshot should have been taken; Latest() returned %v", err)
+	}
+
+	// no previous snapshot, exactly n events
+	if err := estore.Insert(ctx, events[2]); err != nil {
+		t.Fatalf("insert events: %v", err)
+	}
+	if err := s.Trigger(ctx, repo, sstore, newRebuildAggregate(id)); err != nil {
+		t.Fatalf("Trigger() failed with %q", err)
+	}
+	snap, err := sstore.Latest(ctx, "foo", id)
+	if err != nil {
+		t.Fatalf("a snapshot should have been taken; Latest() failed with %q", err)
+	}
+	if snap.AggregateVersion() != 3 {
+		t.Fatalf("snapshot should have version %d; got %d", 3, snap.AggregateVersion())
+	}
+
+	// less than n events since the previous snapshot
+	if err := estore.Insert(ctx, events[3:]...); err != nil {
+		t.Fatalf("insert events: %v", err)
+	}
+	if err := s.Trigger(ctx, repo, sstore, newRebuildAggregate(id)); err != nil {
+		t.Fatalf("Trigger() failed with %q", err)
+	}
+	if snap, _ := sstore.Latest(ctx, "foo", id); snap.AggregateVersion() != 3 {
+		t.Fatalf("no new snapshot should have been taken; latest snapshot has version %d", snap.AggregateVersion())
+	}
+}
@@ -33,11 +33,13 @@ type Snapshot interface {
 type Option func(*snapshot)
 
 type snapshot struct {
-	id      uuid.UUID
-	name    string
-	version int
-	time    time.Time
-	state   []byte
+	id         uuid.UUID
+	name       string
+	version    int
+	time       time.Time
+	state      []byte
+	raw        []byte
+	compressor Compressor
 }
 
 // Time returns an Option that sets the Time of a snapshot.
@@ -47,7 +49,9 @@ func Time(t time.Time) Option {
 	}
 }
 
-// Data returns an Option that overrides the encoded data of a snapshot.
+// Data returns an Option that overrides the encoded data of a snapshot. If b
+// is the compressed state of a snapshot (as returned by Raw), it is
+// decompressed transparently.
 func Data(b []byte) Option {
 	return func(s *snapshot) {
 		s.state = b
@@ -76,6 +80,21 @@ func New(a aggregate.Aggregate, opts ...Option) (Snapshot, error) {
 		}
 	}
 
+	state, c, err := decompress(snap.state)
+	if err != nil {
+		return snap, fmt.Errorf("decompress snapshot: %w", err)
+	}
+
+	if c != nil {
+		snap.raw = snap.state
+		snap.state = state
+		snap.compressor = c
+	} else if snap.compressor != nil && snap.state != nil {
+		if snap.raw, err = compress(snap.compressor, snap.state); err != nil {
+			return snap, fmt.Errorf("compress snapshot: %w", err)
+		}
+	}
+
 	return &snap, nil
 }
 
@@ -99,6 +118,13 @@ func (s snapshot) State() []byte {
 	return s.state
 }
 
+func (s snapshot) Raw() []byte {
+	if s.raw != nil {
+		return s.raw
+	}
+	return s.state
+}
+
 // Sort sorts Snapshot and returns the sorted Snapshots.
 func Sort(snaps []Snapshot, s aggregate.Sorting, dir aggregate.SortDirection) []Snapshot {
 	return SortMulti(snaps, aggregate.SortOptions{Sort: s, Dir: dir})
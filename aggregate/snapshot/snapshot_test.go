@@ -9,6 +9,7 @@ import (
 	"github.com/modernice/goes/aggregate"
 	"github.com/modernice/goes/aggregate/snapshot"
 	"github.com/modernice/goes/internal/xtime"
+	"github.com/modernice/goes/test"
 )
 
 func TestNew(t *testing.T) {
@@ -37,6 +38,20 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNew_clock(t *testing.T) {
+	at := time.Date(2022, 1, 1, 12, 0, 0, 123456789, time.UTC)
+	test.WithFrozenClock(t, at)
+
+	snap, err := snapshot.New(&mockSnapshotter{Base: aggregate.New("foo", uuid.New())})
+	if err != nil {
+		t.Fatalf("New() failed with %q", err)
+	}
+
+	if !snap.Time().Equal(at) {
+		t.Errorf("Time should return %v; got %v", at, snap.Time())
+	}
+}
+
 func TestNew_marshaler(t *testing.T) {
 	a := &mockSnapshotter{Base: aggregate.New("foo", uuid.New())}
 	snap, err := snapshot.New(a)
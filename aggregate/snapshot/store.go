@@ -4,6 +4,7 @@ package snapshot
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
 	"github.com/modernice/goes/aggregate"
@@ -47,15 +48,56 @@ type Store interface {
 	//	// handle err
 	Query(context.Context, Query) (<-chan Snapshot, <-chan error, error)
 
+	// Count returns the number of Snapshots that fit the given Query. Count
+	// honors the same filters as Query but does not return the Snapshots.
+	Count(context.Context, Query) (int, error)
+
 	// Delete deletes a Snapshot from the Store.
 	Delete(context.Context, Snapshot) error
+
+	// Prune deletes all but the `keep` most recent Snapshots (ordered by
+	// aggregate version) of the aggregate with the given name and UUID and
+	// returns the number of deleted Snapshots. Prune is a no-op if the Store
+	// contains no more than `keep` Snapshots of the aggregate.
+	Prune(ctx context.Context, name string, id uuid.UUID, keep int) (int, error)
+
+	// PruneAll applies the Prune policy to every aggregate in the Store and
+	// returns the total number of deleted Snapshots.
+	PruneAll(ctx context.Context, keep int) (int, error)
 }
 
+// ErrNegativeKeep is returned by Store.Prune and Store.PruneAll when called
+// with a negative number of Snapshots to keep.
+var ErrNegativeKeep = errors.New("negative number of snapshots to keep")
+
 // Query is a query for snapshots.
 type Query interface {
 	aggregate.Query
 
 	Times() time.Constraints
+
+	// Limit returns the maximum number of snapshots to return. A limit of 0
+	// means no limit.
+	Limit() int
+
+	// Offset returns the number of (sorted) snapshots to skip.
+	Offset() int
+}
+
+// Paginate applies the pagination of the Query q to the given (sorted)
+// snapshots. Paginate can be used by Store implementations that cannot
+// paginate natively.
+func Paginate(q Query, snaps []Snapshot) []Snapshot {
+	if offset := q.Offset(); offset > 0 {
+		if offset >= len(snaps) {
+			return nil
+		}
+		snaps = snaps[offset:]
+	}
+	if limit := q.Limit(); limit > 0 && limit < len(snaps) {
+		snaps = snaps[:limit]
+	}
+	return snaps
 }
 
 // Test tests the Snapshot s against the Query q and returns true if q should
@@ -34,7 +34,10 @@ func Run(t *testing.T, newStore StoreFactory) {
 	run(t, "Version (not found)", testVersionNotFound, newStore)
 	run(t, "Limit", testLimit, newStore)
 	run(t, "Query", testQuery, newStore)
+	run(t, "Count", testCount, newStore)
 	run(t, "Delete", testDelete, newStore)
+	run(t, "Prune", testPrune, newStore)
+	run(t, "PruneAll", testPruneAll, newStore)
 }
 
 func run(t *testing.T, name string, runner func(*testing.T, StoreFactory), newStore StoreFactory) {
@@ -266,6 +269,7 @@ func testQuery(t *testing.T, newStore StoreFactory) {
 	run(t, "Version", testQueryVersion, newStore)
 	run(t, "Time", testQueryTime, newStore)
 	run(t, "Sorting", testQuerySorting, newStore)
+	run(t, "Pagination", testQueryPagination, newStore)
 }
 
 func testQueryName(t *testing.T, newStore StoreFactory) {
@@ -484,6 +488,108 @@ func testQuerySorting(t *testing.T, newStore StoreFactory) {
 	}
 }
 
+func testQueryPagination(t *testing.T, newStore StoreFactory) {
+	as := make([]aggregate.Aggregate, 9)
+	for i := range as {
+		as[i] = &snapshotter{Base: aggregate.New("foo", uuid.New(), aggregate.Version(i+1))}
+	}
+	snaps := makeSnaps(as)
+
+	store := newStore()
+	for i := range snaps {
+		snap := snaps[len(snaps)-1-i]
+		if err := store.Save(context.Background(), snap); err != nil {
+			t.Fatalf("Save shouldn't fail; failed with %q", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		q    query.Query
+		want []snapshot.Snapshot
+	}{
+		{
+			name: "page 2",
+			q: query.New(
+				query.SortBy(aggregate.SortVersion, aggregate.SortAsc),
+				query.Paginate(3, 3),
+			),
+			want: snaps[3:6],
+		},
+		{
+			name: "last page",
+			q: query.New(
+				query.SortBy(aggregate.SortVersion, aggregate.SortDesc),
+				query.Paginate(4, 8),
+			),
+			want: []snapshot.Snapshot{snaps[0]},
+		},
+		{
+			name: "offset only",
+			q: query.New(
+				query.SortBy(aggregate.SortVersion, aggregate.SortAsc),
+				query.Paginate(0, 7),
+			),
+			want: snaps[7:],
+		},
+		{
+			name: "out of range",
+			q: query.New(
+				query.SortBy(aggregate.SortVersion, aggregate.SortAsc),
+				query.Paginate(3, 9),
+			),
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runQuery(store, tt.q)
+			if err != nil {
+				t.Fatalf("query failed with %q", err)
+			}
+
+			assertEqual(t, tt.want, result)
+		})
+	}
+}
+
+func testCount(t *testing.T, newStore StoreFactory) {
+	s := newStore()
+	foos, _ := xaggregate.Make(5, xaggregate.Name("foo"))
+	bars, _ := xaggregate.Make(3, xaggregate.Name("bar"))
+
+	as := append(foos, bars...)
+	for i, a := range as {
+		id, name, _ := a.Aggregate()
+		as[i] = &snapshotter{Base: aggregate.New(name, id)}
+	}
+
+	for _, snap := range makeSnaps(as) {
+		if err := s.Save(context.Background(), snap); err != nil {
+			t.Fatalf("Save shouldn't fail; failed with %q", err)
+		}
+	}
+
+	count, err := s.Count(context.Background(), query.New(query.Name("foo")))
+	if err != nil {
+		t.Fatalf("Count shouldn't fail; failed with %q", err)
+	}
+
+	if count != 5 {
+		t.Errorf("Count should return %d; got %d", 5, count)
+	}
+
+	count, err = s.Count(context.Background(), query.New())
+	if err != nil {
+		t.Fatalf("Count shouldn't fail; failed with %q", err)
+	}
+
+	if count != 8 {
+		t.Errorf("Count should return %d; got %d", 8, count)
+	}
+}
+
 func testDelete(t *testing.T, newStore StoreFactory) {
 	s := newStore()
 	a := &snapshotter{Base: aggregate.New("foo", uuid.New())}
@@ -506,6 +612,107 @@ func testDelete(t *testing.T, newStore StoreFactory) {
 	}
 }
 
+func testPrune(t *testing.T, newStore StoreFactory) {
+	run(t, "Basic", testPruneBasic, newStore)
+	run(t, "NotFound", testPruneNotFound, newStore)
+}
+
+func testPruneBasic(t *testing.T, newStore StoreFactory) {
+	s := newStore()
+
+	id := uuid.New()
+	as := []aggregate.Aggregate{
+		&snapshotter{Base: aggregate.New("foo", id, aggregate.Version(1))},
+		&snapshotter{Base: aggregate.New("foo", id, aggregate.Version(5))},
+		&snapshotter{Base: aggregate.New("foo", id, aggregate.Version(10))},
+		&snapshotter{Base: aggregate.New("foo", id, aggregate.Version(20))},
+	}
+	snaps := makeSnaps(as)
+
+	for _, snap := range snaps {
+		if err := s.Save(context.Background(), snap); err != nil {
+			t.Fatalf("Save shouldn't fail; failed with %q", err)
+		}
+	}
+
+	deleted, err := s.Prune(context.Background(), "foo", id, 2)
+	if err != nil {
+		t.Fatalf("Prune shouldn't fail; failed with %q", err)
+	}
+
+	if deleted != 2 {
+		t.Errorf("Prune should delete %d Snapshots; deleted %d", 2, deleted)
+	}
+
+	result, err := runQuery(s, query.New(
+		query.ID(id),
+		query.SortBy(aggregate.SortVersion, aggregate.SortAsc),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, snaps[2:], result)
+
+	deleted, err = s.Prune(context.Background(), "foo", id, 2)
+	if err != nil {
+		t.Fatalf("Prune shouldn't fail; failed with %q", err)
+	}
+
+	if deleted != 0 {
+		t.Errorf("Prune should delete %d Snapshots; deleted %d", 0, deleted)
+	}
+}
+
+func testPruneNotFound(t *testing.T, newStore StoreFactory) {
+	s := newStore()
+
+	deleted, err := s.Prune(context.Background(), "foo", uuid.New(), 2)
+	if err != nil {
+		t.Fatalf("Prune shouldn't fail; failed with %q", err)
+	}
+
+	if deleted != 0 {
+		t.Errorf("Prune should delete %d Snapshots; deleted %d", 0, deleted)
+	}
+}
+
+func testPruneAll(t *testing.T, newStore StoreFactory) {
+	s := newStore()
+
+	fooID, barID := uuid.New(), uuid.New()
+	as := []aggregate.Aggregate{
+		&snapshotter{Base: aggregate.New("foo", fooID, aggregate.Version(1))},
+		&snapshotter{Base: aggregate.New("foo", fooID, aggregate.Version(2))},
+		&snapshotter{Base: aggregate.New("foo", fooID, aggregate.Version(3))},
+		&snapshotter{Base: aggregate.New("bar", barID, aggregate.Version(1))},
+		&snapshotter{Base: aggregate.New("bar", barID, aggregate.Version(2))},
+	}
+	snaps := makeSnaps(as)
+
+	for _, snap := range snaps {
+		if err := s.Save(context.Background(), snap); err != nil {
+			t.Fatalf("Save shouldn't fail; failed with %q", err)
+		}
+	}
+
+	deleted, err := s.PruneAll(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("PruneAll shouldn't fail; failed with %q", err)
+	}
+
+	if deleted != 3 {
+		t.Errorf("PruneAll should delete %d Snapshots; deleted %d", 3, deleted)
+	}
+
+	result, err := runQuery(s, query.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertSame(t, []snapshot.Snapshot{snaps[2], snaps[4]}, result)
+}
+
 func runQuery(s snapshot.Store, q snapshot.Query) ([]snapshot.Snapshot, error) {
 	str, errs, err := s.Query(context.Background(), q)
 	if err != nil {
@@ -0,0 +1,147 @@
+// Package softdelete provides helpers to detect and exclude soft-deleted
+// aggregates. An aggregate is soft-deleted if its event stream contains an
+// event with data that implements aggregate.SoftDeleter and that is not
+// followed by an event with data that implements aggregate.SoftRestorer.
+//
+// Aggregates can use their own soft-delete events or the standard DeletedEvent
+// and RestoredEvent that are created by Delete and Restore.
+package softdelete
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/codec"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/event/query"
+	"github.com/modernice/goes/helper/streams"
+)
+
+const (
+	// DeletedEvent is the name of the standard soft-delete event.
+	DeletedEvent = "goes.aggregate.deleted"
+
+	// RestoredEvent is the name of the standard restore event.
+	RestoredEvent = "goes.aggregate.restored"
+)
+
+// DeletedData is the event data of DeletedEvent.
+type DeletedData struct {
+	Reason string
+}
+
+// RestoredData is the event data of RestoredEvent.
+type RestoredData struct{}
+
+// SoftDelete implements aggregate.SoftDeleter.
+func (DeletedData) SoftDelete() bool { return true }
+
+// SoftRestore implements aggregate.SoftRestorer.
+func (RestoredData) SoftRestore() bool { return true }
+
+// RegisterEvents registers the standard soft-delete events into a registry.
+func RegisterEvents(reg *codec.Registry) {
+	gob := codec.Gob(reg)
+	gob.GobRegister(DeletedEvent, func() any { return DeletedData{} })
+	gob.GobRegister(RestoredEvent, func() any { return RestoredData{} })
+}
+
+// Delete soft-deletes the aggregate a by applying and recording the next
+// DeletedEvent with the given reason. The returned event must be committed
+// like any other change of the aggregate.
+//
+//	var foo aggregate.Aggregate
+//	softdelete.Delete(foo, "duplicate")
+//	err := repo.Save(context.TODO(), foo)
+func Delete(a aggregate.Aggregate, reason string) event.Event {
+	return aggregate.Next(a, DeletedEvent, DeletedData{Reason: reason}).Any()
+}
+
+// Restore restores the soft-deleted aggregate a by applying and recording the
+// next RestoredEvent.
+func Restore(a aggregate.Aggregate) event.Event {
+	return aggregate.Next(a, RestoredEvent, RestoredData{}).Any()
+}
+
+// IsDeleted returns whether the aggregate that is made up of the provided
+// events should be considered soft-deleted. The events must be sorted by
+// aggregate version.
+func IsDeleted(events []event.Event) bool {
+	var softDeleted bool
+	for _, evt := range events {
+		data := evt.Data()
+		if data, ok := data.(aggregate.SoftDeleter); ok && data.SoftDelete() {
+			softDeleted = true
+		}
+		if data, ok := data.(aggregate.SoftRestorer); ok && data.SoftRestore() {
+			softDeleted = false
+		}
+	}
+	return softDeleted
+}
+
+// Query returns a query for the soft-delete and restore events with the given
+// names, sorted by aggregate. Event stores cannot inspect event data, which is
+// why the names of the events that implement aggregate.SoftDeleter or
+// aggregate.SoftRestorer must be provided.
+func Query(eventNames ...string) event.Query {
+	return query.New(query.Name(eventNames...), query.SortByAggregate())
+}
+
+// Deleted queries the event store for the soft-delete and restore events with
+// the given names and returns the aggregates that are soft-deleted. Only the
+// soft-delete and restore events are fetched from the store, not the complete
+// event streams of the aggregates. If no event names are provided, the standard
+// DeletedEvent and RestoredEvent are queried.
+func Deleted(ctx context.Context, store event.Store, eventNames ...string) ([]aggregate.Ref, error) {
+	if len(eventNames) == 0 {
+		eventNames = []string{DeletedEvent, RestoredEvent}
+	}
+
+	str, errs, err := store.Query(ctx, Query(eventNames...))
+	if err != nil {
+		return nil, fmt.Errorf("query events: %w", err)
+	}
+
+	events := make(map[aggregate.Ref][]event.Event)
+	var refs []aggregate.Ref
+	if err := streams.Walk(ctx, func(evt event.Event) error {
+		id, name, _ := evt.Aggregate()
+		ref := aggregate.Ref{Name: name, ID: id}
+		if _, ok := events[ref]; !ok {
+			refs = append(refs, ref)
+		}
+		events[ref] = append(events[ref], evt)
+		return nil
+	}, str, errs); err != nil {
+		return nil, err
+	}
+
+	var deleted []aggregate.Ref
+	for _, ref := range refs {
+		if IsDeleted(events[ref]) {
+			deleted = append(deleted, ref)
+		}
+	}
+
+	return deleted, nil
+}
+
+// Exclude returns a filter that rejects the events of the given aggregates.
+// Use it together with Deleted to exclude soft-deleted aggregates from an
+// aggregate stream before their events are buffered:
+//
+//	deleted, err := softdelete.Deleted(ctx, store, "foo.deleted", "foo.restored")
+//	// handle err
+//	str, errs := stream.New(ctx, events, stream.Filter(softdelete.Exclude(deleted...)))
+func Exclude(refs ...aggregate.Ref) func(event.Event) bool {
+	excluded := make(map[aggregate.Ref]bool, len(refs))
+	for _, ref := range refs {
+		excluded[ref] = true
+	}
+	return func(evt event.Event) bool {
+		id, name, _ := evt.Aggregate()
+		return !excluded[aggregate.Ref{Name: name, ID: id}]
+	}
+}
@@ -0,0 +1,185 @@
+package softdelete_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/aggregate/softdelete"
+	"github.com/modernice/goes/codec"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/event/eventstore"
+)
+
+type deletedData struct{}
+
+func (deletedData) SoftDelete() bool { return true }
+
+type restoredData struct{}
+
+func (restoredData) SoftRestore() bool { return true }
+
+func TestIsDeleted(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name   string
+		events []event.Event
+		want   bool
+	}{
+		{
+			name:   "no events",
+			events: nil,
+			want:   false,
+		},
+		{
+			name: "without soft-delete event",
+			events: []event.Event{
+				event.New("foo", "created", event.Aggregate(id, "foo", 1)).Any(),
+			},
+			want: false,
+		},
+		{
+			name: "with soft-delete event",
+			events: []event.Event{
+				event.New("foo", "created", event.Aggregate(id, "foo", 1)).Any(),
+				event.New("foo.deleted", deletedData{}, event.Aggregate(id, "foo", 2)).Any(),
+			},
+			want: true,
+		},
+		{
+			name: "restored",
+			events: []event.Event{
+				event.New("foo", "created", event.Aggregate(id, "foo", 1)).Any(),
+				event.New("foo.deleted", deletedData{}, event.Aggregate(id, "foo", 2)).Any(),
+				event.New("foo.restored", restoredData{}, event.Aggregate(id, "foo", 3)).Any(),
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := softdelete.IsDeleted(tt.events); got != tt.want {
+				t.Errorf("IsDeleted() should return %v; got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDeleted(t *testing.T) {
+	active := aggregate.Ref{Name: "foo", ID: uuid.New()}
+	deleted := aggregate.Ref{Name: "foo", ID: uuid.New()}
+	restored := aggregate.Ref{Name: "foo", ID: uuid.New()}
+
+	store := eventstore.New(
+		event.New("foo", "created", event.Aggregate(active.ID, active.Name, 1)).Any(),
+		event.New("foo", "created", event.Aggregate(deleted.ID, deleted.Name, 1)).Any(),
+		event.New("foo.deleted", deletedData{}, event.Aggregate(deleted.ID, deleted.Name, 2)).Any(),
+		event.New("foo", "created", event.Aggregate(restored.ID, restored.Name, 1)).Any(),
+		event.New("foo.deleted", deletedData{}, event.Aggregate(restored.ID, restored.Name, 2)).Any(),
+		event.New("foo.restored", restoredData{}, event.Aggregate(restored.ID, restored.Name, 3)).Any(),
+	)
+
+	refs, err := softdelete.Deleted(context.Background(), store, "foo.deleted", "foo.restored")
+	if err != nil {
+		t.Fatalf("Deleted() failed with %q", err)
+	}
+
+	if len(refs) != 1 || refs[0] != deleted {
+		t.Fatalf("Deleted() should return %v; got %v", []aggregate.Ref{deleted}, refs)
+	}
+}
+
+func TestExclude(t *testing.T) {
+	deleted := aggregate.Ref{Name: "foo", ID: uuid.New()}
+	active := aggregate.Ref{Name: "foo", ID: uuid.New()}
+
+	filter := softdelete.Exclude(deleted)
+
+	if filter(event.New("foo", "created", event.Aggregate(deleted.ID, deleted.Name, 1)).Any()) {
+		t.Errorf("filter should reject events of excluded aggregates")
+	}
+
+	if !filter(event.New("foo", "created", event.Aggregate(active.ID, active.Name, 1)).Any()) {
+		t.Errorf("filter should accept events of other aggregates")
+	}
+}
+
+func TestDelete_Restore(t *testing.T) {
+	a := aggregate.New("foo", uuid.New())
+
+	evt := softdelete.Delete(a, "duplicate")
+
+	if evt.Name() != softdelete.DeletedEvent {
+		t.Fatalf("Delete() should return a %q event; got %q", softdelete.DeletedEvent, evt.Name())
+	}
+
+	if data, ok := evt.Data().(softdelete.DeletedData); !ok || data.Reason != "duplicate" {
+		t.Fatalf("Delete() should return an event with %v data; got %v", softdelete.DeletedData{Reason: "duplicate"}, evt.Data())
+	}
+
+	if !softdelete.IsDeleted(a.AggregateChanges()) {
+		t.Fatalf("aggregate should be soft-deleted after Delete()")
+	}
+
+	evt = softdelete.Restore(a)
+
+	if evt.Name() != softdelete.RestoredEvent {
+		t.Fatalf("Restore() should return a %q event; got %q", softdelete.RestoredEvent, evt.Name())
+	}
+
+	if _, _, v := evt.Aggregate(); v != 2 {
+		t.Errorf("Restore() should return an event with version %d; got %d", 2, v)
+	}
+
+	if softdelete.IsDeleted(a.AggregateChanges()) {
+		t.Fatalf("aggregate should not be soft-deleted after Restore()")
+	}
+}
+
+func TestDeleted_standardEvents(t *testing.T) {
+	deleted := aggregate.New("foo", uuid.New())
+	softdelete.Delete(deleted, "")
+
+	restored := aggregate.New("foo", uuid.New())
+	softdelete.Delete(restored, "")
+	softdelete.Restore(restored)
+
+	store := eventstore.New(append(deleted.AggregateChanges(), restored.AggregateChanges()...)...)
+
+	refs, err := softdelete.Deleted(context.Background(), store)
+	if err != nil {
+		t.Fatalf("Deleted() failed with %q", err)
+	}
+
+	if len(refs) != 1 || refs[0].ID != deleted.AggregateID() {
+		t.Fatalf("Deleted() should return aggregate %s; got %v", deleted.AggregateID(), refs)
+	}
+}
+
+func TestRegisterEvents(t *testing.T) {
+	reg := codec.New()
+	softdelete.RegisterEvents(reg)
+
+	var buf bytes.Buffer
+	if err := reg.Encode(&buf, softdelete.DeletedEvent, softdelete.DeletedData{Reason: "duplicate"}); err != nil {
+		t.Fatalf("Encode() failed with %q", err)
+	}
+
+	data, err := reg.Decode(&buf, softdelete.DeletedEvent)
+	if err != nil {
+		t.Fatalf("Decode() failed with %q", err)
+	}
+
+	want := softdelete.DeletedData{Reason: "duplicate"}
+	if data != want {
+		t.Fatalf("Decode() should return %v; got %v", want, data)
+	}
+
+	if _, err := reg.New(softdelete.RestoredEvent); err != nil {
+		t.Fatalf("New(%q) failed with %q", softdelete.RestoredEvent, err)
+	}
+}
@@ -3,6 +3,7 @@ package stream_test
 import (
 	"context"
 	"math/rand"
+	"runtime"
 	"testing"
 
 	"github.com/google/uuid"
@@ -110,24 +111,43 @@ func BenchmarkStream_100000A_100E(b *testing.B) {
 	benchmark(b, 100000, 100)
 }
 
+func BenchmarkStream_Memory_100000A_10E(b *testing.B) {
+	b.Run("Ungrouped", func(b *testing.B) {
+		runMemory(b, 100000, 10)
+	})
+
+	b.Run("Ungrouped+MaxPendingGroups", func(b *testing.B) {
+		runMemory(b, 100000, 10, stream.MaxPendingGroups(100000))
+	})
+
+	b.Run("Grouped", func(b *testing.B) {
+		runMemory(b, 100000, 10, stream.Grouped(true))
+	})
+}
+
 func benchmark(b *testing.B, naggregates, nevents int) {
 	b.Run("Ungrouped+Unsorted", func(b *testing.B) {
-		run(b, naggregates, nevents, false, false)
+		run(b, naggregates, nevents, false, false, false)
 	})
 
 	b.Run("Grouped+Unsorted", func(b *testing.B) {
-		run(b, naggregates, nevents, true, false)
+		run(b, naggregates, nevents, true, false, false)
 	})
 
 	b.Run("Grouped+Sorted", func(b *testing.B) {
-		run(b, naggregates, nevents, true, true)
+		run(b, naggregates, nevents, true, true, false)
+	})
+
+	b.Run("Grouped+Sorted+StreamApply", func(b *testing.B) {
+		// Histories of a StreamApply Stream must be applied before the next
+		// History is received.
+		run(b, naggregates, nevents, true, true, true, stream.StreamApply(true))
 	})
 }
 
-func run(b *testing.B, naggregates, nevents int, grouped, sorted bool) {
+func run(b *testing.B, naggregates, nevents int, grouped, sorted, apply bool, opts ...stream.Option) {
 	as := makeAggregates(naggregates)
 	events := makeEvents(nevents, as, grouped, sorted)
-	var opts []stream.Option
 	if grouped {
 		opts = append(opts, stream.Grouped(true))
 	}
@@ -162,6 +182,9 @@ L:
 				ref := res.Aggregate()
 
 				a := &mockAggregate{Base: aggregate.New(ref.Name, ref.ID)}
+				if apply {
+					res.Apply(a)
+				}
 				as = append(as, a)
 			}
 		}
@@ -169,6 +192,44 @@ L:
 	_ = gerr
 }
 
+// runMemory streams events that are sorted by aggregate and reports the peak
+// heap size that was sampled while receiving the Histories.
+func runMemory(b *testing.B, naggregates, nevents int, opts ...stream.Option) {
+	as := makeAggregates(naggregates)
+	events := makeEvents(nevents, as, true, true)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var peak uint64
+	for n := 0; n < b.N; n++ {
+		b.StopTimer()
+		runtime.GC()
+		estr := streams.New(events)
+		b.StartTimer()
+
+		str, errs := stream.New(context.Background(), estr, opts...)
+
+		var received int
+		if err := streams.Walk(context.Background(), func(h aggregate.History) error {
+			if received++; received%1000 == 0 {
+				b.StopTimer()
+				var stats runtime.MemStats
+				runtime.ReadMemStats(&stats)
+				if stats.HeapInuse > peak {
+					peak = stats.HeapInuse
+				}
+				b.StartTimer()
+			}
+			return nil
+		}, str, errs); err != nil {
+			b.Fatalf("Walk() failed with %q", err)
+		}
+	}
+
+	b.ReportMetric(float64(peak), "peak-heap-B")
+}
+
 func (a *mockAggregate) ApplyEvent(evt event.Event) {
 	for i, name := range names {
 		if name != evt.Name() {
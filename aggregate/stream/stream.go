@@ -2,15 +2,36 @@ package stream
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/modernice/goes/aggregate"
-	"github.com/modernice/goes/aggregate/internal/softdelete"
+	"github.com/modernice/goes/aggregate/softdelete"
 	"github.com/modernice/goes/event"
 	"github.com/modernice/goes/helper/streams"
 )
 
+// ErrNegativeLimit is returned by a Stream that was created with a negative
+// Limit.
+var ErrNegativeLimit = errors.New("negative limit")
+
+// ErrTimeout is returned by a Stream that was created with a Timeout if no
+// event is received from the event stream within the timeout.
+var ErrTimeout = errors.New("timed out waiting for event")
+
+// ErrTooManyPendingGroups is returned by a Stream that was created with
+// MaxPendingGroups if an event of another aggregate is received while the
+// maximum number of aggregates is already buffered.
+var ErrTooManyPendingGroups = errors.New("too many pending aggregates")
+
+// ErrSoftDeleted is reported by a StreamApply Stream for an aggregate that is
+// soft-deleted, unless WithSoftDeleted is enabled.
+var ErrSoftDeleted = errors.New("aggregate is soft-deleted")
+
 // Option is a stream option.
 type Option func(*options)
 
@@ -18,7 +39,18 @@ type options struct {
 	isSorted            bool
 	isGrouped           bool
 	validateConsistency bool
+	allowSkipped        bool
+	deduplicate         bool
 	withSoftDeleted     bool
+	streamApply         bool
+	continueOnError     bool
+	onAggregateError    func(aggregate.Ref, error)
+	limit               int
+	cancelUpstream      context.CancelFunc
+	maxPendingGroups    int
+	onProgress          func(events, aggregates int)
+	progressInterval    int
+	timeout             time.Duration
 	filters             []func(event.Event) bool
 	streamErrors        []<-chan error
 }
@@ -26,6 +58,8 @@ type options struct {
 type stream struct {
 	options
 
+	ctx        context.Context
+	cancel     context.CancelFunc
 	stream     <-chan event.Evt[any]
 	inErrors   <-chan error
 	stopErrors func()
@@ -39,6 +73,16 @@ type stream struct {
 
 	out       chan aggregate.History
 	outErrors chan error
+
+	eventCount     int64
+	aggregateCount int64
+	progress       chan progress
+	progressDone   chan struct{}
+}
+
+type progress struct {
+	events     int
+	aggregates int
 }
 
 type job struct {
@@ -57,6 +101,33 @@ type applier struct {
 	apply func(aggregate.Aggregate)
 }
 
+type streamedHistory struct {
+	job
+
+	validate bool
+	dedupe   bool
+	cOpts    []aggregate.ConsistencyOption
+
+	mux      sync.Mutex
+	applying bool
+	buffered []event.Event
+	events   chan event.Event
+
+	once    sync.Once
+	done    chan struct{}
+	err     error
+	deleted bool
+}
+
+// applyState is the state of a single pass over the events of a
+// streamedHistory.
+type applyState struct {
+	prev    event.Event
+	seen    map[uuid.UUID]bool
+	err     error
+	deleted bool
+}
+
 // Errors returns an Option that provides a Stream with error channels. A Stream
 // will cancel its operation as soon as an error can be received from one of the
 // error channels.
@@ -134,6 +205,35 @@ func ValidateConsistency(v bool) Option {
 	}
 }
 
+// AllowSkippedVersions returns an Option that relaxes the consistency
+// validation of the events of an aggregate, so that gaps between the versions
+// of the events are accepted. Gaps are expected when events are removed from
+// the stream, for example by a Filter. The events of an aggregate must still
+// belong to the aggregate, have strictly increasing versions and strictly
+// increasing times (see aggregate.AllowSkippedVersions).
+//
+// AllowSkippedVersions has no effect if ValidateConsistency is disabled.
+func AllowSkippedVersions(v bool) Option {
+	return func(opts *options) {
+		opts.allowSkipped = v
+	}
+}
+
+// Deduplicate returns an Option that specifies if the Stream should discard
+// duplicate events of an aggregate. When enabled, events of an aggregate that
+// have the same id as a previously received event of the same aggregate are
+// discarded before the consistency of the events is validated and before they
+// are applied. Only the first occurrence of an event is kept.
+//
+// Enable this option if the underlying event stream may deliver the same event
+// more than once, for example when events are consumed from an at-least-once
+// message broker. Deduplicate is disabled by default.
+func Deduplicate(v bool) Option {
+	return func(opts *options) {
+		opts.deduplicate = v
+	}
+}
+
 // Filter returns an Option that filters incoming events before they're handled
 // by the Stream. events are passed to every fn in fns until a fn returns false.
 // If any of fns returns false, the event is discarded by the Stream.
@@ -152,6 +252,166 @@ func WithSoftDeleted(v bool) Option {
 	}
 }
 
+// StreamApply returns an Option that reduces the memory usage of aggregate
+// builds by feeding the events of an aggregate one at a time to the aggregate
+// instead of collecting all events of the aggregate before applying them.
+//
+// StreamApply only has an effect if both Grouped and Sorted are enabled,
+// because only then the Stream can apply events in the order they are
+// received. When enabled, a History is returned as soon as the first event of
+// an aggregate is received, and its Apply method pulls the remaining events
+// of the aggregate from the underlying event stream. Only a History that is
+// applied before the next History is received from the Stream benefits from
+// StreamApply. The events of a History that has not been applied by then are
+// buffered until it is applied, so that the Stream does not block, and
+// Histories can still be collected using streams.Drain.
+//
+// Consistency is validated event by event while applying. A consistency error
+// stops the build of the affected aggregate. Because a History is returned
+// before all events of its aggregate have been received, soft-deletion can
+// only be detected after the final event of an aggregate. If WithSoftDeleted
+// is not enabled, the Stream reports soft-deleted aggregates with an error
+// that unwraps to ErrSoftDeleted. Consistency errors and ErrSoftDeleted are
+// sent to the error channel of the Stream when the next History is returned
+// or the Stream is closed, or reported to OnAggregateError if ContinueOnError
+// is enabled. Aggregates that are reported this way must be discarded by the
+// caller.
+func StreamApply(v bool) Option {
+	return func(opts *options) {
+		opts.streamApply = v
+	}
+}
+
+// ContinueOnError returns an Option that specifies if the Stream should keep
+// returning Histories after the events of an aggregate failed the consistency
+// validation. By default, consistency errors are sent to the error channel of
+// the Stream, which makes consumers like streams.Drain abort the whole stream.
+// When ContinueOnError is enabled, consistency errors are not sent to the
+// error channel. Instead, the affected aggregate is skipped and the error is
+// reported to the function that was provided with OnAggregateError. Errors of
+// the underlying event stream are still sent to the error channel.
+//
+// Enable ContinueOnError when building many aggregates at once, so that a
+// single corrupt aggregate does not stop the build of the other aggregates.
+func ContinueOnError(v bool) Option {
+	return func(opts *options) {
+		opts.continueOnError = v
+	}
+}
+
+// OnAggregateError returns an Option that reports the consistency errors of
+// aggregates to fn when ContinueOnError is enabled. fn is called with the
+// affected aggregate and its error. fn is always called from the same
+// goroutine, so it does not need to be safe for concurrent use. The Stream
+// blocks while fn is running.
+func OnAggregateError(fn func(aggregate.Ref, error)) Option {
+	return func(opts *options) {
+		opts.onAggregateError = fn
+	}
+}
+
+// Limit returns an Option that limits the number of aggregates built by the
+// Stream to n. A Limit of 0 means no limit. A negative Limit causes the Stream
+// to return ErrNegativeLimit on its error channel.
+//
+// An aggregate counts towards the limit as soon as the first of its events is
+// accepted by the Stream, which means that aggregates that are discarded
+// because of a consistency error or because they are soft-deleted also count
+// towards the limit. When the Stream receives the first event of another
+// aggregate after n aggregates have been accepted, it stops receiving from the
+// event stream, cancels the upstream (see CancelUpstream) and returns the
+// Histories of the accepted aggregates. The event stream does not have to be
+// closed for the Stream to finish, so Limit can be used with unbounded event
+// streams.
+//
+// When Grouped is enabled, all events of the accepted aggregates have been
+// received at that point. Without Grouped, events of the accepted aggregates
+// that follow later in the event stream are not part of their Histories, so
+// the Histories are consistent but may not reflect the latest state of the
+// aggregates.
+func Limit(n int) Option {
+	return func(opts *options) {
+		opts.limit = n
+	}
+}
+
+// CancelUpstream returns an Option that provides the Stream with the cancel
+// function of the producer of the event stream, e.g. an event store query. The
+// Stream calls cancel as soon as it stops receiving from the event stream, so
+// that the producer is stopped when the Stream finishes before the event
+// stream is closed, for example because the Limit was reached, the Timeout
+// expired or an error occurred:
+//
+//	qctx, cancel := context.WithCancel(ctx)
+//	events, errs, err := store.Query(qctx, query.New())
+//	// handle err
+//	str, errs := stream.New(ctx, events, stream.Errors(errs), stream.Limit(10), stream.CancelUpstream(cancel))
+func CancelUpstream(cancel context.CancelFunc) Option {
+	return func(opts *options) {
+		opts.cancelUpstream = cancel
+	}
+}
+
+// MaxPendingGroups returns an Option that limits the number of aggregates whose
+// events are buffered by the Stream at the same time to n. A value of 0 or less
+// means no limit. MaxPendingGroups has no effect when Grouped is enabled,
+// because a grouped Stream only buffers the events of a single aggregate.
+//
+// Without Grouped, the Stream buffers the events of every aggregate until the
+// event stream is closed, because more events of an aggregate could follow at
+// any time. For the same reason, the Stream cannot wait for buffered
+// aggregates to complete before it reads further events. When an event of
+// another aggregate is received while n aggregates are buffered, the Stream
+// therefore stops receiving from the event stream and fails with an error that
+// unwraps to ErrTooManyPendingGroups. The Histories of the buffered aggregates
+// are discarded instead of being returned with missing events.
+//
+// Use MaxPendingGroups to bound the memory of a Stream whose event stream is
+// expected to contain at most n aggregates. Enable Grouped instead if the
+// events are grouped by aggregate.
+func MaxPendingGroups(n int) Option {
+	return func(opts *options) {
+		opts.maxPendingGroups = n
+	}
+}
+
+// OnProgress returns an Option that reports the progress of the Stream to fn.
+// fn is called with the number of events that have been received from the
+// event stream and the number of aggregates that have been returned by the
+// Stream. fn is called every nth received event (see ProgressInterval) and a
+// final time before the Stream is closed.
+//
+// fn is always called from the same goroutine, so it does not need to be
+// safe for concurrent use. The Stream blocks while fn is running.
+func OnProgress(fn func(events, aggregates int)) Option {
+	return func(opts *options) {
+		opts.onProgress = fn
+	}
+}
+
+// ProgressInterval returns an Option that specifies after how many received
+// events the Stream reports its progress to the OnProgress function. The
+// default interval is 100 events.
+func ProgressInterval(n int) Option {
+	return func(opts *options) {
+		opts.progressInterval = n
+	}
+}
+
+// Timeout returns an Option that limits how long the Stream waits for the next
+// event of the event stream. If no event is received within d, the Stream
+// sends ErrTimeout to its error channel and stops receiving from the event
+// stream. Aggregates whose events have already been received are still
+// returned. A Timeout of 0 means no timeout.
+//
+// Use Timeout to prevent a Stream from hanging when the underlying event
+// stream stalls without being closed.
+func Timeout(d time.Duration) Option {
+	return func(opts *options) {
+		opts.timeout = d
+	}
+}
+
 // New takes a channel of events and returns both a channel of aggregate
 // Histories and an error channel. A History apply itself on an aggregate to
 // build the current state of the aggregate.
@@ -191,8 +451,25 @@ func NewOf[D any, Event event.Of[D]](ctx context.Context, events <-chan Event, o
 		events = evts
 	}
 
+	cfg := options{validateConsistency: true, progressInterval: 100}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.limit < 0 {
+		out, outErrs := make(chan aggregate.History), make(chan error, 1)
+		outErrs <- fmt.Errorf("%w: %d", ErrNegativeLimit, cfg.limit)
+		close(out)
+		close(outErrs)
+		return out, outErrs
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
 	aes := stream{
-		options:    options{validateConsistency: true},
+		options:    cfg,
+		ctx:        ctx,
+		cancel:     cancel,
 		stream:     streams.Map(ctx, events, func(e Event) event.Evt[any] { return event.Any[D](e) }),
 		acceptDone: make(chan struct{}),
 		events:     make(chan event.Event),
@@ -201,12 +478,20 @@ func NewOf[D any, Event event.Of[D]](ctx context.Context, events <-chan Event, o
 		out:        make(chan aggregate.History),
 		outErrors:  make(chan error),
 	}
-	for _, opt := range opts {
-		opt(&aes.options)
-	}
 
 	aes.inErrors, aes.stopErrors = streams.FanIn(aes.streamErrors...)
 
+	if aes.onProgress != nil {
+		aes.progress = make(chan progress)
+		aes.progressDone = make(chan struct{})
+		go aes.reportProgress()
+	}
+
+	if aes.streamApply && aes.isGrouped && aes.isSorted {
+		go aes.streamEvents()
+		return aes.out, aes.outErrors
+	}
+
 	go aes.acceptEvents()
 	go aes.groupEvents()
 	go aes.sortEvents()
@@ -214,18 +499,59 @@ func NewOf[D any, Event event.Of[D]](ctx context.Context, events <-chan Event, o
 	return aes.out, aes.outErrors
 }
 
+// Build takes a channel of events and returns both a channel of aggregates and
+// an error channel. Build wraps New and applies each History on the aggregate
+// that is returned by factory for the name and id of the History. The returned
+// aggregates are fully built when they are received from the channel.
+//
+//	var events <-chan event.Event
+//	str, errs := stream.Build(context.TODO(), events, func(name string, id uuid.UUID) aggregate.Aggregate {
+//		return newFoo(id)
+//	})
+//	foos, err := streams.Drain(context.TODO(), str, errs)
+func Build(
+	ctx context.Context,
+	events <-chan event.Event,
+	factory func(name string, id uuid.UUID) aggregate.Aggregate,
+	opts ...Option,
+) (<-chan aggregate.Aggregate, <-chan error) {
+	histories, errs := New(ctx, events, opts...)
+	return streams.Map(ctx, histories, func(h aggregate.History) aggregate.Aggregate {
+		ref := h.Aggregate()
+		a := factory(ref.Name, ref.ID)
+		h.Apply(a)
+		return a
+	}), errs
+}
+
 func (s *stream) acceptEvents() {
 	defer close(s.complete)
 	defer close(s.acceptDone)
 	defer close(s.events)
 	defer s.stopErrors()
+	defer s.stopUpstream()
+	defer s.cancel()
 
 	pending := make(map[job]bool)
+	accepted := make(map[job]bool)
+
+	maxPending := s.maxPendingGroups
+	if s.isGrouped {
+		maxPending = 0
+	}
+
+	idle, resetIdle, stopIdle := s.idleTimer()
+	defer stopIdle()
 
 	var prev job
 L:
 	for {
+		resetIdle()
+
 		select {
+		case <-idle:
+			s.outErrors <- s.timeoutError()
+			break L
 		case err, ok := <-s.inErrors:
 			if !ok {
 				s.inErrors = nil
@@ -238,12 +564,12 @@ L:
 				break L
 			}
 
+			s.eventReceived()
+
 			if s.shouldDiscard(evt) {
 				break
 			}
 
-			s.events <- evt
-
 			id, name, _ := evt.Aggregate()
 
 			j := job{
@@ -251,6 +577,23 @@ L:
 				id:   id,
 			}
 
+			if s.limit > 0 && !accepted[j] {
+				if len(accepted) >= s.limit {
+					break L
+				}
+				accepted[j] = true
+			}
+
+			if maxPending > 0 && !pending[j] && len(pending) >= maxPending {
+				s.outErrors <- fmt.Errorf("%w [max=%d]", ErrTooManyPendingGroups, maxPending)
+				// Discard the buffered aggregates, because they might be
+				// incomplete.
+				pending = nil
+				break L
+			}
+
+			s.events <- evt
+
 			pending[j] = true
 
 			if s.isGrouped && prev.name != "" && prev != j {
@@ -267,6 +610,41 @@ L:
 	}
 }
 
+// stopUpstream cancels the producer of the event stream if the Stream was
+// created with CancelUpstream.
+func (s *stream) stopUpstream() {
+	if s.cancelUpstream != nil {
+		s.cancelUpstream()
+	}
+}
+
+// idleTimer returns a channel that receives when the Stream has been waiting
+// for an event for longer than the configured Timeout, a function that
+// restarts the timer and a function that stops it. If no Timeout is
+// configured, the returned channel is nil.
+func (s *stream) idleTimer() (<-chan time.Time, func(), func()) {
+	if s.timeout <= 0 {
+		return nil, func() {}, func() {}
+	}
+
+	timer := time.NewTimer(s.timeout)
+	reset := func() {
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(s.timeout)
+	}
+
+	return timer.C, reset, func() { timer.Stop() }
+}
+
+func (s *stream) timeoutError() error {
+	return fmt.Errorf("event stream: %w [timeout=%v]", ErrTimeout, s.timeout)
+}
+
 func (s *stream) shouldDiscard(evt event.Event) bool {
 	for _, fn := range s.filters {
 		if !fn(evt) {
@@ -309,6 +687,7 @@ func (s *stream) sortEvents() {
 	defer close(s.out)
 	defer close(s.outErrors)
 	defer close(s.groupReqs)
+	defer s.finishProgress()
 
 	for j := range s.complete {
 		req := groupRequest{
@@ -322,21 +701,234 @@ func (s *stream) sortEvents() {
 			events = event.Sort(events, event.SortAggregateVersion, event.SortAsc)
 		}
 
+		if s.deduplicate {
+			events = deduplicate(events)
+		}
+
 		if s.validateConsistency {
 			a := aggregate.New(j.name, j.id)
-			if err := aggregate.ValidateConsistency(a, events); err != nil {
-				s.outErrors <- err
+			if err := aggregate.ValidateConsistency(a, events, s.consistencyOptions()...); err != nil {
+				if !s.continueOnError {
+					s.outErrors <- err
+				} else {
+					s.aggregateError(j, err)
+				}
 				continue
 			}
 		}
 
-		if !s.withSoftDeleted && softdelete.SoftDeleted(events) {
+		if !s.withSoftDeleted && softdelete.IsDeleted(events) {
 			continue
 		}
 
 		s.out <- applier{
 			job:   j,
-			apply: func(a aggregate.Aggregate) { aggregate.ApplyHistory(a, events) },
+			apply: func(a aggregate.Aggregate) { aggregate.ApplyHistory(a, events, s.consistencyOptions()...) },
+		}
+		atomic.AddInt64(&s.aggregateCount, 1)
+	}
+}
+
+// aggregateError reports the error of an aggregate to the OnAggregateError
+// function of the Stream, if provided.
+func (s *stream) aggregateError(j job, err error) {
+	if s.onAggregateError != nil {
+		s.onAggregateError(aggregate.Ref{Name: j.name, ID: j.id}, err)
+	}
+}
+
+func (s *stream) consistencyOptions() []aggregate.ConsistencyOption {
+	return []aggregate.ConsistencyOption{aggregate.AllowSkippedVersions(s.allowSkipped)}
+}
+
+// deduplicate removes events with duplicate ids from events, keeping the first
+// occurrence of each event.
+func deduplicate(events []event.Event) []event.Event {
+	seen := make(map[uuid.UUID]bool, len(events))
+	out := events[:0]
+	for _, evt := range events {
+		if seen[evt.ID()] {
+			continue
+		}
+		seen[evt.ID()] = true
+		out = append(out, evt)
+	}
+	return out
+}
+
+func (s *stream) eventReceived() {
+	n := atomic.AddInt64(&s.eventCount, 1)
+	if s.progress != nil && s.progressInterval > 0 && n%int64(s.progressInterval) == 0 {
+		s.progress <- s.currentProgress()
+	}
+}
+
+func (s *stream) currentProgress() progress {
+	return progress{
+		events:     int(atomic.LoadInt64(&s.eventCount)),
+		aggregates: int(atomic.LoadInt64(&s.aggregateCount)),
+	}
+}
+
+func (s *stream) reportProgress() {
+	defer close(s.progressDone)
+	for p := range s.progress {
+		s.onProgress(p.events, p.aggregates)
+	}
+}
+
+func (s *stream) finishProgress() {
+	if s.progress == nil {
+		return
+	}
+	s.progress <- s.currentProgress()
+	close(s.progress)
+	<-s.progressDone
+}
+
+func (s *stream) streamEvents() {
+	defer close(s.out)
+	defer close(s.outErrors)
+	defer s.stopErrors()
+	defer s.stopUpstream()
+	defer s.cancel()
+	defer s.finishProgress()
+
+	var current *streamedHistory
+	var count int
+	defer func() {
+		if current != nil {
+			close(current.events)
+		}
+	}()
+
+	finish := func() bool {
+		if current == nil {
+			return true
+		}
+		h := current
+		current = nil
+
+		h.mux.Lock()
+		applying, buffered := h.applying, h.buffered
+		h.mux.Unlock()
+		close(h.events)
+
+		var err error
+		var deleted bool
+		if applying {
+			select {
+			case <-s.ctx.Done():
+				return false
+			case <-h.done:
+			}
+			err, deleted = h.err, h.deleted
+		} else {
+			// The History has not been applied yet, so validate its buffered
+			// events on a placeholder aggregate.
+			st := h.newState()
+			a := aggregate.New(h.name, h.id)
+			for _, evt := range buffered {
+				h.applyEvent(a, evt, st)
+			}
+			err, deleted = st.err, st.deleted
+		}
+
+		if err == nil && deleted && !s.withSoftDeleted {
+			err = fmt.Errorf("%w [name=%v, id=%v]", ErrSoftDeleted, h.name, h.id)
+		}
+
+		if err == nil {
+			return true
+		}
+
+		if s.continueOnError {
+			s.aggregateError(h.job, err)
+			return true
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return false
+		case s.outErrors <- err:
+			return true
+		}
+	}
+
+	idle, resetIdle, stopIdle := s.idleTimer()
+	defer stopIdle()
+
+	for {
+		resetIdle()
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-idle:
+			if finish() {
+				select {
+				case <-s.ctx.Done():
+				case s.outErrors <- s.timeoutError():
+				}
+			}
+			return
+		case err, ok := <-s.inErrors:
+			if !ok {
+				s.inErrors = nil
+				break
+			}
+			if finish() {
+				select {
+				case <-s.ctx.Done():
+				case s.outErrors <- fmt.Errorf("event stream: %w", err):
+				}
+			}
+			return
+		case evt, ok := <-s.stream:
+			if !ok {
+				finish()
+				return
+			}
+
+			s.eventReceived()
+
+			if s.shouldDiscard(evt) {
+				break
+			}
+
+			id, name, _ := evt.Aggregate()
+			j := job{name: name, id: id}
+
+			if current == nil || current.job != j {
+				if !finish() {
+					return
+				}
+
+				if s.limit > 0 && count >= s.limit {
+					return
+				}
+				count++
+
+				current = &streamedHistory{
+					job:      j,
+					events:   make(chan event.Event),
+					validate: s.validateConsistency,
+					dedupe:   s.deduplicate,
+					cOpts:    s.consistencyOptions(),
+					done:     make(chan struct{}),
+				}
+
+				select {
+				case <-s.ctx.Done():
+					return
+				case s.out <- current:
+					atomic.AddInt64(&s.aggregateCount, 1)
+				}
+			}
+
+			if !current.push(s.ctx, evt) {
+				return
+			}
 		}
 	}
 }
@@ -348,3 +940,104 @@ func (a applier) Aggregate() aggregate.Ref {
 func (a applier) Apply(ag aggregate.Aggregate) {
 	a.apply(ag)
 }
+
+func (h *streamedHistory) Aggregate() aggregate.Ref {
+	return aggregate.Ref{Name: h.name, ID: h.id}
+}
+
+func (h *streamedHistory) Apply(a aggregate.Aggregate) {
+	h.once.Do(func() {
+		defer close(h.done)
+
+		h.mux.Lock()
+		h.applying = true
+		buffered := h.buffered
+		h.buffered = nil
+		h.mux.Unlock()
+
+		st := h.newState()
+		for _, evt := range buffered {
+			h.applyEvent(a, evt, st)
+		}
+		for evt := range h.events {
+			h.applyEvent(a, evt, st)
+		}
+
+		h.err, h.deleted = st.err, st.deleted
+	})
+}
+
+// push passes evt to the History. If the History is being applied, push blocks
+// until evt has been applied or ctx is canceled. Otherwise, evt is buffered
+// until the History is applied.
+func (h *streamedHistory) push(ctx context.Context, evt event.Event) bool {
+	h.mux.Lock()
+	if !h.applying {
+		h.buffered = append(h.buffered, evt)
+		h.mux.Unlock()
+		return true
+	}
+	h.mux.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case h.events <- evt:
+		return true
+	}
+}
+
+func (h *streamedHistory) newState() *applyState {
+	st := &applyState{}
+	if h.dedupe {
+		st.seen = make(map[uuid.UUID]bool)
+	}
+	return st
+}
+
+// applyEvent validates and applies evt to a. After a consistency error, no
+// further events are applied.
+func (h *streamedHistory) applyEvent(a aggregate.Aggregate, evt event.Event, st *applyState) {
+	if st.err != nil {
+		return
+	}
+
+	if st.seen != nil {
+		if st.seen[evt.ID()] {
+			return
+		}
+		st.seen[evt.ID()] = true
+	}
+
+	if h.validate {
+		if err := aggregate.ValidateConsistency(a, []event.Event{evt}, h.cOpts...); err != nil {
+			st.err = err
+			return
+		}
+
+		if st.prev != nil && !evt.Time().After(st.prev.Time()) {
+			st.err = &aggregate.ConsistencyError{
+				Kind:       aggregate.InconsistentTime,
+				Aggregate:  a,
+				Events:     []event.Event{st.prev, evt},
+				EventIndex: 1,
+			}
+			return
+		}
+	}
+
+	a.ApplyEvent(evt)
+
+	if c, ok := a.(aggregate.Committer); ok {
+		c.RecordChange(evt)
+		c.Commit()
+	}
+
+	if softdelete.IsDeleted([]event.Event{evt}) {
+		st.deleted = true
+	} else if data, ok := evt.Data().(aggregate.SoftRestorer); ok && data.SoftRestore() {
+		st.deleted = false
+	}
+
+	st.prev = evt
+}
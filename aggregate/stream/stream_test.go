@@ -3,6 +3,7 @@ package stream_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
 	"strings"
 	"testing"
@@ -302,6 +303,108 @@ func TestFilter(t *testing.T) {
 	}
 }
 
+func TestAllowSkippedVersions(t *testing.T) {
+	as, getAppliedEvents := xaggregate.Make(1)
+	am := xaggregate.Map(as)
+	events := xevent.Make("foo", etest.FooEventData{}, 5, xevent.ForAggregate(as...))
+	events = xevent.Shuffle(events)
+
+	skip := func(evt event.Event) bool {
+		return pick.AggregateVersion(evt) != 3
+	}
+
+	str, errs := stream.New(context.Background(), streams.New(events), stream.Filter(skip))
+	if _, err := drain(str, errs, 3*time.Second, makeFactory(am)); !aggregate.IsConsistencyError(err) {
+		t.Fatalf("stream should fail with a consistency error; got %v", err)
+	}
+
+	str, errs = stream.New(
+		context.Background(),
+		streams.New(events),
+		stream.Filter(skip),
+		stream.AllowSkippedVersions(true),
+	)
+
+	res, err := drain(str, errs, 3*time.Second, makeFactory(am))
+	if err != nil {
+		t.Fatalf("drain stream: %v", err)
+	}
+
+	if len(res) != 1 {
+		t.Fatalf("stream should return %d aggregate; got %d", 1, len(res))
+	}
+
+	if v := pick.AggregateVersion(res[0]); v != 5 {
+		t.Errorf("aggregate should be at version %d; is at version %d", 5, v)
+	}
+
+	var want []event.Event
+	for _, evt := range event.Sort(events, event.SortAggregateVersion, event.SortAsc) {
+		if skip(evt) {
+			want = append(want, evt)
+		}
+	}
+	etest.AssertEqualEvents(t, want, getAppliedEvents(pick.AggregateID(as[0])))
+}
+
+func TestDeduplicate(t *testing.T) {
+	as, getAppliedEvents := xaggregate.Make(1)
+	am := xaggregate.Map(as)
+	events := xevent.Make("foo", etest.FooEventData{}, 5, xevent.ForAggregate(as...))
+	withDuplicates := append(append([]event.Event{}, events...), events[1], events[3])
+	withDuplicates = xevent.Shuffle(withDuplicates)
+
+	es := streams.New(withDuplicates)
+	str, errs := stream.New(context.Background(), es, stream.Deduplicate(true))
+
+	res, err := drain(str, errs, 3*time.Second, makeFactory(am))
+	if err != nil {
+		t.Fatalf("drain stream: %v", err)
+	}
+
+	if len(res) != 1 {
+		t.Fatalf("stream should return %d aggregate; got %d", 1, len(res))
+	}
+
+	if v := pick.AggregateVersion(res[0]); v != len(events) {
+		t.Errorf("aggregate should be at version %d; is at version %d", len(events), v)
+	}
+
+	etest.AssertEqualEvents(t, events, getAppliedEvents(pick.AggregateID(as[0])))
+}
+
+func TestDeduplicate_streamApply(t *testing.T) {
+	as, getAppliedEvents := xaggregate.Make(1)
+	am := xaggregate.Map(as)
+	events := xevent.Make("foo", etest.FooEventData{}, 5, xevent.ForAggregate(as...))
+	withDuplicates := []event.Event{events[0], events[1], events[1], events[2], events[3], events[3], events[4]}
+
+	es := streams.New(withDuplicates)
+	str, errs := stream.New(
+		context.Background(),
+		es,
+		stream.Grouped(true),
+		stream.Sorted(true),
+		stream.StreamApply(true),
+		stream.Deduplicate(true),
+	)
+
+	res, err := drainApply(str, errs, 3*time.Second, makeFactory(am))
+	if err != nil {
+		t.Fatalf("drain stream: %v", err)
+	}
+
+	if len(res) != 1 {
+		t.Fatalf("stream should return %d aggregate; got %d", 1, len(res))
+	}
+
+	if v := pick.AggregateVersion(res[0]); v != len(events) {
+		t.Errorf("aggregate should be at version %d; is at version %d", len(events), v)
+	}
+
+	etest.AssertEqualEvents(t, events, getAppliedEvents(pick.AggregateID(as[0])))
+}
+
 func TestWithSoftDeleted(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -340,6 +443,647 @@ func TestWithSoftDeleted(t *testing.T) {
 	}
 }
 
+func TestStreamApply(t *testing.T) {
+	as, getAppliedEvents := xaggregate.Make(5)
+	am := xaggregate.Map(as)
+	events := xevent.Make("foo", etest.FooEventData{}, 10, xevent.ForAggregate(as...))
+	events = event.SortMulti(
+		events,
+		event.SortOptions{Sort: event.SortAggregateID, Dir: event.SortAsc},
+		event.SortOptions{Sort: event.SortAggregateVersion, Dir: event.SortAsc},
+	)
+
+	es := streams.New(events)
+	str, errs := stream.New(
+		context.Background(),
+		es,
+		stream.Grouped(true),
+		stream.Sorted(true),
+		stream.StreamApply(true),
+	)
+
+	res, err := drainApply(str, errs, 3*time.Second, makeFactory(am))
+	if err != nil {
+		t.Fatalf("drain stream: %v", err)
+	}
+
+	if len(res) != len(as) {
+		t.Fatalf("stream should return %d aggregates; got %d", len(as), len(res))
+	}
+
+	for _, a := range as {
+		applied := getAppliedEvents(pick.AggregateID(a))
+		etest.AssertEqualEvents(t, xevent.FilterAggregate(events, a), applied)
+
+		if v := pick.AggregateVersion(a); v != 10 {
+			t.Errorf("aggregate should have version %d; got %d", 10, v)
+		}
+	}
+}
+
+func TestStreamApply_drain(t *testing.T) {
+	as, getAppliedEvents := xaggregate.Make(5)
+	am := xaggregate.Map(as)
+	events := xevent.Make("foo", etest.FooEventData{}, 10, xevent.ForAggregate(as...))
+	events = event.SortMulti(
+		events,
+		event.SortOptions{Sort: event.SortAggregateID, Dir: event.SortAsc},
+		event.SortOptions{Sort: event.SortAggregateVersion, Dir: event.SortAsc},
+	)
+
+	str, errs := stream.New(
+		context.Background(),
+		streams.New(events),
+		stream.Grouped(true),
+		stream.Sorted(true),
+		stream.StreamApply(true),
+	)
+
+	// Histories that are not applied before the next History is received must
+	// not block the Stream.
+	res, err := drain(str, errs, 3*time.Second, makeFactory(am))
+	if err != nil {
+		t.Fatalf("drain stream: %v", err)
+	}
+
+	if len(res) != len(as) {
+		t.Fatalf("stream should return %d aggregates; got %d", len(as), len(res))
+	}
+
+	for _, a := range as {
+		etest.AssertEqualEvents(t, xevent.FilterAggregate(events, a), getAppliedEvents(pick.AggregateID(a)))
+	}
+}
+
+func TestStreamApply_softDeleted(t *testing.T) {
+	foo := test.NewFoo(uuid.New())
+	bar := test.NewFoo(uuid.New())
+	aggregate.NextEvent(foo, "foo", etest.FooEventData{})
+	aggregate.Next(foo, "soft_deleted", softDeletedEvent{})
+	aggregate.NextEvent(bar, "foo", etest.FooEventData{})
+
+	events := append(foo.AggregateChanges(), bar.AggregateChanges()...)
+	factory := func(_ string, id uuid.UUID) aggregate.Aggregate { return test.NewFoo(id) }
+
+	newStream := func(opts ...stream.Option) (<-chan aggregate.History, <-chan error) {
+		return stream.New(context.Background(), streams.New(events), append([]stream.Option{
+			stream.Grouped(true),
+			stream.Sorted(true),
+			stream.StreamApply(true),
+		}, opts...)...)
+	}
+
+	t.Run("default", func(t *testing.T) {
+		str, errs := newStream()
+		if _, err := drainApply(str, errs, 3*time.Second, factory); !errors.Is(err, stream.ErrSoftDeleted) {
+			t.Fatalf("stream should fail with %q; got %v", stream.ErrSoftDeleted, err)
+		}
+	})
+
+	t.Run("ContinueOnError", func(t *testing.T) {
+		var reported []aggregate.Ref
+		str, errs := newStream(stream.ContinueOnError(true), stream.OnAggregateError(func(ref aggregate.Ref, err error) {
+			if !errors.Is(err, stream.ErrSoftDeleted) {
+				t.Errorf("reported error should unwrap to %q; got %q", stream.ErrSoftDeleted, err)
+			}
+			reported = append(reported, ref)
+		}))
+
+		res, err := drainApply(str, errs, 3*time.Second, factory)
+		if err != nil {
+			t.Fatalf("stream should not return an error; got %q", err)
+		}
+
+		if len(res) != 2 {
+			t.Fatalf("stream should return %d aggregates; got %d", 2, len(res))
+		}
+
+		if len(reported) != 1 || reported[0].ID != foo.AggregateID() {
+			t.Fatalf("only aggregate %s should be reported as soft-deleted; got %v", foo.AggregateID(), reported)
+		}
+	})
+
+	t.Run("WithSoftDeleted", func(t *testing.T) {
+		str, errs := newStream(stream.WithSoftDeleted(true))
+		if _, err := drainApply(str, errs, 3*time.Second, factory); err != nil {
+			t.Fatalf("stream should not return an error; got %q", err)
+		}
+	})
+}
+
+func TestStreamApply_inconsistent(t *testing.T) {
+	as, getAppliedEvents := xaggregate.Make(1)
+	am := xaggregate.Map(as)
+	events := xevent.Make("foo", etest.FooEventData{}, 10, xevent.ForAggregate(as...), xevent.SkipVersion(3))
+
+	es := streams.New(events)
+	str, errs := stream.New(
+		context.Background(),
+		es,
+		stream.Grouped(true),
+		stream.Sorted(true),
+		stream.StreamApply(true),
+	)
+
+	_, err := drainApply(str, errs, 3*time.Second, makeFactory(am))
+
+	var cerr *aggregate.ConsistencyError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("stream should return an error of type %T; got %T", cerr, err)
+	}
+
+	if cerr.Event() != events[2] {
+		t.Errorf("cerr.Event should return %#v; got %#v", events[2], cerr.Event())
+	}
+
+	if applied := getAppliedEvents(pick.AggregateID(as[0])); len(applied) != 2 {
+		t.Errorf("only the events before the inconsistency should be applied; %d were applied", len(applied))
+	}
+}
+
+func TestContinueOnError(t *testing.T) {
+	good, _ := xaggregate.Make(3)
+	corrupt, _ := xaggregate.Make(1)
+	am := xaggregate.Map(append(good, corrupt...))
+
+	events := xevent.Make("foo", etest.FooEventData{}, 10, xevent.ForAggregate(good...))
+	corruptEvents := xevent.Make("foo", etest.FooEventData{}, 10, xevent.ForAggregate(corrupt...), xevent.SkipVersion(3))
+	events = append(events, corruptEvents...)
+
+	var reported []aggregate.Ref
+	var reportedErr error
+
+	es := streams.New(events)
+	str, errs := stream.New(
+		context.Background(),
+		es,
+		stream.ContinueOnError(true),
+		stream.OnAggregateError(func(ref aggregate.Ref, err error) {
+			reported = append(reported, ref)
+			reportedErr = err
+		}),
+	)
+
+	res, err := drain(str, errs, 3*time.Second, makeFactory(am))
+	if err != nil {
+		t.Fatalf("stream should not return an error; got %q", err)
+	}
+
+	if len(res) != len(good) {
+		t.Fatalf("stream should return %d aggregates; got %d", len(good), len(res))
+	}
+
+	for _, a := range res {
+		if pick.AggregateID(a) == pick.AggregateID(corrupt[0]) {
+			t.Fatalf("stream should not return the corrupt aggregate")
+		}
+		if v := pick.AggregateVersion(a); v != 10 {
+			t.Errorf("aggregate should have version %d; got %d", 10, v)
+		}
+	}
+
+	if len(reported) != 1 {
+		t.Fatalf("OnAggregateError should be called once; was called %d times", len(reported))
+	}
+
+	if reported[0].ID != pick.AggregateID(corrupt[0]) {
+		t.Errorf("OnAggregateError should be called with aggregate %s; got %s", pick.AggregateID(corrupt[0]), reported[0].ID)
+	}
+
+	var cerr *aggregate.ConsistencyError
+	if !errors.As(reportedErr, &cerr) {
+		t.Fatalf("OnAggregateError should be called with an error of type %T; got %T", cerr, reportedErr)
+	}
+}
+
+func TestContinueOnError_streamApply(t *testing.T) {
+	good, _ := xaggregate.Make(2)
+	corrupt, _ := xaggregate.Make(1)
+	am := xaggregate.Map(append(good, corrupt...))
+
+	events := xevent.Make("foo", etest.FooEventData{}, 10, xevent.ForAggregate(corrupt...), xevent.SkipVersion(3))
+	events = append(events, xevent.Make("foo", etest.FooEventData{}, 10, xevent.ForAggregate(good...))...)
+
+	var reported []aggregate.Ref
+
+	es := streams.New(events)
+	str, errs := stream.New(
+		context.Background(),
+		es,
+		stream.Grouped(true),
+		stream.Sorted(true),
+		stream.StreamApply(true),
+		stream.ContinueOnError(true),
+		stream.OnAggregateError(func(ref aggregate.Ref, err error) {
+			reported = append(reported, ref)
+		}),
+	)
+
+	res, err := drainApply(str, errs, 3*time.Second, makeFactory(am))
+	if err != nil {
+		t.Fatalf("stream should not return an error; got %q", err)
+	}
+
+	if len(res) != len(am) {
+		t.Fatalf("stream should return %d aggregates; got %d", len(am), len(res))
+	}
+
+	if len(reported) != 1 || reported[0].ID != pick.AggregateID(corrupt[0]) {
+		t.Fatalf("OnAggregateError should be called once with aggregate %s; got %v", pick.AggregateID(corrupt[0]), reported)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	as, _ := xaggregate.Make(3)
+	events := xevent.Make("foo", etest.FooEventData{}, 10, xevent.ForAggregate(as...))
+
+	str, errs := stream.Build(context.Background(), streams.New(events), func(name string, id uuid.UUID) aggregate.Aggregate {
+		return newCounter(name, id)
+	})
+
+	res, err := streams.Drain(context.Background(), str, errs)
+	if err != nil {
+		t.Fatalf("Drain() failed with %q", err)
+	}
+
+	if len(res) != len(as) {
+		t.Fatalf("stream should return %d aggregates; got %d", len(as), len(res))
+	}
+
+	for _, a := range res {
+		c, ok := a.(*counter)
+		if !ok {
+			t.Fatalf("stream should return aggregates of type %T; got %T", c, a)
+		}
+
+		if c.count != 10 {
+			t.Errorf("counter should have count %d; got %d", 10, c.count)
+		}
+
+		if v := pick.AggregateVersion(c); v != 10 {
+			t.Errorf("counter should have version %d; got %d", 10, v)
+		}
+	}
+}
+
+func TestLimit(t *testing.T) {
+	as, getAppliedEvents := xaggregate.Make(10)
+	am := xaggregate.Map(as)
+	events := xevent.Make("foo", etest.FooEventData{}, 10, xevent.ForAggregate(as...))
+	events = event.Sort(xevent.Shuffle(events), event.SortAggregateVersion, event.SortAsc)
+
+	// the Stream stops at the first event of the 4th aggregate
+	want := make(map[uuid.UUID][]event.Event)
+	for _, evt := range events {
+		id := pick.AggregateID(evt)
+		if _, ok := want[id]; !ok && len(want) == 3 {
+			break
+		}
+		want[id] = append(want[id], evt)
+	}
+
+	es := streams.New(events)
+	str, errs := stream.New(context.Background(), es, stream.Limit(3))
+
+	res, err := drain(str, errs, 3*time.Second, makeFactory(am))
+	if err != nil {
+		t.Fatalf("drain stream: %v", err)
+	}
+
+	if len(res) != 3 {
+		t.Fatalf("stream should return %d aggregates; got %d", 3, len(res))
+	}
+
+	for _, a := range res {
+		etest.AssertEqualEvents(t, want[pick.AggregateID(a)], getAppliedEvents(pick.AggregateID(a)))
+	}
+}
+
+func TestLimit_unboundedEventStream(t *testing.T) {
+	for _, grouped := range []bool{false, true} {
+		t.Run(fmt.Sprintf("Grouped(%v)", grouped), func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			events, stopped := produceEvents(ctx)
+
+			str, errs := stream.New(
+				context.Background(),
+				events,
+				stream.Grouped(grouped),
+				stream.Sorted(true),
+				stream.Limit(2),
+				stream.CancelUpstream(cancel),
+			)
+
+			res, err := drain(str, errs, 3*time.Second, func(name string, id uuid.UUID) aggregate.Aggregate {
+				return aggregate.New(name, id)
+			})
+			if err != nil {
+				t.Fatalf("drain stream: %v", err)
+			}
+
+			if len(res) != 2 {
+				t.Fatalf("stream should return %d aggregates; got %d", 2, len(res))
+			}
+
+			select {
+			case <-time.After(3 * time.Second):
+				t.Fatalf("stream should cancel the producer of the event stream")
+			case <-stopped:
+			}
+		})
+	}
+}
+
+func TestLimit_grouped(t *testing.T) {
+	as, _ := xaggregate.Make(5)
+	am := xaggregate.Map(as)
+	events := xevent.Make("foo", etest.FooEventData{}, 3, xevent.ForAggregate(as...))
+	events = event.SortMulti(
+		events,
+		event.SortOptions{Sort: event.SortAggregateID, Dir: event.SortAsc},
+		event.SortOptions{Sort: event.SortAggregateVersion, Dir: event.SortAsc},
+	)
+
+	// the event stream is never closed
+	es := make(chan event.Event, len(events))
+	for _, evt := range events {
+		es <- evt
+	}
+
+	str, errs := stream.New(context.Background(), es, stream.Grouped(true), stream.Limit(2))
+
+	res, err := drain(str, errs, 3*time.Second, makeFactory(am))
+	if err != nil {
+		t.Fatalf("drain stream: %v", err)
+	}
+
+	if len(res) != 2 {
+		t.Fatalf("stream should return %d aggregates; got %d", 2, len(res))
+	}
+}
+
+func TestLimit_negative(t *testing.T) {
+	es := streams.New(xevent.Make("foo", etest.FooEventData{}, 3))
+	str, errs := stream.New(context.Background(), es, stream.Limit(-1))
+
+	_, err := drain(str, errs, time.Second, nil)
+	if !errors.Is(err, stream.ErrNegativeLimit) {
+		t.Fatalf("stream should fail with %q; got %q", stream.ErrNegativeLimit, err)
+	}
+}
+
+func TestMaxPendingGroups(t *testing.T) {
+	as, getAppliedEvents := xaggregate.Make(5)
+	am := xaggregate.Map(as)
+	events := xevent.Make("foo", etest.FooEventData{}, 10, xevent.ForAggregate(as...))
+	events = xevent.Shuffle(events)
+
+	str, errs := stream.New(context.Background(), streams.New(events), stream.MaxPendingGroups(len(as)))
+
+	res, err := drain(str, errs, 3*time.Second, makeFactory(am))
+	if err != nil {
+		t.Fatalf("stream should not return an error; got %q", err)
+	}
+
+	if len(res) != len(as) {
+		t.Fatalf("stream should return %d aggregates; got %d", len(as), len(res))
+	}
+
+	for _, a := range as {
+		if applied := getAppliedEvents(pick.AggregateID(a)); len(applied) != 10 {
+			t.Errorf("%d events should be applied to aggregate %s; got %d", 10, pick.AggregateID(a), len(applied))
+		}
+	}
+}
+
+func TestMaxPendingGroups_exceeded(t *testing.T) {
+	as, _ := xaggregate.Make(3)
+
+	var events []event.Event
+	for _, a := range as {
+		events = append(events, xevent.Make("foo", etest.FooEventData{}, 3, xevent.ForAggregate(a))...)
+	}
+
+	// the event stream is never closed
+	es := make(chan event.Event, len(events))
+	for _, evt := range events {
+		es <- evt
+	}
+
+	str, errs := stream.New(context.Background(), es, stream.MaxPendingGroups(2))
+
+	var res []aggregate.History
+	var err error
+	for str != nil || errs != nil {
+		select {
+		case <-time.After(3 * time.Second):
+			t.Fatalf("timed out waiting for the stream to finish")
+		case e, ok := <-errs:
+			if !ok {
+				errs = nil
+				break
+			}
+			err = e
+		case h, ok := <-str:
+			if !ok {
+				str = nil
+				break
+			}
+			res = append(res, h)
+		}
+	}
+
+	if !errors.Is(err, stream.ErrTooManyPendingGroups) {
+		t.Fatalf("stream should fail with %q; got %v", stream.ErrTooManyPendingGroups, err)
+	}
+
+	if len(res) != 0 {
+		t.Fatalf("stream should not return the Histories of possibly incomplete aggregates; got %d", len(res))
+	}
+}
+
+func TestOnProgress(t *testing.T) {
+	as, _ := xaggregate.Make(10)
+	am := xaggregate.Map(as)
+	events := xevent.Make("foo", etest.FooEventData{}, 10, xevent.ForAggregate(as...))
+	events = xevent.Shuffle(events)
+
+	type progress struct{ events, aggregates int }
+	var reported []progress
+
+	es := streams.New(events)
+	str, errs := stream.New(
+		context.Background(),
+		es,
+		stream.OnProgress(func(events, aggregates int) {
+			reported = append(reported, progress{events, aggregates})
+		}),
+		stream.ProgressInterval(25),
+	)
+
+	if _, err := drain(str, errs, 3*time.Second, makeFactory(am)); err != nil {
+		t.Fatalf("drain stream: %v", err)
+	}
+
+	want := []progress{{25, 0}, {50, 0}, {75, 0}, {100, 0}, {100, 10}}
+	if len(reported) != len(want) {
+		t.Fatalf("progress should be reported %d times; was reported %d times: %v", len(want), len(reported), reported)
+	}
+
+	for i, p := range reported[:len(reported)-1] {
+		if p.events != want[i].events {
+			t.Errorf("progress #%d should report %d events; reported %d", i+1, want[i].events, p.events)
+		}
+	}
+
+	if final := reported[len(reported)-1]; final != want[len(want)-1] {
+		t.Errorf("final progress should be %v; got %v", want[len(want)-1], final)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	as, getAppliedEvents := xaggregate.Make(1)
+	am := xaggregate.Map(as)
+	events := xevent.Make("foo", etest.FooEventData{}, 3, xevent.ForAggregate(as...))
+
+	// the event stream stalls and is never closed
+	es := make(chan event.Event, len(events))
+	for _, evt := range events {
+		es <- evt
+	}
+
+	str, errs := stream.New(context.Background(), es, stream.Timeout(50*time.Millisecond))
+
+	start := time.Now()
+	res, err := drainAll(str, errs, 3*time.Second, makeFactory(am))
+	if !errors.Is(err, stream.ErrTimeout) {
+		t.Fatalf("stream should fail with %q; got %q", stream.ErrTimeout, err)
+	}
+
+	if dur := time.Since(start); dur > time.Second {
+		t.Fatalf("stream should time out after ~%v; took %v", 50*time.Millisecond, dur)
+	}
+
+	if len(res) != 1 {
+		t.Fatalf("stream should return %d aggregate; got %d", 1, len(res))
+	}
+
+	etest.AssertEqualEvents(t, events, getAppliedEvents(pick.AggregateID(as[0])))
+}
+
+func TestTimeout_streamApply(t *testing.T) {
+	as, _ := xaggregate.Make(1)
+	am := xaggregate.Map(as)
+	events := xevent.Make("foo", etest.FooEventData{}, 3, xevent.ForAggregate(as...))
+
+	es := make(chan event.Event, len(events))
+	for _, evt := range events {
+		es <- evt
+	}
+
+	str, errs := stream.New(
+		context.Background(),
+		es,
+		stream.Grouped(true),
+		stream.Sorted(true),
+		stream.StreamApply(true),
+		stream.Timeout(50*time.Millisecond),
+	)
+
+	_, err := drainApply(str, errs, 3*time.Second, makeFactory(am))
+	if !errors.Is(err, stream.ErrTimeout) {
+		t.Fatalf("stream should fail with %q; got %q", stream.ErrTimeout, err)
+	}
+}
+
+func TestTimeout_slowEvents(t *testing.T) {
+	as, _ := xaggregate.Make(1)
+	am := xaggregate.Map(as)
+	events := xevent.Make("foo", etest.FooEventData{}, 3, xevent.ForAggregate(as...))
+
+	es := make(chan event.Event)
+	go func() {
+		defer close(es)
+		for _, evt := range events {
+			time.Sleep(20 * time.Millisecond)
+			es <- evt
+		}
+	}()
+
+	str, errs := stream.New(context.Background(), es, stream.Timeout(200*time.Millisecond))
+
+	res, err := drain(str, errs, 3*time.Second, makeFactory(am))
+	if err != nil {
+		t.Fatalf("drain stream: %v", err)
+	}
+
+	if len(res) != 1 {
+		t.Fatalf("stream should return %d aggregate; got %d", 1, len(res))
+	}
+}
+
+func drainApply(
+	s <-chan aggregate.History,
+	errs <-chan error,
+	timeout time.Duration,
+	factory func(string, uuid.UUID) aggregate.Aggregate,
+) ([]aggregate.Aggregate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var as []aggregate.Aggregate
+	for {
+		select {
+		case <-ctx.Done():
+			return as, ctx.Err()
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				break
+			}
+			return as, err
+		case h, ok := <-s:
+			if !ok {
+				return as, nil
+			}
+			ref := h.Aggregate()
+			a := factory(ref.Name, ref.ID)
+			h.Apply(a)
+			as = append(as, a)
+		}
+	}
+}
+
+// produceEvents returns an event stream that produces the events of new
+// aggregates until ctx is canceled. The returned channel is closed when the
+// producer has stopped.
+func produceEvents(ctx context.Context) (<-chan event.Event, <-chan struct{}) {
+	events := make(chan event.Event)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		defer close(events)
+		now := xtime.Now()
+		for {
+			id := uuid.New()
+			for v := 1; v <= 3; v++ {
+				evt := event.New[any](
+					"foo",
+					etest.FooEventData{},
+					event.Aggregate(id, "foo", v),
+					event.Time(now.Add(time.Duration(v)*time.Millisecond)),
+				)
+				select {
+				case <-ctx.Done():
+					return
+				case events <- evt:
+				}
+			}
+		}
+	}()
+	return events, stopped
+}
+
 func drain(
 	s <-chan aggregate.History,
 	errs <-chan error,
@@ -372,12 +1116,70 @@ func drain(
 	return as, nil
 }
 
+// drainAll drains both the History and error channel until they are closed
+// and returns the built aggregates together with the first error.
+func drainAll(
+	s <-chan aggregate.History,
+	errs <-chan error,
+	timeout time.Duration,
+	factory func(string, uuid.UUID) aggregate.Aggregate,
+) ([]aggregate.Aggregate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var (
+		as       []aggregate.Aggregate
+		firstErr error
+	)
+	for s != nil || errs != nil {
+		select {
+		case <-ctx.Done():
+			return as, ctx.Err()
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				break
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+		case h, ok := <-s:
+			if !ok {
+				s = nil
+				break
+			}
+			ref := h.Aggregate()
+			a := factory(ref.Name, ref.ID)
+			h.Apply(a)
+			as = append(as, a)
+		}
+	}
+
+	return as, firstErr
+}
+
 func makeFactory(am map[uuid.UUID]aggregate.Aggregate) func(string, uuid.UUID) aggregate.Aggregate {
 	return func(_ string, id uuid.UUID) aggregate.Aggregate {
 		return am[id]
 	}
 }
 
+type counter struct {
+	*aggregate.Base
+
+	count int
+}
+
+func newCounter(name string, id uuid.UUID) *counter {
+	c := &counter{Base: aggregate.New(name, id)}
+	event.ApplyWith(c, c.increment, "foo")
+	return c
+}
+
+func (c *counter) increment(event.Of[etest.FooEventData]) {
+	c.count++
+}
+
 type softDeletedEvent struct{}
 
 func (softDeletedEvent) SoftDelete() bool { return true }
@@ -3,6 +3,7 @@ package memory
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"sync"
 	"time"
 
@@ -123,6 +124,10 @@ func (r *ModelRepository[Model, ID]) fetchWithoutAcquire(ctx context.Context, id
 // with it, and finally saves the model back to the repository. Use ensures that
 // a single model is not operated on by multiple goroutines at the same time to
 // avoid optimistic concurrency issues.
+//
+// If the model is a pointer, fn is called with a shallow copy of the stored
+// model, so that the stored model remains unchanged if fn returns an error.
+// Fields that are themselves pointers, maps or slices are not copied.
 func (r *ModelRepository[Model, ID]) Use(ctx context.Context, id ID, fn func(Model) error) error {
 	unlock, err := r.acquireLock(ctx, id)
 	if err != nil {
@@ -135,6 +140,10 @@ func (r *ModelRepository[Model, ID]) Use(ctx context.Context, id ID, fn func(Mod
 		return fmt.Errorf("fetch model: %w", err)
 	}
 
+	// Pass a copy of the model to fn so that the stored model is not modified
+	// if fn fails.
+	m = copyModel(m)
+
 	if err := fn(m); err != nil {
 		return err
 	}
@@ -146,6 +155,18 @@ func (r *ModelRepository[Model, ID]) Use(ctx context.Context, id ID, fn func(Mod
 	return nil
 }
 
+// copyModel returns a shallow copy of the value that m points to if m is a
+// pointer. Otherwise m is returned.
+func copyModel[Model any](m Model) Model {
+	v := reflect.ValueOf(m)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return m
+	}
+	c := reflect.New(v.Elem().Type())
+	c.Elem().Set(v.Elem())
+	return c.Interface().(Model)
+}
+
 // acquireLock locks the model with the given id and returns a function that
 // unlocks the model. If the model is already locked, it waits until the model
 // is unlocked. If ctx is canceled before the lock was acquired, ctx.Err() is
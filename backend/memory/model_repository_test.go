@@ -83,6 +83,39 @@ func testModelRepository_Use(t *testing.T, r *memory.ModelRepository[*uuidModel,
 	}
 }
 
+func TestModelRepository_Use_error(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := memory.NewModelRepository[*uuidModel, uuid.UUID]()
+
+	m := &uuidModel{
+		ID:  uuid.New(),
+		Foo: "foo",
+	}
+
+	if err := r.Save(ctx, m); err != nil {
+		t.Fatalf("failed to save model: %v", err)
+	}
+
+	mockError := errors.New("mock error")
+	if err := r.Use(ctx, m.ModelID(), func(m *uuidModel) error {
+		m.Foo = "bar"
+		return mockError
+	}); !errors.Is(err, mockError) {
+		t.Fatalf("Use() should fail with %q; got %q", mockError, err)
+	}
+
+	fetched, err := r.Fetch(ctx, m.ModelID())
+	if err != nil {
+		t.Fatalf("failed to fetch model: %v", err)
+	}
+
+	if fetched.Foo != "foo" {
+		t.Fatalf("Foo should be %q; is %q", "foo", fetched.Foo)
+	}
+}
+
 func TestModelRepository_Delete(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
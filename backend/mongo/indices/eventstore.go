@@ -70,19 +70,28 @@ var EventStore = EventStoreIndices{
 type EventStoreIndices struct {
 	// Core indices
 
-	// ID creates an index for the event id.
+	// ID creates a unique index for the event id. It accelerates finding and
+	// deleting events by id and resolving the AfterID cursor of queries.
 	ID mongo.IndexModel
 
-	// Name creates an index for the event name.
+	// Name creates an index for the event name. It accelerates queries that
+	// only filter by event name.
 	Name mongo.IndexModel
 
-	// NameAndTime creates a compound index for the event name and id.
+	// NameAndTime creates a compound index for the event name and time. It
+	// accelerates queries that filter by event name and sort or filter by
+	// time, like the queries of projection jobs.
 	NameAndTime mongo.IndexModel
 
-	// AggregateNameAndVersion creates a compound index for the aggregate name and version.
+	// AggregateNameAndVersion creates a compound index for the aggregate name
+	// and version. It accelerates queries that fetch the events of all
+	// aggregates with a given name.
 	AggregateNameAndVersion mongo.IndexModel
 
-	// AggregateNameAndIDAndVersion creates a compound index for the aggregate name, id, and version.
+	// AggregateNameAndIDAndVersion creates a unique compound index for the
+	// aggregate name, id, and version. It accelerates queries that fetch the
+	// event stream of a single aggregate, and prevents duplicate aggregate
+	// versions.
 	AggregateNameAndIDAndVersion mongo.IndexModel
 
 	// Edge-case indices
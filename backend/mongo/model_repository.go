@@ -30,6 +30,9 @@ type modelRepositoryOptions struct {
 	createIfNotFound bool
 	customDecoder    func(*mongo.SingleResult, any) error
 	customEncoder    func(any) (any, error)
+	versionKey       string
+	retries          int
+	useFactory       func(any) any
 }
 
 // ModelIDKey returns a ModelRepositoryOption that specifies which field of the
@@ -50,6 +53,37 @@ func ModelTransactions(tx bool) ModelRepositoryOption {
 	}
 }
 
+// ModelVersionKey returns a ModelRepositoryOption that enables optimistic
+// concurrency control using the given version field of the model. When a model
+// is saved, the repository only replaces the document if the version field of
+// the document still matches the version field of the model, and increments
+// the version field. If the document was modified in the meantime, Save
+// returns an error that unwraps to model.ErrConcurrency. A model with a version
+// of 0 (or no version field) is considered new and can only be saved if the
+// document does not exist or does not have a version yet.
+//
+// Save does not update the version field of the provided model. Fetch the
+// model again to get the current version. Use this option together with
+// ModelRetries to let Use() automatically retry on concurrency errors.
+//
+// If a custom id key is configured, the unique index created by CreateIndexes
+// is required to detect concurrent creation of new models.
+func ModelVersionKey(field string) ModelRepositoryOption {
+	return func(o *modelRepositoryOptions) {
+		o.versionKey = field
+	}
+}
+
+// ModelRetries returns a ModelRepositoryOption that makes Use() retry up to n
+// times if the model could not be saved because of a concurrency error (see
+// ModelVersionKey). On each retry, the model is fetched again and passed to
+// the provided function.
+func ModelRetries(n int) ModelRepositoryOption {
+	return func(o *modelRepositoryOptions) {
+		o.retries = n
+	}
+}
+
 // ModelDecoder returns a ModelRepositoryOption that specifies a custom decoder
 // for the model.
 func ModelDecoder[Model model.Model[ID], ID model.ID](decode func(*mongo.SingleResult, *Model) error) ModelRepositoryOption {
@@ -87,6 +121,43 @@ func ModelFactory[Model model.Model[ID], ID model.ID](factory func(ID) Model, cr
 	}
 }
 
+// QueryOption is an option for ModelRepository.Query.
+type QueryOption func(*queryOptions)
+
+type queryOptions struct {
+	sort  bson.D
+	limit int64
+}
+
+// QuerySort returns a QueryOption that sorts the queried models by the given
+// field. dir must be either 1 (ascending) or -1 (descending). Multiple QuerySort
+// options can be provided to sort by multiple fields, in the given order.
+func QuerySort(field string, dir int) QueryOption {
+	return func(o *queryOptions) {
+		o.sort = append(o.sort, bson.E{Key: field, Value: dir})
+	}
+}
+
+// QueryLimit returns a QueryOption that limits the number of queried models.
+func QueryLimit(limit int64) QueryOption {
+	return func(o *queryOptions) {
+		o.limit = limit
+	}
+}
+
+// ModelUseCreate returns a ModelRepositoryOption that makes Use() create models
+// that do not exist yet. If the model cannot be found, Use() creates the model
+// using the provided factory function, passes it to the provided function and
+// saves it. In contrast to ModelFactory, Fetch still returns model.ErrNotFound
+// for models that do not exist.
+func ModelUseCreate[Model model.Model[ID], ID model.ID](factory func(ID) Model) ModelRepositoryOption {
+	return func(o *modelRepositoryOptions) {
+		o.useFactory = func(id any) any {
+			return factory(id.(ID))
+		}
+	}
+}
+
 // NewModelRepository returns a MongoDB backed model repository.
 func NewModelRepository[Model model.Model[ID], ID model.ID](col *mongo.Collection, opts ...ModelRepositoryOption) *ModelRepository[Model, ID] {
 	var options modelRepositoryOptions
@@ -126,17 +197,155 @@ func (r *ModelRepository[Model, ID]) CreateIndexes(ctx context.Context) error {
 // Save saves the given model to the database using the MongoDB "ReplaceOne"
 // command with the upsert option set to true.
 func (r *ModelRepository[Model, ID]) Save(ctx context.Context, m Model) error {
+	repl, err := r.replaceModel(m)
+	if err != nil {
+		return err
+	}
+	return r.replaceOne(ctx, m, repl)
+}
+
+// SaveMany saves the given models to the database using "ReplaceOne"
+// operations with the upsert option set to true. Without the ModelVersionKey
+// option, the models are saved using a single ordered bulk write.
+//
+// With the ModelVersionKey option, the models are saved one by one in the
+// provided order, because a bulk write does not stop at a model with an
+// outdated version. If a model was modified concurrently, SaveMany returns an
+// error that unwraps to model.ErrConcurrency and contains the id of that
+// model. The models before it are saved, the models after it are not.
+func (r *ModelRepository[Model, ID]) SaveMany(ctx context.Context, ms ...Model) error {
+	if len(ms) == 0 {
+		return nil
+	}
+
+	repls := make([]*mongo.ReplaceOneModel, len(ms))
+	for i, m := range ms {
+		repl, err := r.replaceModel(m)
+		if err != nil {
+			return err
+		}
+		repls[i] = repl
+	}
+
+	if r.versionKey != "" {
+		for i, m := range ms {
+			if err := r.replaceOne(ctx, m, repls[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	writes := make([]mongo.WriteModel, len(repls))
+	for i, repl := range repls {
+		writes[i] = repl
+	}
+
+	if _, err := r.col.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(true)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// replaceOne executes the "ReplaceOne" operation that saves the model m.
+func (r *ModelRepository[Model, ID]) replaceOne(ctx context.Context, m Model, repl *mongo.ReplaceOneModel) error {
+	res, err := r.col.ReplaceOne(ctx, repl.Filter, repl.Replacement, options.Replace().SetUpsert(*repl.Upsert))
+	if err != nil {
+		if r.versionKey != "" && mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("%w: model %v was created concurrently: %v", model.ErrConcurrency, m.ModelID(), err)
+		}
+		return err
+	}
+
+	if r.versionKey != "" && res.MatchedCount+res.UpsertedCount == 0 {
+		return fmt.Errorf("%w: version of model %v is outdated", model.ErrConcurrency, m.ModelID())
+	}
+
+	return nil
+}
+
+// replaceModel returns the "ReplaceOne" operation that saves the given model.
+func (r *ModelRepository[Model, ID]) replaceModel(m Model) (*mongo.ReplaceOneModel, error) {
 	var replacement any = m
 	if r.customEncoder != nil {
 		repl, err := r.customEncoder(m)
 		if err != nil {
-			return fmt.Errorf("custom encoder: %w", err)
+			return nil, fmt.Errorf("custom encoder: %w", err)
 		}
 		replacement = repl
 	}
 
-	_, err := r.col.ReplaceOne(ctx, bson.D{{Key: r.key, Value: m.ModelID()}}, replacement, options.Replace().SetUpsert(true))
-	return err
+	if r.versionKey == "" {
+		return mongo.NewReplaceOneModel().
+			SetFilter(bson.D{{Key: r.key, Value: m.ModelID()}}).
+			SetReplacement(replacement).
+			SetUpsert(true), nil
+	}
+
+	b, err := bson.Marshal(replacement)
+	if err != nil {
+		return nil, fmt.Errorf("marshal model: %w", err)
+	}
+
+	var doc bson.D
+	if err := bson.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("unmarshal model: %w", err)
+	}
+
+	version, err := r.incrementVersion(&doc)
+	if err != nil {
+		return nil, err
+	}
+
+	// New models are upserted. If the document was created concurrently, the
+	// upsert fails with a duplicate key error.
+	if version == 0 {
+		return mongo.NewReplaceOneModel().
+			SetFilter(bson.D{
+				{Key: r.key, Value: m.ModelID()},
+				{Key: r.versionKey, Value: bson.D{{Key: "$in", Value: bson.A{nil, 0}}}},
+			}).
+			SetReplacement(doc).
+			SetUpsert(true), nil
+	}
+
+	return mongo.NewReplaceOneModel().
+		SetFilter(bson.D{
+			{Key: r.key, Value: m.ModelID()},
+			{Key: r.versionKey, Value: version},
+		}).
+		SetReplacement(doc).
+		SetUpsert(false), nil
+}
+
+// incrementVersion increments the version field of the given document and
+// returns the version before the increment.
+func (r *ModelRepository[Model, ID]) incrementVersion(doc *bson.D) (int64, error) {
+	for i, elem := range *doc {
+		if elem.Key != r.versionKey {
+			continue
+		}
+
+		var version int64
+		switch v := elem.Value.(type) {
+		case int32:
+			version = int64(v)
+		case int64:
+			version = v
+		case nil:
+		default:
+			return 0, fmt.Errorf("version field %q must be an integer; is %T", r.versionKey, elem.Value)
+		}
+
+		(*doc)[i].Value = version + 1
+
+		return version, nil
+	}
+
+	*doc = append(*doc, bson.E{Key: r.versionKey, Value: int64(1)})
+
+	return 0, nil
 }
 
 // Fetch fetches the given model from the database. If the model cannot be found,
@@ -165,6 +374,56 @@ func (r *ModelRepository[Model, ID]) Fetch(ctx context.Context, id ID) (Model, e
 	return m, nil
 }
 
+// Query returns the models that match the given filter. If no models match the
+// filter, an empty slice is returned.
+//
+//	var repo *mongo.ModelRepository[*Model, uuid.UUID]
+//	models, err := repo.Query(
+//		context.TODO(),
+//		bson.M{"status": "active"},
+//		mongo.QuerySort("createdAt", -1),
+//		mongo.QueryLimit(10),
+//	)
+func (r *ModelRepository[Model, ID]) Query(ctx context.Context, filter bson.M, opts ...QueryOption) ([]Model, error) {
+	var cfg queryOptions
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	findOpts := options.Find()
+	if len(cfg.sort) > 0 {
+		findOpts.SetSort(cfg.sort)
+	}
+	if cfg.limit > 0 {
+		findOpts.SetLimit(cfg.limit)
+	}
+
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	cur, err := r.col.Find(ctx, filter, findOpts)
+	if err != nil {
+		return nil, fmt.Errorf("mongo: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	out := make([]Model, 0)
+	for cur.Next(ctx) {
+		var m Model
+		if err := r.decodeCursor(cur, &m); err != nil {
+			return out, fmt.Errorf("decode model: %w", err)
+		}
+		out = append(out, m)
+	}
+
+	if err := cur.Err(); err != nil {
+		return out, fmt.Errorf("mongo cursor: %w", err)
+	}
+
+	return out, nil
+}
+
 func (r *ModelRepository[Model, ID]) decode(res *mongo.SingleResult, m any) error {
 	if r.customDecoder != nil {
 		return r.customDecoder(res, m)
@@ -172,11 +431,31 @@ func (r *ModelRepository[Model, ID]) decode(res *mongo.SingleResult, m any) erro
 	return res.Decode(m)
 }
 
+func (r *ModelRepository[Model, ID]) decodeCursor(cur *mongo.Cursor, m any) error {
+	if r.customDecoder != nil {
+		return r.customDecoder(mongo.NewSingleResultFromDocument(cur.Current, nil, nil), m)
+	}
+	return cur.Decode(m)
+}
+
 // Use fetches the given model from the database, passes the model to the
 // provided function and finally saves the model back to the database.
 // If the ModelTransactions option is set to true, the operation is done within
-// a MongoDB transaction (must be supported by your MongoDB cluster).
+// a MongoDB transaction (must be supported by your MongoDB cluster). If the
+// ModelUseCreate option is used, models that do not exist yet are created. If
+// the ModelRetries option is used, Use retries the operation if the model could
+// not be saved because of a concurrency error.
 func (r *ModelRepository[Model, ID]) Use(ctx context.Context, id ID, fn func(Model) error) error {
+	var err error
+	for i := 0; i <= r.retries; i++ {
+		if err = r.use(ctx, id, fn); !errors.Is(err, model.ErrConcurrency) {
+			return err
+		}
+	}
+	return err
+}
+
+func (r *ModelRepository[Model, ID]) use(ctx context.Context, id ID, fn func(Model) error) error {
 	return r.col.Database().Client().UseSession(ctx, func(ctx mongo.SessionContext) error {
 		abort := func(txError error) error {
 			if r.transactions {
@@ -195,10 +474,13 @@ func (r *ModelRepository[Model, ID]) Use(ctx context.Context, id ID, fn func(Mod
 
 		m, err := r.Fetch(ctx, id)
 		if err != nil {
-			if err := abort(err); err != nil {
-				return err
+			if !errors.Is(err, model.ErrNotFound) || r.useFactory == nil {
+				if err := abort(err); err != nil {
+					return err
+				}
+				return fmt.Errorf("fetch model: %w", err)
 			}
-			return fmt.Errorf("fetch model: %w", err)
+			m = r.useFactory(id).(Model)
 		}
 
 		if err := fn(m); err != nil {
@@ -227,3 +509,19 @@ func (r *ModelRepository[Model, ID]) Delete(ctx context.Context, m Model) error
 	_, err := r.col.DeleteOne(ctx, bson.D{{Key: r.key, Value: m.ModelID()}})
 	return err
 }
+
+// DeleteMany deletes the given models from the database using a single
+// ordered bulk write of "DeleteOne" operations.
+func (r *ModelRepository[Model, ID]) DeleteMany(ctx context.Context, ms ...Model) error {
+	if len(ms) == 0 {
+		return nil
+	}
+
+	writes := make([]mongo.WriteModel, len(ms))
+	for i, m := range ms {
+		writes[i] = mongo.NewDeleteOneModel().SetFilter(bson.D{{Key: r.key, Value: m.ModelID()}})
+	}
+
+	_, err := r.col.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(true))
+	return err
+}
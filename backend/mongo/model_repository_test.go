@@ -5,12 +5,15 @@ package mongo_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/modernice/goes/backend/mongo"
 	"github.com/modernice/goes/persistence/model"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	gomongo "go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -91,6 +94,53 @@ func testModelRepository_Use(t *testing.T, r *mongo.ModelRepository[*basicModel,
 	}
 }
 
+func TestModelRepository_Use_ModelUseCreate(t *testing.T) {
+	col := connect(t)
+	testModelRepository_Use_ModelUseCreate(t, mongo.NewModelRepository[*basicModel, primitive.ObjectID](
+		col,
+		mongo.ModelUseCreate(newBasicModel),
+	))
+}
+
+func TestModelRepository_Use_ModelUseCreate_Transaction(t *testing.T) {
+	col := connect(t)
+	testModelRepository_Use_ModelUseCreate(t, mongo.NewModelRepository[*basicModel, primitive.ObjectID](
+		col,
+		mongo.ModelUseCreate(newBasicModel),
+		mongo.ModelTransactions(true),
+	))
+}
+
+func testModelRepository_Use_ModelUseCreate(t *testing.T, r *mongo.ModelRepository[*basicModel, primitive.ObjectID]) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	id := primitive.NewObjectID()
+
+	if _, err := r.Fetch(ctx, id); !errors.Is(err, model.ErrNotFound) {
+		t.Fatalf("Fetch() should fail with %q for a model that does not exist in the database; got %q", model.ErrNotFound, err)
+	}
+
+	if err := r.Use(ctx, id, func(m *basicModel) error {
+		if m.Foo != "created" {
+			t.Fatalf("Foo should be %q; is %q", "created", m.Foo)
+		}
+		m.Foo = "bar"
+		return nil
+	}); err != nil {
+		t.Fatalf("Use() failed with %q", err)
+	}
+
+	fetched, err := r.Fetch(ctx, id)
+	if err != nil {
+		t.Fatalf("failed to fetch model: %v", err)
+	}
+
+	if fetched.Foo != "bar" {
+		t.Fatalf("Foo should be %q; is %q", "bar", fetched.Foo)
+	}
+}
+
 func TestModelRepository_Delete(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -117,6 +167,46 @@ func TestModelRepository_Delete(t *testing.T) {
 	}
 }
 
+func TestModelRepository_SaveMany_DeleteMany(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	col := connect(t)
+	r := mongo.NewModelRepository[*uuidModel, uuid.UUID](col, mongo.ModelIDKey("customid"))
+
+	models := make([]*uuidModel, 100)
+	for i := range models {
+		models[i] = &uuidModel{ID: uuid.New(), Foo: fmt.Sprintf("foo-%d", i)}
+	}
+
+	if err := r.SaveMany(ctx, models...); err != nil {
+		t.Fatalf("SaveMany() failed with %q", err)
+	}
+
+	for _, m := range []*uuidModel{models[0], models[49], models[99]} {
+		fetched, err := r.Fetch(ctx, m.ModelID())
+		if err != nil {
+			t.Fatalf("Fetch() failed with %q", err)
+		}
+
+		if fetched.Foo != m.Foo {
+			t.Fatalf("Foo should be %q; is %q", m.Foo, fetched.Foo)
+		}
+	}
+
+	if err := r.DeleteMany(ctx, models[:50]...); err != nil {
+		t.Fatalf("DeleteMany() failed with %q", err)
+	}
+
+	if _, err := r.Fetch(ctx, models[0].ModelID()); !errors.Is(err, model.ErrNotFound) {
+		t.Fatalf("fetching a deleted model should return %q; got %q", model.ErrNotFound, err)
+	}
+
+	if _, err := r.Fetch(ctx, models[50].ModelID()); err != nil {
+		t.Fatalf("Fetch() failed with %q", err)
+	}
+}
+
 func TestModelRepository_CustomID(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -224,6 +314,220 @@ func TestModelRepository_Fetch_ModelFactory_CreateIfNotFound(t *testing.T) {
 	}
 }
 
+func TestModelVersionKey(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	col := connect(t)
+	r := mongo.NewModelRepository[*versionedModel, primitive.ObjectID](col, mongo.ModelVersionKey("version"))
+
+	m := &versionedModel{ID: primitive.NewObjectID(), Foo: "foo"}
+
+	if err := r.Save(ctx, m); err != nil {
+		t.Fatalf("failed to save model: %v", err)
+	}
+
+	// saving a new model again should fail because it now has a version
+	if err := r.Save(ctx, m); !errors.Is(err, model.ErrConcurrency) {
+		t.Fatalf("Save() should fail with %q for a stale model; got %q", model.ErrConcurrency, err)
+	}
+
+	first, err := r.Fetch(ctx, m.ModelID())
+	if err != nil {
+		t.Fatalf("failed to fetch model: %v", err)
+	}
+
+	if first.Version != 1 {
+		t.Fatalf("Version should be %d; is %d", 1, first.Version)
+	}
+
+	second, err := r.Fetch(ctx, m.ModelID())
+	if err != nil {
+		t.Fatalf("failed to fetch model: %v", err)
+	}
+
+	first.Foo = "bar"
+	if err := r.Save(ctx, first); err != nil {
+		t.Fatalf("failed to save model: %v", err)
+	}
+
+	second.Foo = "baz"
+	if err := r.Save(ctx, second); !errors.Is(err, model.ErrConcurrency) {
+		t.Fatalf("Save() should fail with %q for a stale model; got %q", model.ErrConcurrency, err)
+	}
+
+	fetched, err := r.Fetch(ctx, m.ModelID())
+	if err != nil {
+		t.Fatalf("failed to fetch model: %v", err)
+	}
+
+	if fetched.Foo != "bar" {
+		t.Fatalf("Foo should be %q; is %q", "bar", fetched.Foo)
+	}
+
+	if fetched.Version != 2 {
+		t.Fatalf("Version should be %d; is %d", 2, fetched.Version)
+	}
+}
+
+func TestModelVersionKey_SaveMany(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	col := connect(t)
+	r := mongo.NewModelRepository[*versionedModel, primitive.ObjectID](col, mongo.ModelVersionKey("version"))
+
+	models := []*versionedModel{
+		{ID: primitive.NewObjectID(), Foo: "foo"},
+		{ID: primitive.NewObjectID(), Foo: "foo"},
+		{ID: primitive.NewObjectID(), Foo: "foo"},
+	}
+
+	if err := r.SaveMany(ctx, models...); err != nil {
+		t.Fatalf("SaveMany() failed with %q", err)
+	}
+
+	fetched := make([]*versionedModel, len(models))
+	for i, m := range models {
+		var err error
+		if fetched[i], err = r.Fetch(ctx, m.ModelID()); err != nil {
+			t.Fatalf("failed to fetch model: %v", err)
+		}
+		fetched[i].Foo = "bar"
+	}
+
+	// simulate a concurrent update of the second model
+	concurrent, err := r.Fetch(ctx, models[1].ModelID())
+	if err != nil {
+		t.Fatalf("failed to fetch model: %v", err)
+	}
+	concurrent.Foo = "baz"
+	if err := r.Save(ctx, concurrent); err != nil {
+		t.Fatalf("failed to save model: %v", err)
+	}
+
+	err = r.SaveMany(ctx, fetched...)
+	if !errors.Is(err, model.ErrConcurrency) {
+		t.Fatalf("SaveMany() should fail with %q for a stale model; got %q", model.ErrConcurrency, err)
+	}
+
+	if !strings.Contains(err.Error(), models[1].ModelID().String()) {
+		t.Fatalf("error should contain the id of the stale model %v; got %q", models[1].ModelID(), err)
+	}
+
+	for i, want := range []string{"bar", "baz", "foo"} {
+		m, err := r.Fetch(ctx, models[i].ModelID())
+		if err != nil {
+			t.Fatalf("failed to fetch model: %v", err)
+		}
+		if m.Foo != want {
+			t.Errorf("Foo of model %d should be %q; is %q", i, want, m.Foo)
+		}
+	}
+}
+
+func TestModelRetries(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	col := connect(t)
+	r := mongo.NewModelRepository[*versionedModel, primitive.ObjectID](col, mongo.ModelVersionKey("version"), mongo.ModelRetries(1))
+
+	m := &versionedModel{ID: primitive.NewObjectID(), Foo: "foo"}
+
+	if err := r.Save(ctx, m); err != nil {
+		t.Fatalf("failed to save model: %v", err)
+	}
+
+	var calls int
+	if err := r.Use(ctx, m.ModelID(), func(m *versionedModel) error {
+		calls++
+		if calls > 1 {
+			m.Foo = "bar"
+			return nil
+		}
+
+		// simulate a concurrent update
+		concurrent, err := r.Fetch(ctx, m.ModelID())
+		if err != nil {
+			return err
+		}
+		concurrent.Foo = "baz"
+		return r.Save(ctx, concurrent)
+	}); err != nil {
+		t.Fatalf("Use() failed with %q", err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("Use() should call the function %d times; called %d times", 2, calls)
+	}
+
+	fetched, err := r.Fetch(ctx, m.ModelID())
+	if err != nil {
+		t.Fatalf("failed to fetch model: %v", err)
+	}
+
+	if fetched.Foo != "bar" {
+		t.Fatalf("Foo should be %q; is %q", "bar", fetched.Foo)
+	}
+
+	if fetched.Version != 3 {
+		t.Fatalf("Version should be %d; is %d", 3, fetched.Version)
+	}
+}
+
+func TestModelRepository_Query(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	col := connect(t)
+	r := mongo.NewModelRepository[*queryModel, primitive.ObjectID](col)
+
+	tag := uuid.NewString()
+	models := []*queryModel{
+		{ID: primitive.NewObjectID(), Tag: tag, Status: "active", Rank: 2},
+		{ID: primitive.NewObjectID(), Tag: tag, Status: "inactive", Rank: 1},
+		{ID: primitive.NewObjectID(), Tag: tag, Status: "active", Rank: 3},
+		{ID: primitive.NewObjectID(), Tag: tag, Status: "active", Rank: 1},
+	}
+
+	for _, m := range models {
+		if err := r.Save(ctx, m); err != nil {
+			t.Fatalf("failed to save model: %v", err)
+		}
+	}
+
+	result, err := r.Query(ctx, bson.M{"tag": tag, "status": "active"}, mongo.QuerySort("rank", -1), mongo.QueryLimit(2))
+	if err != nil {
+		t.Fatalf("Query() failed with %q", err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("Query() should return %d models; got %d", 2, len(result))
+	}
+
+	if result[0].ModelID() != models[2].ModelID() || result[1].ModelID() != models[0].ModelID() {
+		t.Fatalf("Query() returned the wrong models: %v", result)
+	}
+}
+
+func TestModelRepository_Query_empty(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	col := connect(t)
+	r := mongo.NewModelRepository[*queryModel, primitive.ObjectID](col)
+
+	result, err := r.Query(ctx, bson.M{"tag": uuid.NewString()})
+	if err != nil {
+		t.Fatalf("Query() failed with %q", err)
+	}
+
+	if result == nil || len(result) != 0 {
+		t.Fatalf("Query() should return an empty slice; got %v", result)
+	}
+}
+
 func connect(t *testing.T) *gomongo.Collection {
 	client, err := gomongo.Connect(context.Background(), options.Client().ApplyURI(os.Getenv("MONGOMODEL_URL")))
 	if err != nil {
@@ -242,10 +546,35 @@ type basicModel struct {
 	Foo string
 }
 
+func newBasicModel(id primitive.ObjectID) *basicModel {
+	return &basicModel{ID: id, Foo: "created"}
+}
+
 func (m basicModel) ModelID() primitive.ObjectID {
 	return m.ID
 }
 
+type versionedModel struct {
+	ID      primitive.ObjectID `bson:"_id"`
+	Foo     string
+	Version int `bson:"version"`
+}
+
+func (m versionedModel) ModelID() primitive.ObjectID {
+	return m.ID
+}
+
+type queryModel struct {
+	ID     primitive.ObjectID `bson:"_id"`
+	Tag    string             `bson:"tag"`
+	Status string             `bson:"status"`
+	Rank   int                `bson:"rank"`
+}
+
+func (m queryModel) ModelID() primitive.ObjectID {
+	return m.ID
+}
+
 type uuidModel struct {
 	ID  uuid.UUID `bson:"customid"`
 	Foo string
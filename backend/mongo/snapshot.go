@@ -100,7 +100,7 @@ func (s *SnapshotStore) Save(ctx context.Context, snap snapshot.Snapshot) error
 		AggregateVersion: snap.AggregateVersion(),
 		Time:             snap.Time(),
 		TimeNano:         snap.Time().UnixNano(),
-		Data:             snap.State(),
+		Data:             snapshot.Raw(snap),
 	}
 
 	if _, err := s.col.ReplaceOne(ctx, bson.D{
@@ -198,6 +198,12 @@ func (s *SnapshotStore) Query(ctx context.Context, q snapshot.Query) (<-chan sna
 	filter := makeSnapshotFilter(q)
 	opts := options.Find()
 	applySnapshotSortings(opts, q.Sortings()...)
+	if offset := q.Offset(); offset > 0 {
+		opts.SetSkip(int64(offset))
+	}
+	if limit := q.Limit(); limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
 	cur, err := s.col.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, nil, fmt.Errorf("mongo: %w", err)
@@ -236,6 +242,21 @@ func (s *SnapshotStore) Query(ctx context.Context, q snapshot.Query) (<-chan sna
 	return out, outErrs, nil
 }
 
+// Count returns the number of Snapshots in the database that fit the given
+// Query.
+func (s *SnapshotStore) Count(ctx context.Context, q snapshot.Query) (int, error) {
+	if err := s.connectOnce(ctx); err != nil {
+		return 0, fmt.Errorf("connect: %w", err)
+	}
+
+	count, err := s.col.CountDocuments(ctx, makeSnapshotFilter(q))
+	if err != nil {
+		return 0, fmt.Errorf("mongo: %w", err)
+	}
+
+	return int(count), nil
+}
+
 // Delete deletes a Snapshot from the database.
 func (s *SnapshotStore) Delete(ctx context.Context, snap snapshot.Snapshot) error {
 	if _, err := s.col.DeleteOne(ctx, bson.D{
@@ -248,6 +269,107 @@ func (s *SnapshotStore) Delete(ctx context.Context, snap snapshot.Snapshot) erro
 	return nil
 }
 
+// Prune deletes all but the `keep` most recent Snapshots of the aggregate with
+// the given name and UUID and returns the number of deleted Snapshots.
+func (s *SnapshotStore) Prune(ctx context.Context, name string, id uuid.UUID, keep int) (int, error) {
+	if keep < 0 {
+		return 0, snapshot.ErrNegativeKeep
+	}
+
+	if err := s.connectOnce(ctx); err != nil {
+		return 0, fmt.Errorf("connect: %w", err)
+	}
+
+	return s.prune(ctx, name, id, keep)
+}
+
+// PruneAll deletes all but the `keep` most recent Snapshots of every aggregate
+// in the database and returns the total number of deleted Snapshots.
+func (s *SnapshotStore) PruneAll(ctx context.Context, keep int) (int, error) {
+	if keep < 0 {
+		return 0, snapshot.ErrNegativeKeep
+	}
+
+	if err := s.connectOnce(ctx); err != nil {
+		return 0, fmt.Errorf("connect: %w", err)
+	}
+
+	cur, err := s.col.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{
+				{Key: "aggregateName", Value: "$aggregateName"},
+				{Key: "aggregateId", Value: "$aggregateId"},
+			}},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+		{{Key: "$match", Value: bson.D{
+			{Key: "count", Value: bson.D{{Key: "$gt", Value: keep}}},
+		}}},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("mongo: %w", err)
+	}
+
+	var groups []struct {
+		ID struct {
+			AggregateName string    `bson:"aggregateName"`
+			AggregateID   uuid.UUID `bson:"aggregateId"`
+		} `bson:"_id"`
+	}
+	if err := cur.All(ctx, &groups); err != nil {
+		return 0, fmt.Errorf("mongo cursor: %w", err)
+	}
+
+	var deleted int
+	for _, g := range groups {
+		n, err := s.prune(ctx, g.ID.AggregateName, g.ID.AggregateID, keep)
+		deleted += n
+		if err != nil {
+			return deleted, fmt.Errorf("prune %s(%s): %w", g.ID.AggregateName, g.ID.AggregateID, err)
+		}
+	}
+
+	return deleted, nil
+}
+
+func (s *SnapshotStore) prune(ctx context.Context, name string, id uuid.UUID, keep int) (int, error) {
+	cur, err := s.col.Find(ctx, bson.D{
+		{Key: "aggregateName", Value: name},
+		{Key: "aggregateId", Value: id},
+	}, options.Find().
+		SetSort(bson.D{{Key: "aggregateVersion", Value: -1}}).
+		SetSkip(int64(keep)).
+		SetProjection(bson.D{{Key: "aggregateVersion", Value: 1}}))
+	if err != nil {
+		return 0, fmt.Errorf("mongo: %w", err)
+	}
+
+	var entries []snapshotEntry
+	if err := cur.All(ctx, &entries); err != nil {
+		return 0, fmt.Errorf("mongo cursor: %w", err)
+	}
+
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	versions := make([]int, len(entries))
+	for i, e := range entries {
+		versions[i] = e.AggregateVersion
+	}
+
+	res, err := s.col.DeleteMany(ctx, bson.D{
+		{Key: "aggregateName", Value: name},
+		{Key: "aggregateId", Value: id},
+		{Key: "aggregateVersion", Value: bson.D{{Key: "$in", Value: versions}}},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("mongo: %w", err)
+	}
+
+	return int(res.DeletedCount), nil
+}
+
 // Connect establishes the connection to the underlying MongoDB and returns the
 // mongo.Client. Connect doesn't need to be called manually as it's called
 // automatically on the first call to s.Save, s.Latest, s.Version, s.Query or
@@ -18,11 +18,26 @@ import (
 	"github.com/modernice/goes/event/query/version"
 	"github.com/modernice/goes/helper/pick"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/x/mongo/driver"
 )
 
+// DefaultInsertBatchSize is the default maximum number of events that Insert
+// inserts into the database with a single command.
+const DefaultInsertBatchSize = 500
+
+// illegalOperationCode is the error code that MongoDB returns when a
+// transaction is started on a standalone server.
+const illegalOperationCode = 20
+
+var (
+	// ErrTransactionsNotSupported is returned by InsertAtomic if the MongoDB
+	// deployment does not support transactions.
+	ErrTransactionsNotSupported = errors.New("transactions are not supported")
+)
+
 // EventStore is the MongoDB event.Store.
 type EventStore struct {
 	enc               codec.Encoding
@@ -32,6 +47,7 @@ type EventStore struct {
 	statesCol         string
 	transactions      bool
 	validateVersions  bool
+	insertBatchSize   int
 	additionalIndices []mongo.IndexModel
 
 	client  *mongo.Client
@@ -109,6 +125,8 @@ type entry struct {
 	AggregateName    string       `bson:"aggregateName"`
 	AggregateID      uuid.UUID    `bson:"aggregateId"`
 	AggregateVersion int          `bson:"aggregateVersion"`
+	CausationID      uuid.UUID    `bson:"causationId"`
+	CorrelationID    uuid.UUID    `bson:"correlationId"`
 	Data             []byte       `bson:"data"`
 }
 
@@ -174,6 +192,19 @@ func ValidateVersions(v bool) EventStoreOption {
 	}
 }
 
+// InsertBatchSize returns an EventStoreOption that configures the maximum
+// number of events that Insert inserts into the database with a single
+// command. Larger inserts are split into batches that are inserted one after
+// another, so that a single insert does not exceed the size and operation
+// limits of MongoDB. A size <= 0 disables batching.
+//
+// Defaults to DefaultInsertBatchSize.
+func InsertBatchSize(n int) EventStoreOption {
+	return func(s *EventStore) {
+		s.insertBatchSize = n
+	}
+}
+
 // WithIndices returns an EventStoreOption that creates additional indices for
 // the event collection. Can be used to create builtin edge-case indices:
 //	WithIndices(indices.EventStore.NameAndVersion)
@@ -184,10 +215,21 @@ func WithIndices(models ...mongo.IndexModel) EventStoreOption {
 }
 
 // NewEventStore returns a MongoDB event.Store.
+//
+// The order of insertion that is used for the AfterID cursor of a query is
+// the order of the ObjectIDs of the stored documents. ObjectIDs are ordered by
+// their creation time with second precision, then by a per-process counter.
+// Events that are inserted by a single process are therefore returned in
+// their order of insertion, but an event that another process inserts within
+// the same second as the cursor event may be ordered before the cursor and
+// is then never returned after it. Consumers that read the events of multiple
+// writers should resume from the sort key of the last event instead (see
+// query.Resume), which does not depend on the order of insertion.
 func NewEventStore(enc codec.Encoding, opts ...EventStoreOption) *EventStore {
 	s := EventStore{
 		enc:              enc,
 		validateVersions: true,
+		insertBatchSize:  DefaultInsertBatchSize,
 	}
 	for _, opt := range opts {
 		opt(&s)
@@ -236,8 +278,33 @@ func (s *EventStore) StateCollection() *mongo.Collection {
 	return s.states
 }
 
-// Insert saves the given events into the database.
+// Insert saves the given events into the database. Events are inserted in
+// batches of the configured InsertBatchSize. If the insertion of a batch
+// fails, the remaining batches are not inserted and the returned error
+// contains the index of the failed batch. Without transactions, the batches
+// that were inserted before the failed batch remain in the database.
 func (s *EventStore) Insert(ctx context.Context, events ...event.Event) error {
+	return s.insertSession(ctx, events, s.transactions)
+}
+
+// InsertAtomic saves the given events into the database within a transaction,
+// regardless of the Transactions option, so that either all events are
+// inserted or none of them, even if the events belong to different
+// aggregates. Transactions are only supported by replica sets and sharded
+// clusters. If MongoDB does not support transactions, InsertAtomic returns an
+// error that unwraps to ErrTransactionsNotSupported.
+func (s *EventStore) InsertAtomic(ctx context.Context, events ...event.Event) error {
+	err := s.insertSession(ctx, events, true)
+
+	var cmdError mongo.CommandError
+	if errors.As(err, &cmdError) && cmdError.Code == illegalOperationCode {
+		return fmt.Errorf("%w: %v", ErrTransactionsNotSupported, err)
+	}
+
+	return err
+}
+
+func (s *EventStore) insertSession(ctx context.Context, events []event.Event, transaction bool) error {
 	if err := s.connectOnce(ctx); err != nil {
 		return fmt.Errorf("connect: %w", err)
 	}
@@ -255,7 +322,7 @@ func (s *EventStore) Insert(ctx context.Context, events ...event.Event) error {
 			}
 		}()
 
-		if s.transactions {
+		if transaction {
 			if err := ctx.StartTransaction(); err != nil {
 				return fmt.Errorf("start transaction: %w", err)
 			}
@@ -267,7 +334,7 @@ func (s *EventStore) Insert(ctx context.Context, events ...event.Event) error {
 		}
 
 		if err := s.insert(ctx, events); err != nil {
-			if s.transactions {
+			if transaction {
 				if abortError := ctx.AbortTransaction(ctx); abortError != nil {
 					return fmt.Errorf("abort transaction: %w", abortError)
 				}
@@ -276,7 +343,7 @@ func (s *EventStore) Insert(ctx context.Context, events ...event.Event) error {
 		}
 
 		if err := s.updateState(ctx, st, events); err != nil {
-			if s.transactions {
+			if transaction {
 				if abortError := ctx.AbortTransaction(ctx); abortError != nil {
 					return fmt.Errorf("abort transaction: %w", abortError)
 				}
@@ -284,7 +351,7 @@ func (s *EventStore) Insert(ctx context.Context, events ...event.Event) error {
 			return fmt.Errorf("update state: %w", err)
 		}
 
-		if s.transactions {
+		if transaction {
 			if err := ctx.CommitTransaction(ctx); err != nil {
 				return fmt.Errorf("commit transaction: %w", err)
 			}
@@ -361,11 +428,36 @@ func (s *EventStore) insert(ctx context.Context, events []event.Event) error {
 		return nil
 	}
 
+	docs, err := s.makeEntries(events)
+	if err != nil {
+		return err
+	}
+
+	size := s.insertBatchSize
+	if size <= 0 {
+		size = len(docs)
+	}
+
+	for batch, start := 0, 0; start < len(docs); batch, start = batch+1, start+size {
+		end := start + size
+		if end > len(docs) {
+			end = len(docs)
+		}
+
+		if _, err := s.entries.InsertMany(ctx, docs[start:end]); err != nil {
+			return fmt.Errorf("mongo: %w [batch=%d]", err, batch)
+		}
+	}
+
+	return nil
+}
+
+func (s *EventStore) makeEntries(events []event.Event) ([]any, error) {
 	docs := make([]any, len(events))
 	for i, evt := range events {
 		var data bytes.Buffer
 		if err := s.enc.Encode(&data, evt.Name(), evt.Data()); err != nil {
-			return fmt.Errorf("encode %q event data: %w", evt.Name(), err)
+			return nil, fmt.Errorf("encode %q event data: %w", evt.Name(), err)
 		}
 		id, name, v := evt.Aggregate()
 		docs[i] = entry{
@@ -376,15 +468,109 @@ func (s *EventStore) insert(ctx context.Context, events []event.Event) error {
 			AggregateName:    name,
 			AggregateID:      id,
 			AggregateVersion: v,
+			CausationID:      event.CausationID(evt),
+			CorrelationID:    event.CorrelationID(evt),
 			Data:             data.Bytes(),
 		}
 	}
-	if _, err := s.entries.InsertMany(ctx, docs); err != nil {
-		return fmt.Errorf("mongo: %w", err)
+	return docs, nil
+}
+
+// InsertIdempotent inserts events into the database, skipping events whose id
+// already exists in the database, and returns the number of inserted events.
+// This is useful for at-least-once delivery of events, where the same event
+// may be received multiple times.
+//
+// In contrast to Insert, InsertIdempotent does not validate the versions of
+// the events and does not use a transaction, even if transactions are enabled.
+// The events are inserted using an unordered bulk write, so that duplicate
+// events do not prevent the insertion of the remaining events. The version
+// state of the aggregates is updated to the highest inserted event version.
+func (s *EventStore) InsertIdempotent(ctx context.Context, events ...event.Event) (int, error) {
+	if err := s.connectOnce(ctx); err != nil {
+		return 0, fmt.Errorf("connect: %w", err)
 	}
+
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	docs, err := s.makeEntries(events)
+	if err != nil {
+		return 0, err
+	}
+
+	// failed contains the indexes of the events that were not inserted,
+	// either because they already exist or because of another write error.
+	failed := make(map[int]bool)
+	var insertError error
+	if _, err := s.entries.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false)); err != nil {
+		var bulkError mongo.BulkWriteException
+		if !errors.As(err, &bulkError) || bulkError.WriteConcernError != nil {
+			return 0, fmt.Errorf("mongo: %w", err)
+		}
+		for _, writeError := range bulkError.WriteErrors {
+			failed[writeError.Index] = true
+			if !isDuplicateIDError(writeError.WriteError) {
+				insertError = fmt.Errorf("mongo: %w", err)
+			}
+		}
+	}
+
+	inserted := make([]event.Event, 0, len(events)-len(failed))
+	for i, evt := range events {
+		if !failed[i] {
+			inserted = append(inserted, evt)
+		}
+	}
+
+	// The states of the inserted events must be updated even if some events
+	// failed to insert, so that later inserts are validated against them.
+	if err := s.updateMaxStates(ctx, inserted); err != nil {
+		return len(inserted), fmt.Errorf("update state: %w", err)
+	}
+
+	return len(inserted), insertError
+}
+
+// updateMaxStates updates the version state of the aggregates of the given
+// events to the highest event version, unless the stored version is higher.
+func (s *EventStore) updateMaxStates(ctx context.Context, events []event.Event) error {
+	versions := make(map[state]int)
+	for _, evt := range events {
+		id, name, v := evt.Aggregate()
+		if name == "" || id == uuid.Nil {
+			continue
+		}
+		key := state{AggregateName: name, AggregageID: id}
+		if v > versions[key] {
+			versions[key] = v
+		}
+	}
+
+	for st, v := range versions {
+		if _, err := s.states.UpdateOne(
+			ctx,
+			bson.D{
+				{Key: "aggregateName", Value: st.AggregateName},
+				{Key: "aggregateId", Value: st.AggregageID},
+			},
+			bson.D{{Key: "$max", Value: bson.D{{Key: "version", Value: v}}}},
+			options.Update().SetUpsert(true),
+		); err != nil {
+			return fmt.Errorf("mongo: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// isDuplicateIDError returns whether err is a duplicate key error for the
+// unique event id index.
+func isDuplicateIDError(err mongo.WriteError) bool {
+	return err.Code == 11000 && strings.Contains(err.Message, "goes_id")
+}
+
 // Find returns the event with the specified UUID from the database if it exists.
 func (s *EventStore) Find(ctx context.Context, id uuid.UUID) (event.Event, error) {
 	if err := s.connectOnce(ctx); err != nil {
@@ -398,6 +584,53 @@ func (s *EventStore) Find(ctx context.Context, id uuid.UUID) (event.Event, error
 	return e.event(s.enc)
 }
 
+// Redact replaces the data of the event with the given id with the data that
+// is returned by redactor, which receives the decoded data of the event. Use
+// Redact to remove personal data from an event that must be kept for the
+// consistency of its aggregate. The redacted data is encoded using the same
+// event name and updated in place, so the id, name, time and aggregate of the
+// event are preserved.
+//
+//	err := store.Redact(context.TODO(), eventID, func(data any) (any, error) {
+//		d := data.(UserRegistered)
+//		d.Email = ""
+//		return d, nil
+//	})
+func (s *EventStore) Redact(ctx context.Context, id uuid.UUID, redactor func(data any) (any, error)) error {
+	if err := s.connectOnce(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+
+	res := s.entries.FindOne(ctx, bson.M{"id": id})
+	var e entry
+	if err := res.Decode(&e); err != nil {
+		return fmt.Errorf("decode document: %w", err)
+	}
+
+	data, err := s.enc.Decode(bytes.NewReader(e.Data), e.Name)
+	if err != nil {
+		return fmt.Errorf("decode %q event data: %w", e.Name, err)
+	}
+
+	redacted, err := redactor(data)
+	if err != nil {
+		return fmt.Errorf("redact %q event data: %w", e.Name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := s.enc.Encode(&buf, e.Name, redacted); err != nil {
+		return fmt.Errorf("encode %q event data: %w", e.Name, err)
+	}
+
+	if _, err := s.entries.UpdateOne(ctx, bson.M{"id": id}, bson.D{
+		{Key: "$set", Value: bson.D{{Key: "data", Value: buf.Bytes()}}},
+	}); err != nil {
+		return fmt.Errorf("mongo: %w", err)
+	}
+
+	return nil
+}
+
 // Delete deletes the given event from the database.
 func (s *EventStore) Delete(ctx context.Context, events ...event.Event) error {
 	if len(events) == 0 {
@@ -475,16 +708,139 @@ func (s *EventStore) Delete(ctx context.Context, events ...event.Event) error {
 	})
 }
 
+// DeleteQuery deletes the events that match the given Query using a single
+// DeleteMany operation and returns the number of deleted events. The stored
+// version state of an aggregate is removed if none of its events remain in the
+// database, so that the aggregate can be recreated from version 1. If the Query
+// has no filters, DeleteQuery returns event.ErrEmptyQuery.
+func (s *EventStore) DeleteQuery(ctx context.Context, q event.Query) (int, error) {
+	if !event.HasFilter(q) {
+		return 0, event.ErrEmptyQuery
+	}
+
+	if err := s.connectOnce(ctx); err != nil {
+		return 0, fmt.Errorf("connect: %w", err)
+	}
+
+	var deleted int
+	err := s.client.UseSession(ctx, func(ctx mongo.SessionContext) error {
+		if s.transactions {
+			if err := ctx.StartTransaction(); err != nil {
+				return fmt.Errorf("start transaction: %w", err)
+			}
+		}
+
+		abort := func(err error) error {
+			if s.transactions {
+				if abortError := ctx.AbortTransaction(ctx); abortError != nil {
+					return fmt.Errorf("abort transaction: %w", abortError)
+				}
+			}
+			return err
+		}
+
+		f, err := s.withCursorFilter(ctx, makeFilter(q), q.AfterID())
+		if err != nil {
+			return abort(err)
+		}
+
+		states, err := s.affectedStates(ctx, f)
+		if err != nil {
+			return abort(err)
+		}
+
+		res, err := s.entries.DeleteMany(ctx, f)
+		if err != nil {
+			return abort(fmt.Errorf("mongo: %w", err))
+		}
+		deleted = int(res.DeletedCount)
+
+		for _, st := range states {
+			remaining, err := s.entries.CountDocuments(ctx, bson.D{
+				{Key: "aggregateName", Value: st.AggregateName},
+				{Key: "aggregateId", Value: st.AggregageID},
+			}, options.Count().SetLimit(1))
+			if err != nil {
+				return abort(fmt.Errorf("mongo: count remaining events: %w", err))
+			}
+
+			if remaining > 0 {
+				continue
+			}
+
+			if _, err := s.states.DeleteOne(ctx, bson.D{
+				{Key: "aggregateName", Value: st.AggregateName},
+				{Key: "aggregateId", Value: st.AggregageID},
+			}); err != nil {
+				return abort(fmt.Errorf("delete aggregate state: %w", err))
+			}
+		}
+
+		if s.transactions {
+			if err := ctx.CommitTransaction(ctx); err != nil {
+				return fmt.Errorf("commit transaction: %w", err)
+			}
+		}
+
+		return nil
+	})
+
+	return deleted, err
+}
+
+// affectedStates returns the aggregates of the events that match the given
+// filter.
+func (s *EventStore) affectedStates(ctx context.Context, filter bson.D) ([]state, error) {
+	match := append(bson.D{}, filter...)
+	match = append(match, bson.E{Key: "aggregateName", Value: bson.D{{Key: "$ne", Value: ""}}})
+
+	cur, err := s.entries.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.D{{Key: "_id", Value: bson.D{
+			{Key: "aggregateName", Value: "$aggregateName"},
+			{Key: "aggregateId", Value: "$aggregateId"},
+		}}}}},
+		{{Key: "$replaceRoot", Value: bson.D{{Key: "newRoot", Value: "$_id"}}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("mongo: find affected aggregates: %w", err)
+	}
+
+	var states []state
+	if err := cur.All(ctx, &states); err != nil {
+		return nil, fmt.Errorf("mongo cursor: %w", err)
+	}
+
+	return states, nil
+}
+
 // Query queries the database for events filtered by Query q and returns an
 // streams.New for those events.
 func (s *EventStore) Query(ctx context.Context, q event.Query) (<-chan event.Event, <-chan error, error) {
 	if err := s.connectOnce(ctx); err != nil {
 		return nil, nil, fmt.Errorf("connect: %w", err)
 	}
+	if q.AfterID() != uuid.Nil && (len(q.Sortings()) > 0 || !q.ResumeAfter().IsZero()) {
+		return nil, nil, event.ErrCursorWithSortings
+	}
+
 	opts := options.Find()
-	opts = applySortings(opts, q.Sortings()...)
+	if !q.ResumeAfter().IsZero() {
+		// Events that are equal for all sortings are ordered by their ids, so
+		// that the order matches the resume filter.
+		opts = opts.SetSort(append(sortDocument(event.ResumeSortings(q)...), bson.E{Key: "id", Value: 1}))
+	} else {
+		opts = applySortings(opts, q.Sortings()...)
+	}
 
-	f := makeFilter(q)
+	f, err := s.withCursorFilter(ctx, makeFilter(q), q.AfterID())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if q.AfterID() != uuid.Nil {
+		opts = opts.SetSort(bson.D{{Key: "_id", Value: 1}})
+	}
 
 	cur, err := s.entries.Find(ctx, f, opts)
 	if err != nil {
@@ -536,10 +892,121 @@ func (s *EventStore) Query(ctx context.Context, q event.Query) (<-chan event.Eve
 	return events, errs, nil
 }
 
+// Count returns the number of events that match the given Query.
+func (s *EventStore) Count(ctx context.Context, q event.Query) (int, error) {
+	if err := s.connectOnce(ctx); err != nil {
+		return 0, fmt.Errorf("connect: %w", err)
+	}
+
+	f, err := s.withCursorFilter(ctx, makeFilter(q), q.AfterID())
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := s.entries.CountDocuments(ctx, f)
+	if err != nil {
+		return 0, fmt.Errorf("mongo: %w", err)
+	}
+
+	return int(count), nil
+}
+
+// withCursorFilter adds a filter for the documents that were inserted after
+// the event with the given id. The insertion order is determined by the
+// ObjectIDs of the documents.
+func (s *EventStore) withCursorFilter(ctx context.Context, filter bson.D, afterID uuid.UUID) (bson.D, error) {
+	if afterID == uuid.Nil {
+		return filter, nil
+	}
+
+	res := s.entries.FindOne(ctx, bson.M{"id": afterID}, options.FindOne().SetProjection(bson.M{"_id": 1}))
+
+	var doc struct {
+		ObjectID primitive.ObjectID `bson:"_id"`
+	}
+	if err := res.Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("%w [id=%v]", event.ErrCursorNotFound, afterID)
+		}
+		return nil, fmt.Errorf("mongo: find cursor event: %w", err)
+	}
+
+	return append(filter, bson.E{Key: "_id", Value: bson.D{{Key: "$gt", Value: doc.ObjectID}}}), nil
+}
+
+// Subscribe subscribes to events that are inserted into the store, using a
+// MongoDB change stream on the event collection. Change streams require
+// MongoDB to run as a replica set or sharded cluster. If no event names are
+// provided, every inserted event is received.
+func (s *EventStore) Subscribe(ctx context.Context, names ...string) (<-chan event.Event, <-chan error, error) {
+	if err := s.connectOnce(ctx); err != nil {
+		return nil, nil, fmt.Errorf("connect: %w", err)
+	}
+
+	match := bson.D{{Key: "operationType", Value: "insert"}}
+	if len(names) > 0 {
+		match = append(match, bson.E{Key: "fullDocument.name", Value: bson.D{{Key: "$in", Value: names}}})
+	}
+
+	stream, err := s.entries.Watch(ctx, mongo.Pipeline{{{Key: "$match", Value: match}}})
+	if err != nil {
+		return nil, nil, fmt.Errorf("mongo: %w", err)
+	}
+
+	events := make(chan event.Event)
+	errs := make(chan error)
+
+	go func() {
+		defer close(events)
+		defer close(errs)
+		defer stream.Close(context.Background())
+
+		for stream.Next(ctx) {
+			var change struct {
+				FullDocument entry `bson:"fullDocument"`
+			}
+			if err := stream.Decode(&change); err != nil {
+				select {
+				case <-ctx.Done():
+					return
+				case errs <- err:
+				}
+				continue
+			}
+
+			evt, err := change.FullDocument.event(s.enc)
+			if err != nil {
+				select {
+				case <-ctx.Done():
+					return
+				case errs <- err:
+				}
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case events <- evt:
+			}
+		}
+
+		if err := stream.Err(); err != nil && ctx.Err() == nil {
+			select {
+			case <-ctx.Done():
+			case errs <- fmt.Errorf("mongo change stream: %w", err):
+			}
+		}
+	}()
+
+	return events, errs, nil
+}
+
 // Connect establishes the connection to the underlying MongoDB and returns the
 // mongo.Client. Connect doesn't need to be called manually as it's called
-// automatically on the first call to s.Insert, s.Find, s.Delete or s.Query. Use
-// Connect if you want to explicitly control when to connect to MongoDB.
+// automatically on the first call to s.Insert, s.Find, s.Delete, s.Query,
+// s.Count or s.Subscribe. Use Connect if you want to explicitly control when to
+// connect to MongoDB.
 func (s *EventStore) Connect(ctx context.Context, opts ...*options.ClientOptions) (*mongo.Client, error) {
 	if err := s.connectOnce(ctx, opts...); err != nil {
 		return nil, err
@@ -584,6 +1051,35 @@ func (s *EventStore) connect(ctx context.Context, opts ...*options.ClientOptions
 	return nil
 }
 
+// EnsureIndexes creates the core indexes of the event collection (see
+// indices.EventStoreCore) and the additional indexes that were configured
+// with WithIndices:
+//
+//   - a unique index on the event id, used by Find, Delete and the AfterID
+//     cursor of queries
+//   - a compound index on (name, timeNano), used by queries that filter by
+//     event name and/or sort by time, like the queries of projection jobs
+//   - a compound index on (aggregateName, aggregateVersion)
+//   - a unique compound index on (aggregateName, aggregateId,
+//     aggregateVersion), used by queries that fetch the events of a single
+//     aggregate, like aggregate repositories do
+//
+// The indexes are also created when the store connects to MongoDB for the
+// first time. Call EnsureIndexes to create them explicitly, for example in a
+// deployment step. EnsureIndexes is idempotent, because MongoDB does not
+// re-create indexes that already exist.
+func (s *EventStore) EnsureIndexes(ctx context.Context) error {
+	if err := s.connectOnce(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+
+	if err := s.ensureIndexes(ctx); err != nil {
+		return fmt.Errorf("ensure indexes: %w", err)
+	}
+
+	return nil
+}
+
 func (s *EventStore) ensureIndexes(ctx context.Context) error {
 	models := append(indices.EventStoreCore(), s.additionalIndices...)
 	_, err := s.entries.Indexes().CreateMany(ctx, models)
@@ -601,6 +1097,8 @@ func (e entry) event(enc codec.Encoding) (event.Event, error) {
 		event.ID(e.ID),
 		event.Time(stdtime.Unix(0, e.TimeNano)),
 		event.Aggregate(e.AggregateID, e.AggregateName, e.AggregateVersion),
+		event.CausedByID(e.CausationID),
+		event.CorrelatedWith(e.CorrelationID),
 	), nil
 }
 
@@ -608,21 +1106,64 @@ func makeFilter(q event.Query) bson.D {
 	filter := make(bson.D, 0)
 	filter = withIDFilter(filter, q.IDs()...)
 	filter = withTimeFilter(filter, q.Times())
-	filter = withNameFilter(filter, q.Names()...)
-	filter = withAggregateNameFilter(filter, q.AggregateNames()...)
+	filter = withNameFilter(filter, q.Names(), q.ExcludedNames())
+	filter = withAggregateNameFilter(filter, q.AggregateNames(), q.ExcludedAggregateNames())
 	filter = withAggregateIDFilter(filter, q.AggregateIDs()...)
 	filter = withAggregateVersionFilter(filter, q.AggregateVersions())
 	filter = withAggregateRefFilter(filter, q.Aggregates())
+	filter = withResumeFilter(filter, q.ResumeAfter(), event.ResumeSortings(q))
 	return filter
 }
 
-func withNameFilter(filter bson.D, names ...string) bson.D {
-	if len(names) == 0 {
+// withResumeFilter adds a filter for the documents that are sorted after the
+// given key by the given sortings and then by event id. The filter compares
+// the sorted fields lexicographically:
+//
+//	{$or: [
+//		{f1: {$gt: v1}},
+//		{f1: v1, f2: {$gt: v2}},
+//		...
+//		{f1: v1, f2: v2, ..., id: {$gt: id}},
+//	]}
+//
+// where $gt is replaced by $lt for fields that are sorted in descending order.
+func withResumeFilter(filter bson.D, key event.SortKey, sortings []event.SortOptions) bson.D {
+	if key.IsZero() {
 		return filter
 	}
-	return append(filter, bson.E{
-		Key: "name", Value: bson.D{{Key: "$in", Value: names}},
-	})
+
+	var equal bson.D
+	or := make(bson.A, 0, len(sortings)+1)
+	add := func(field string, value interface{}, dir event.SortDirection) {
+		op := "$gt"
+		if dir == event.SortDesc {
+			op = "$lt"
+		}
+		clause := append(append(bson.D{}, equal...), bson.E{Key: field, Value: bson.D{{Key: op, Value: value}}})
+		or = append(or, clause)
+		equal = append(equal, bson.E{Key: field, Value: value})
+	}
+
+	for _, opts := range sortings {
+		switch opts.Sort {
+		case event.SortTime:
+			add("timeNano", key.Time.UnixNano(), opts.Dir)
+		case event.SortAggregateName:
+			add("aggregateName", key.AggregateName, opts.Dir)
+		case event.SortAggregateID:
+			add("aggregateId", key.AggregateID, opts.Dir)
+		case event.SortAggregateVersion:
+			add("aggregateVersion", key.AggregateVersion, opts.Dir)
+		}
+	}
+	add("id", key.ID, event.SortAsc)
+
+	// Other filters may already use $or, so the filter is wrapped in $and.
+	return append(filter, bson.E{Key: "$and", Value: bson.A{bson.D{{Key: "$or", Value: or}}}})
+}
+
+func withNameFilter(filter bson.D, names, excluded []string) bson.D {
+	return withStringFilter(filter, "name", names, excluded)
 }
 
 func withIDFilter(filter bson.D, ids ...uuid.UUID) bson.D {
@@ -680,13 +1221,24 @@ func nanoTimes(ts ...stdtime.Time) []int64 {
 	return nano
 }
 
-func withAggregateNameFilter(filter bson.D, names ...string) bson.D {
-	if len(names) == 0 {
+func withAggregateNameFilter(filter bson.D, names, excluded []string) bson.D {
+	return withStringFilter(filter, "aggregateName", names, excluded)
+}
+
+// withStringFilter adds a filter for the given field that matches one of the
+// included values and none of the excluded values.
+func withStringFilter(filter bson.D, key string, included, excluded []string) bson.D {
+	var cond bson.D
+	if len(included) > 0 {
+		cond = append(cond, bson.E{Key: "$in", Value: included})
+	}
+	if len(excluded) > 0 {
+		cond = append(cond, bson.E{Key: "$nin", Value: excluded})
+	}
+	if len(cond) == 0 {
 		return filter
 	}
-	return append(filter, bson.E{
-		Key: "aggregateName", Value: bson.D{{Key: "$in", Value: names}},
-	})
+	return append(filter, bson.E{Key: key, Value: cond})
 }
 
 func withAggregateIDFilter(filter bson.D, ids ...uuid.UUID) bson.D {
@@ -761,6 +1313,10 @@ func withAggregateRefFilter(filter bson.D, tuples []event.AggregateRef) bson.D {
 }
 
 func applySortings(opts *options.FindOptions, sortings ...event.SortOptions) *options.FindOptions {
+	return opts.SetSort(sortDocument(sortings...))
+}
+
+func sortDocument(sortings ...event.SortOptions) bson.D {
 	sorts := make(bson.D, len(sortings))
 	for i, opts := range sortings {
 		v := 1
@@ -779,5 +1335,5 @@ func applySortings(opts *options.FindOptions, sortings ...event.SortOptions) *op
 			sorts[i] = bson.E{Key: "timeNano", Value: v}
 		}
 	}
-	return opts.SetSort(sorts)
+	return sorts
 }
@@ -15,7 +15,9 @@ import (
 	"github.com/modernice/goes/backend/testing/eventstoretest"
 	"github.com/modernice/goes/codec"
 	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/event/query"
 	etest "github.com/modernice/goes/event/test"
+	"github.com/modernice/goes/helper/streams"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
@@ -85,3 +87,194 @@ func TestStore_Insert_versionError(t *testing.T) {
 		t.Errorf("VersionError should have Event %v; got %v", events[0], versionError.Event)
 	}
 }
+
+func TestStore_InsertIdempotent_versionConflict(t *testing.T) {
+	enc := etest.NewEncoder()
+	s := mongotest.NewEventStore(enc, mongo.URL(os.Getenv("MONGOSTORE_URL")))
+
+	if _, err := s.Connect(context.Background()); err != nil {
+		t.Fatalf("failed to connect to mongodb: %v", err)
+	}
+
+	id := uuid.New()
+	first := event.New[any]("foo", etest.FooEventData{}, event.Aggregate(id, "foo", 1))
+
+	if err := s.Insert(context.Background(), first); err != nil {
+		t.Fatalf("Insert failed with %q", err)
+	}
+
+	conflicting := event.New[any]("foo", etest.FooEventData{}, event.Aggregate(id, "foo", 1))
+	second := event.New[any]("foo", etest.FooEventData{}, event.Aggregate(id, "foo", 2))
+
+	inserted, err := s.InsertIdempotent(context.Background(), conflicting, second)
+	if err == nil {
+		t.Fatalf("InsertIdempotent should fail for a duplicate aggregate version")
+	}
+
+	if inserted != 1 {
+		t.Fatalf("InsertIdempotent should return %d; got %d", 1, inserted)
+	}
+
+	// The state must contain the version of the inserted event.
+	stale := event.New[any]("foo", etest.FooEventData{}, event.Aggregate(id, "foo", 2))
+	var versionError mongo.VersionError
+	if err := s.Insert(context.Background(), stale); !errors.As(err, &versionError) {
+		t.Fatalf("Insert should fail with a %T error; got %v", versionError, err)
+	}
+
+	if versionError.CurrentVersion != 2 {
+		t.Fatalf("VersionError should have CurrentVersion %d; got %d", 2, versionError.CurrentVersion)
+	}
+}
+
+func TestStore_Insert_batches(t *testing.T) {
+	enc := etest.NewEncoder()
+	s := mongotest.NewEventStore(enc, mongo.URL(os.Getenv("MONGOSTORE_URL")), mongo.InsertBatchSize(3))
+
+	if _, err := s.Connect(context.Background()); err != nil {
+		t.Fatalf("failed to connect to mongodb: %v", err)
+	}
+
+	id := uuid.New()
+	events := make([]event.Event, 8)
+	for i := range events {
+		events[i] = event.New[any]("foo", etest.FooEventData{}, event.Aggregate(id, "foo", i+1))
+	}
+
+	if err := s.Insert(context.Background(), events...); err != nil {
+		t.Fatalf("Insert failed with %q", err)
+	}
+
+	count, err := s.Count(context.Background(), query.New(query.AggregateID(id)))
+	if err != nil {
+		t.Fatalf("failed to count events: %v", err)
+	}
+
+	if count != len(events) {
+		t.Fatalf("%d events should have been inserted; got %d", len(events), count)
+	}
+}
+
+func TestStore_EnsureIndexes(t *testing.T) {
+	s := mongotest.NewEventStore(etest.NewEncoder(), mongo.URL(os.Getenv("MONGOSTORE_URL")))
+
+	for i := 0; i < 2; i++ {
+		if err := s.EnsureIndexes(context.Background()); err != nil {
+			t.Fatalf("EnsureIndexes failed with %q", err)
+		}
+	}
+
+	cur, err := s.Collection().Indexes().List(context.Background())
+	if err != nil {
+		t.Fatalf("failed to list indexes: %v", err)
+	}
+
+	var indexes []bson.M
+	if err := cur.All(context.Background(), &indexes); err != nil {
+		t.Fatalf("failed to decode indexes: %v", err)
+	}
+
+	names := make(map[string]bool)
+	for _, idx := range indexes {
+		if name, ok := idx["name"].(string); ok {
+			names[name] = true
+		}
+	}
+
+	for _, name := range []string{"goes_id", "goes_name_time", "goes_aname_aversion", "goes_aname_aid_aversion"} {
+		if !names[name] {
+			t.Errorf("index %q should have been created; got %v", name, names)
+		}
+	}
+}
+
+func TestStore_InsertAtomic(t *testing.T) {
+	s := mongotest.NewEventStore(etest.NewEncoder(), mongo.URL(os.Getenv("MONGOREPLSTORE_URL")))
+
+	existing := event.New[any]("foo", etest.FooEventData{})
+	if err := s.Insert(context.Background(), existing); err != nil {
+		t.Fatalf("failed to insert event: %v", err)
+	}
+
+	events := []event.Event{
+		event.New[any]("foo", etest.FooEventData{}, event.Aggregate(uuid.New(), "foo", 1)),
+		event.New[any]("bar", etest.BarEventData{}, event.Aggregate(uuid.New(), "bar", 1)),
+		existing,
+	}
+
+	if err := s.InsertAtomic(context.Background(), events...); err == nil {
+		t.Fatalf("InsertAtomic should fail if an event already exists")
+	}
+
+	for _, evt := range events[:2] {
+		if _, err := s.Find(context.Background(), evt.ID()); err == nil {
+			t.Fatalf("no event should have been inserted; found %q event", evt.Name())
+		}
+	}
+}
+
+func TestStore_InsertAtomic_notSupported(t *testing.T) {
+	s := mongotest.NewEventStore(etest.NewEncoder(), mongo.URL(os.Getenv("MONGOSTORE_URL")))
+
+	err := s.InsertAtomic(context.Background(), event.New[any]("foo", etest.FooEventData{}))
+	if !errors.Is(err, mongo.ErrTransactionsNotSupported) {
+		t.Fatalf("InsertAtomic should fail with %q on a standalone server; got %v", mongo.ErrTransactionsNotSupported, err)
+	}
+}
+
+func TestStore_Redact(t *testing.T) {
+	s := mongotest.NewEventStore(etest.NewEncoder(), mongo.URL(os.Getenv("MONGOSTORE_URL")))
+
+	id := uuid.New()
+	events := []event.Event{
+		event.New[any]("foo", etest.FooEventData{A: "secret"}, event.Aggregate(id, "foo", 1)),
+		event.New[any]("foo", etest.FooEventData{A: "secret"}, event.Aggregate(id, "foo", 2)),
+		event.New[any]("foo", etest.FooEventData{A: "secret"}, event.Aggregate(id, "foo", 3)),
+	}
+
+	if err := s.Insert(context.Background(), events...); err != nil {
+		t.Fatalf("failed to insert events: %v", err)
+	}
+
+	if err := s.Redact(context.Background(), events[1].ID(), func(data any) (any, error) {
+		d := data.(etest.FooEventData)
+		d.A = ""
+		return d, nil
+	}); err != nil {
+		t.Fatalf("Redact() failed with %q", err)
+	}
+
+	str, errs, err := s.Query(context.Background(), query.New(query.Aggregate("foo", id), query.SortByAggregate()))
+	if err != nil {
+		t.Fatalf("Query() failed with %q", err)
+	}
+
+	result, err := streams.Drain(context.Background(), str, errs)
+	if err != nil {
+		t.Fatalf("drain events: %v", err)
+	}
+
+	if len(result) != len(events) {
+		t.Fatalf("Query() should return %d events; got %d", len(events), len(result))
+	}
+
+	for i, evt := range result {
+		want := events[i]
+		if evt.ID() != want.ID() || !evt.Time().Equal(want.Time()) {
+			t.Errorf("event %d should have id %s and time %v; got %s and %v", i, want.ID(), want.Time(), evt.ID(), evt.Time())
+		}
+
+		if _, _, v := evt.Aggregate(); v != i+1 {
+			t.Errorf("event %d should have version %d; got %d", i, i+1, v)
+		}
+
+		wantData := etest.FooEventData{A: "secret"}
+		if i == 1 {
+			wantData.A = ""
+		}
+
+		if evt.Data() != wantData {
+			t.Errorf("event %d should have data %v; got %v", i, wantData, evt.Data())
+		}
+	}
+}
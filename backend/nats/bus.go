@@ -72,6 +72,8 @@ type envelope struct {
 	AggregateName    string
 	AggregateID      uuid.UUID
 	AggregateVersion int
+	CausationID      uuid.UUID
+	CorrelationID    uuid.UUID
 }
 
 // NewEventBus returns a NATS event bus.
@@ -99,6 +99,8 @@ func (core *core) publish(ctx context.Context, bus *EventBus, evt event.Event) e
 		AggregateName:    name,
 		AggregateID:      id,
 		AggregateVersion: v,
+		CausationID:      event.CausationID(evt),
+		CorrelationID:    event.CorrelationID(evt),
 	}
 
 	buf = bytes.Buffer{}
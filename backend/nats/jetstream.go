@@ -259,6 +259,8 @@ func (js *jetStream) publish(ctx context.Context, bus *EventBus, evt event.Event
 		AggregateName:    name,
 		AggregateID:      id,
 		AggregateVersion: v,
+		CausationID:      event.CausationID(evt),
+		CorrelationID:    event.CorrelationID(evt),
 	}
 
 	buf = bytes.Buffer{}
@@ -138,6 +138,8 @@ func (sub *subscription) send(bus *EventBus, msg []byte) error {
 			env.AggregateName,
 			env.AggregateVersion,
 		),
+		event.CausedByID(env.CausationID),
+		event.CorrelatedWith(env.CorrelationID),
 	)
 
 	for _, rcpt := range sub.recipients {
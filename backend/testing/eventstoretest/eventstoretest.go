@@ -2,6 +2,7 @@ package eventstoretest
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 	stdtime "time"
@@ -17,6 +18,7 @@ import (
 	"github.com/modernice/goes/helper/pick"
 	"github.com/modernice/goes/helper/streams"
 	"github.com/modernice/goes/internal/xtime"
+	goestest "github.com/modernice/goes/test"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -29,8 +31,11 @@ func Run(t *testing.T, name string, newStore EventStoreFactory) {
 		run(t, "Insert", newStore, testInsert)
 		run(t, "Find", newStore, testFind)
 		run(t, "Delete", newStore, testDelete)
+		run(t, "DeleteQuery", newStore, testDeleteQuery)
 		run(t, "Concurrency", newStore, testConcurrency)
 		run(t, "Query", newStore, testQuery)
+		run(t, "Count", newStore, testCount)
+		run(t, "Subscribe", newStore, testSubscribe)
 	})
 }
 
@@ -44,6 +49,8 @@ func testInsert(t *testing.T, newStore EventStoreFactory) {
 	run(t, "SingleInsert", newStore, testSingleInsert)
 	run(t, "MultiInsert", newStore, testMultiInsert)
 	run(t, "InvalidMultiInsert", newStore, testInvalidMultiInsert)
+	run(t, "InsertIdempotent", newStore, testInsertIdempotent)
+	run(t, "InsertCausation", newStore, testInsertCausation)
 }
 
 func testSingleInsert(t *testing.T, newStore EventStoreFactory) {
@@ -105,6 +112,76 @@ func testInvalidMultiInsert(t *testing.T, newStore EventStoreFactory) {
 	}
 }
 
+func testInsertIdempotent(t *testing.T, newStore EventStoreFactory) {
+	store := newStore(test.NewEncoder())
+
+	evt := event.New[any]("foo", test.FooEventData{A: "foo"}, event.Aggregate(uuid.New(), "bar", 1))
+
+	inserted, err := store.InsertIdempotent(context.Background(), evt)
+	if err != nil {
+		t.Fatalf("InsertIdempotent shouldn't fail; failed with %q", err)
+	}
+
+	if inserted != 1 {
+		t.Fatalf("InsertIdempotent should return %d; got %d", 1, inserted)
+	}
+
+	// inserting the same event again should be a no-op
+	inserted, err = store.InsertIdempotent(context.Background(), evt)
+	if err != nil {
+		t.Fatalf("InsertIdempotent shouldn't fail for an existing event; failed with %q", err)
+	}
+
+	if inserted != 0 {
+		t.Fatalf("InsertIdempotent should return %d for an existing event; got %d", 0, inserted)
+	}
+
+	// only the new event should be inserted
+	id, name, _ := evt.Aggregate()
+	other := event.New[any]("foo", test.FooEventData{A: "foo"}, event.Aggregate(id, name, 2))
+
+	inserted, err = store.InsertIdempotent(context.Background(), evt, other)
+	if err != nil {
+		t.Fatalf("InsertIdempotent shouldn't fail; failed with %q", err)
+	}
+
+	if inserted != 1 {
+		t.Fatalf("InsertIdempotent should return %d; got %d", 1, inserted)
+	}
+
+	result, err := runQuery(store, query.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test.AssertEqualEventsUnsorted(t, []event.Event{evt, other}, result)
+}
+
+func testInsertCausation(t *testing.T, newStore EventStoreFactory) {
+	store := newStore(test.NewEncoder())
+
+	causationID := uuid.New()
+	correlationID := uuid.New()
+	evt := event.New[any]("foo", test.FooEventData{A: "foo"}, event.CausedByID(causationID), event.CorrelatedWith(correlationID))
+
+	if err := store.Insert(context.Background(), evt); err != nil {
+		t.Fatalf("Insert shouldn't fail; failed with %q", err)
+	}
+
+	found, err := store.Find(context.Background(), evt.ID())
+	if err != nil {
+		t.Fatalf("Find shouldn't fail; failed with %q", err)
+	}
+
+	if id := event.CausationID(found); id != causationID {
+		t.Errorf("found event should have causation id %s; got %s", causationID, id)
+	}
+
+	if id := event.CorrelationID(found); id != correlationID {
+		t.Errorf("found event should have correlation id %s; got %s", correlationID, id)
+	}
+}
+
 func testFind(t *testing.T, newStore EventStoreFactory) {
 	store := newStore(test.NewEncoder())
 
@@ -179,6 +256,55 @@ L:
 	}
 }
 
+func testDeleteQuery(t *testing.T, newStore EventStoreFactory) {
+	userID := uuid.New()
+	otherID := uuid.New()
+
+	userEvents := []event.Event{
+		event.New[any]("foo", test.FooEventData{A: "foo"}, event.Aggregate(userID, "user", 1)),
+		event.New[any]("foo", test.FooEventData{A: "foo"}, event.Aggregate(userID, "user", 2)),
+		event.New[any]("bar", test.BarEventData{A: "bar"}, event.Aggregate(userID, "user", 3)),
+	}
+	otherEvents := []event.Event{
+		event.New[any]("foo", test.FooEventData{A: "foo"}, event.Aggregate(otherID, "user", 1)),
+		event.New[any]("baz", test.BazEventData{A: "baz"}),
+	}
+
+	store, err := makeStore(newStore, append(userEvents, otherEvents...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.DeleteQuery(context.Background(), query.New()); !errors.Is(err, event.ErrEmptyQuery) {
+		t.Fatalf("DeleteQuery should fail with %q for an empty query; got %v", event.ErrEmptyQuery, err)
+	}
+
+	if _, err := store.DeleteQuery(context.Background(), query.New(query.SortByTime())); !errors.Is(err, event.ErrEmptyQuery) {
+		t.Fatalf("DeleteQuery should fail with %q for a query without filters; got %v", event.ErrEmptyQuery, err)
+	}
+
+	deleted, err := store.DeleteQuery(context.Background(), query.New(query.Aggregate("user", userID)))
+	if err != nil {
+		t.Fatalf("DeleteQuery shouldn't fail; failed with %q", err)
+	}
+
+	if deleted != len(userEvents) {
+		t.Fatalf("DeleteQuery should return %d; got %d", len(userEvents), deleted)
+	}
+
+	result, err := runQuery(store, query.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test.AssertEqualEventsUnsorted(t, otherEvents, result)
+
+	// the deleted aggregate can be recreated from version 1
+	if err := store.Insert(context.Background(), event.New[any]("foo", test.FooEventData{A: "foo"}, event.Aggregate(userID, "user", 1))); err != nil {
+		t.Fatalf("Insert shouldn't fail after the aggregate was deleted; failed with %q", err)
+	}
+}
+
 func testConcurrency(t *testing.T, newStore EventStoreFactory) {
 	run(t, "ConcurrentInsert", newStore, testConcurrentInsert)
 	run(t, "ConcurrentFind", newStore, testConcurrentFind)
@@ -256,13 +382,17 @@ func testConcurrentDelete(t *testing.T, newStore EventStoreFactory) {
 
 func testQuery(t *testing.T, newStore EventStoreFactory) {
 	run(t, "QueryName", newStore, testQueryName)
+	run(t, "QueryExcludeName", newStore, testQueryExcludeName)
 	run(t, "QueryID", newStore, testQueryID)
 	run(t, "QueryTime", newStore, testQueryTime)
 	run(t, "QueryAggregateName", newStore, testQueryAggregateName)
+	run(t, "QueryExcludeAggregateName", newStore, testQueryExcludeAggregateName)
 	run(t, "QueryAggregateID", newStore, testQueryAggregateID)
 	run(t, "QueryAggregateVersion", newStore, testQueryAggregateVersion)
 	run(t, "QueryAggregate", newStore, testQueryAggregate)
 	run(t, "Sorting", newStore, testQuerySorting)
+	run(t, "AfterID", newStore, testQueryAfterID)
+	run(t, "Resume", newStore, testQueryResume)
 }
 
 func testQueryName(t *testing.T, newStore EventStoreFactory) {
@@ -287,6 +417,37 @@ func testQueryName(t *testing.T, newStore EventStoreFactory) {
 	test.AssertEqualEventsUnsorted(t, events, result)
 }
 
+func testQueryExcludeName(t *testing.T, newStore EventStoreFactory) {
+	events := []event.Event{
+		event.New[any]("foo", test.FooEventData{A: "foo"}),
+		event.New[any]("bar", test.BarEventData{A: "bar"}),
+		event.New[any]("baz", test.BazEventData{A: "baz"}),
+	}
+
+	store, err := makeStore(newStore, events...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := runQuery(store, query.New(query.ExcludeName("bar")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test.AssertEqualEventsUnsorted(t, []event.Event{events[0], events[2]}, result)
+
+	// exclusions are applied after inclusions
+	result, err = runQuery(store, query.New(
+		query.Name("foo", "bar"),
+		query.ExcludeName("bar"),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test.AssertEqualEventsUnsorted(t, []event.Event{events[0]}, result)
+}
+
 func testQueryID(t *testing.T, newStore EventStoreFactory) {
 	events := []event.Event{
 		event.New[any]("foo", test.FooEventData{A: "foo"}, event.ID(uuid.New())),
@@ -312,12 +473,16 @@ func testQueryID(t *testing.T, newStore EventStoreFactory) {
 }
 
 func testQueryTime(t *testing.T, newStore EventStoreFactory) {
-	now := xtime.Now()
-	events := []event.Event{
-		event.New[any]("foo", test.FooEventData{A: "foo"}, event.Time(now)),
-		event.New[any]("bar", test.BarEventData{A: "bar"}, event.Time(now.AddDate(0, 1, 0))),
-		event.New[any]("baz", test.BazEventData{A: "baz"}, event.Time(now.AddDate(1, 0, 0))),
-	}
+	now := stdtime.Date(2022, 1, 1, 12, 0, 0, 123456789, stdtime.UTC)
+	clock := goestest.WithFrozenClock(t, now)
+
+	foo := event.New[any]("foo", test.FooEventData{A: "foo"})
+	clock.Set(now.AddDate(0, 1, 0))
+	bar := event.New[any]("bar", test.BarEventData{A: "bar"})
+	clock.Set(now.AddDate(1, 0, 0))
+	baz := event.New[any]("baz", test.BazEventData{A: "baz"})
+
+	events := []event.Event{foo, bar, baz}
 
 	store, err := makeStore(newStore, events...)
 	if err != nil {
@@ -357,6 +522,29 @@ func testQueryAggregateName(t *testing.T, newStore EventStoreFactory) {
 	test.AssertEqualEventsUnsorted(t, want, result)
 }
 
+func testQueryExcludeAggregateName(t *testing.T, newStore EventStoreFactory) {
+	events := []event.Event{
+		event.New[any]("foo", test.FooEventData{A: "foo"}, event.Aggregate(uuid.New(), "foo", 1)),
+		event.New[any]("foo", test.FooEventData{A: "foo"}, event.Aggregate(uuid.New(), "bar", 1)),
+		event.New[any]("foo", test.FooEventData{A: "foo"}, event.Aggregate(uuid.New(), "baz", 1)),
+	}
+
+	store, err := makeStore(newStore, events...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := runQuery(store, query.New(
+		query.AggregateName("foo", "bar"),
+		query.ExcludeAggregateName("bar"),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test.AssertEqualEventsUnsorted(t, []event.Event{events[0]}, result)
+}
+
 func testQueryAggregateID(t *testing.T, newStore EventStoreFactory) {
 	events := []event.Event{
 		event.New[any]("foo", test.FooEventData{A: "foo"}, event.Aggregate(uuid.New(), "foo", 5)),
@@ -548,6 +736,218 @@ func testQuerySorting(t *testing.T, newStore EventStoreFactory) {
 	}
 }
 
+func testQueryAfterID(t *testing.T, newStore EventStoreFactory) {
+	now := xtime.Now()
+	events := []event.Event{
+		event.New[any]("foo", test.FooEventData{A: "foo"}, event.Time(now)),
+		event.New[any]("bar", test.BarEventData{A: "bar"}, event.Time(now)),
+		event.New[any]("foo", test.FooEventData{A: "foo"}, event.Time(now)),
+		event.New[any]("bar", test.BarEventData{A: "bar"}, event.Time(now)),
+		event.New[any]("foo", test.FooEventData{A: "foo"}, event.Time(now)),
+	}
+
+	// makeStore inserts the events one by one, so their insertion order is
+	// deterministic
+	store, err := makeStore(newStore, events...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := runQuery(store, query.New(query.AfterID(events[1].ID())))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// events with the same time are returned in insertion order
+	test.AssertEqualEvents(t, events[2:], result)
+
+	result, err = runQuery(store, query.New(query.AfterID(events[1].ID()), query.Name("foo")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test.AssertEqualEvents(t, []event.Event{events[2], events[4]}, result)
+
+	count, err := store.Count(context.Background(), query.New(query.AfterID(events[2].ID())))
+	if err != nil {
+		t.Fatalf("Count() failed with %q", err)
+	}
+
+	if count != 2 {
+		t.Fatalf("Count() should return %d; got %d", 2, count)
+	}
+
+	if _, _, err := store.Query(context.Background(), query.New(query.AfterID(uuid.New()))); !errors.Is(err, event.ErrCursorNotFound) {
+		t.Fatalf("Query() should fail with %q for an unknown cursor; got %q", event.ErrCursorNotFound, err)
+	}
+
+	if _, _, err := store.Query(context.Background(), query.New(
+		query.AfterID(events[1].ID()),
+		query.SortBy(event.SortTime, event.SortAsc),
+	)); !errors.Is(err, event.ErrCursorWithSortings) {
+		t.Fatalf("Query() should fail with %q for a cursor with sortings; got %q", event.ErrCursorWithSortings, err)
+	}
+}
+
+func testQueryResume(t *testing.T, newStore EventStoreFactory) {
+	now := xtime.Now()
+	aggregateID := uuid.New()
+	events := []event.Event{
+		event.New[any]("foo", test.FooEventData{A: "foo"}, event.Time(now.Add(2*stdtime.Second))),
+		event.New[any]("foo", test.FooEventData{A: "foo"}, event.Time(now)),
+		event.New[any]("bar", test.BarEventData{A: "bar"}, event.Time(now), event.Aggregate(aggregateID, "foo", 1)),
+		event.New[any]("bar", test.BarEventData{A: "bar"}, event.Time(now.Add(3*stdtime.Second)), event.Aggregate(aggregateID, "foo", 2)),
+		event.New[any]("foo", test.FooEventData{A: "foo"}, event.Time(now)),
+		event.New[any]("baz", test.BazEventData{A: "baz"}, event.Time(now.Add(-stdtime.Second))),
+	}
+
+	tests := map[string][]query.Option{
+		"default":              nil,
+		"time (desc)":          {query.SortBy(event.SortTime, event.SortDesc)},
+		"version, time (desc)": {query.SortByMulti(event.SortOptions{Sort: event.SortAggregateVersion, Dir: event.SortDesc}, event.SortOptions{Sort: event.SortTime, Dir: event.SortAsc})},
+		"name":                 {query.SortBy(event.SortAggregateName, event.SortAsc), query.Name("foo", "baz")},
+	}
+
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			store, err := makeStore(newStore, events...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			q := query.New(opts...)
+			sortings := event.ResumeSortings(q)
+			want := event.SortFunc(query.Apply(q, events...), func(a, b event.Event) bool {
+				return event.SortKeyOf(a).Compare(event.SortKeyOf(b), sortings...) < 0
+			})
+
+			for i, evt := range want {
+				resume, err := query.Resume(query.ResumeToken(evt))
+				if err != nil {
+					t.Fatalf("Resume() failed with %q", err)
+				}
+
+				result, err := runQuery(store, query.New(append(opts, resume)...))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				test.AssertEqualEvents(t, want[i+1:], result)
+
+				count, err := store.Count(context.Background(), query.New(append(opts, resume)...))
+				if err != nil {
+					t.Fatalf("Count() failed with %q", err)
+				}
+
+				if count != len(want[i+1:]) {
+					t.Fatalf("Count() should return %d; got %d", len(want[i+1:]), count)
+				}
+			}
+
+			// resuming does not depend on the existence of the cursor event
+			if err := store.Delete(context.Background(), want[0]); err != nil {
+				t.Fatalf("Delete() failed with %q", err)
+			}
+
+			resume, err := query.Resume(query.ResumeToken(want[0]))
+			if err != nil {
+				t.Fatalf("Resume() failed with %q", err)
+			}
+
+			result, err := runQuery(store, query.New(append(opts, resume)...))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			test.AssertEqualEvents(t, want[1:], result)
+		})
+	}
+}
+
+func testCount(t *testing.T, newStore EventStoreFactory) {
+	events := []event.Event{
+		event.New[any]("foo", test.FooEventData{A: "foo"}),
+		event.New[any]("foo", test.FooEventData{A: "foo"}),
+		event.New[any]("foo", test.FooEventData{A: "foo"}),
+		event.New[any]("bar", test.BarEventData{A: "bar"}),
+		event.New[any]("bar", test.BarEventData{A: "bar"}),
+	}
+
+	store, err := makeStore(newStore, events...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := store.Count(context.Background(), query.New())
+	if err != nil {
+		t.Fatalf("Count shouldn't fail; failed with %q", err)
+	}
+
+	if count != len(events) {
+		t.Fatalf("Count should return %d; got %d", len(events), count)
+	}
+
+	count, err = store.Count(context.Background(), query.New(query.Name("foo")))
+	if err != nil {
+		t.Fatalf("Count shouldn't fail; failed with %q", err)
+	}
+
+	if count != 3 {
+		t.Fatalf("Count should return %d; got %d", 3, count)
+	}
+}
+
+func testSubscribe(t *testing.T, newStore EventStoreFactory) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*stdtime.Second)
+	defer cancel()
+
+	store := newStore(test.NewEncoder())
+
+	if err := store.Insert(ctx, event.New[any]("foo", test.FooEventData{A: "foo"})); err != nil {
+		t.Fatalf("Insert shouldn't fail; failed with %q", err)
+	}
+
+	subCtx, cancelSub := context.WithCancel(ctx)
+	defer cancelSub()
+
+	events, errs, err := store.Subscribe(subCtx, "foo", "bar")
+	if err != nil {
+		t.Fatalf("Subscribe shouldn't fail; failed with %q", err)
+	}
+
+	inserted := []event.Event{
+		event.New[any]("foo", test.FooEventData{A: "foo"}),
+		event.New[any]("baz", test.BazEventData{A: "baz"}),
+		event.New[any]("bar", test.BarEventData{A: "bar"}),
+		event.New[any]("foo", test.FooEventData{A: "foo"}),
+	}
+
+	for _, evt := range inserted {
+		if err := store.Insert(ctx, evt); err != nil {
+			t.Fatalf("Insert shouldn't fail; failed with %q", err)
+		}
+	}
+
+	want := []event.Event{inserted[0], inserted[2], inserted[3]}
+	for i, evt := range want {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("timed out. received %d/%d events", i, len(want))
+		case err := <-errs:
+			t.Fatalf("subscription shouldn't fail; failed with %q", err)
+		case got := <-events:
+			if !event.Equal(got, evt) {
+				t.Fatalf("event #%d should be %q (%s); got %q (%s)", i, evt.Name(), evt.ID(), got.Name(), got.ID())
+			}
+		}
+	}
+
+	cancelSub()
+
+	for range events {
+	}
+}
+
 func makeStore(newStore EventStoreFactory, events ...event.Event) (event.Store, error) {
 	store := newStore(test.NewEncoder())
 	for i, evt := range events {
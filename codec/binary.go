@@ -0,0 +1,120 @@
+package codec
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Marshaler is implemented by data that marshals itself into bytes, like the
+// messages that are generated by protobuf.
+type Marshaler interface {
+	Marshal() ([]byte, error)
+}
+
+// Unmarshaler is implemented by data that unmarshals itself from bytes, like
+// the messages that are generated by protobuf.
+type Unmarshaler interface {
+	Unmarshal([]byte) error
+}
+
+// A BinaryRegistry allows registering data into a Registry using factory
+// functions. Data that is registered via a BinaryRegistry must implement
+// Marshaler and Unmarshaler, either on the value or on a pointer to it, and is
+// encoded and decoded by calling these methods directly. This allows to
+// register protobuf messages without implementing encoding.BinaryMarshaler and
+// encoding.BinaryUnmarshaler:
+//
+//	reg := codec.Binary(codec.New())
+//	reg.BinaryRegister("foo", func() any { return &pb.Foo{} })
+//
+// The Decoder reads all remaining bytes of its input, so DecodeN returns
+// ErrUndelimited for data that is registered via a BinaryRegistry.
+type BinaryRegistry struct{ *Registry }
+
+// Binary wraps the given Registry in a BinaryRegistry. The BinaryRegistry
+// provides a BinaryRegister function to register data using a factory
+// function.
+//
+// If reg is nil, a new underlying Registry is created with New().
+func Binary(reg *Registry) *BinaryRegistry {
+	if reg == nil {
+		reg = New()
+	}
+	return &BinaryRegistry{Registry: reg}
+}
+
+// BinaryRegister registers data with the given name into the underlying
+// registry. makeFunc is used to create instances of the data, and the
+// Marshal and Unmarshal methods of the data are used to encode and decode it.
+func (r *BinaryRegistry) BinaryRegister(name string, makeFunc func() any) {
+	registerWithFactoryFunc[any](
+		r.Registry,
+		name,
+		binaryEncoder{name},
+		binaryDecoder{name: name, makeFunc: makeFunc},
+		makeFunc,
+	)
+}
+
+type binaryEncoder struct{ name string }
+
+func (enc binaryEncoder) Encode(w io.Writer, data any) error {
+	m, ok := data.(Marshaler)
+	if !ok && data != nil {
+		m, ok = newPtr(data).(Marshaler)
+	}
+	if !ok {
+		return fmt.Errorf("%T does not implement Marshaler [name=%v]", data, enc.name)
+	}
+
+	b, err := m.Marshal()
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	_, err = w.Write(b)
+	return err
+}
+
+type binaryDecoder struct {
+	name     string
+	makeFunc func() any
+}
+
+func (dec binaryDecoder) Decode(r io.Reader) (any, error) {
+	data := dec.makeFunc()
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return data, fmt.Errorf("reader: %w", err)
+	}
+
+	// If the factory returns a non-nil pointer, unmarshal directly into it.
+	if rv := reflect.ValueOf(data); rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		m, ok := data.(Unmarshaler)
+		if !ok {
+			return data, fmt.Errorf("%T does not implement Unmarshaler [name=%v]", data, dec.name)
+		}
+		if err := m.Unmarshal(b); err != nil {
+			return data, fmt.Errorf("unmarshal: %w", err)
+		}
+		return data, nil
+	}
+
+	if data == nil {
+		return data, fmt.Errorf("factory returned nil [name=%v]", dec.name)
+	}
+
+	// Otherwise unmarshal into a pointer to a copy of the returned value.
+	ptr := newPtr(data)
+	m, ok := ptr.(Unmarshaler)
+	if !ok {
+		return data, fmt.Errorf("%T does not implement Unmarshaler [name=%v]", ptr, dec.name)
+	}
+	if err := m.Unmarshal(b); err != nil {
+		return data, fmt.Errorf("unmarshal: %w", err)
+	}
+
+	return deref(ptr), nil
+}
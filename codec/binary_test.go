@@ -0,0 +1,203 @@
+package codec_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/modernice/goes/codec"
+)
+
+func TestBinaryRegistry(t *testing.T) {
+	reg := codec.Binary(codec.New())
+	reg.BinaryRegister("foo", func() any { return mockMessage{} })
+
+	want := newMockMessage()
+
+	var buf bytes.Buffer
+	if err := reg.Encode(&buf, "foo", want); err != nil {
+		t.Fatalf("Encode() failed with %q", err)
+	}
+
+	decoded, err := reg.Decode(&buf, "foo")
+	if err != nil {
+		t.Fatalf("Decode() failed with %q", err)
+	}
+
+	if !cmp.Equal(want, decoded) {
+		t.Fatalf("decoded data should be %v; is %v\n%s", want, decoded, cmp.Diff(want, decoded))
+	}
+}
+
+func TestBinaryRegistry_pointer(t *testing.T) {
+	reg := codec.Binary(codec.New())
+	reg.BinaryRegister("foo", func() any { return &mockMessage{} })
+
+	want := newMockMessage()
+
+	var buf bytes.Buffer
+	if err := reg.Encode(&buf, "foo", &want); err != nil {
+		t.Fatalf("Encode() failed with %q", err)
+	}
+
+	decoded, err := reg.Decode(&buf, "foo")
+	if err != nil {
+		t.Fatalf("Decode() failed with %q", err)
+	}
+
+	if !cmp.Equal(&want, decoded) {
+		t.Fatalf("decoded data should be %v; is %v\n%s", &want, decoded, cmp.Diff(&want, decoded))
+	}
+}
+
+func TestBinaryRegistry_notMarshaler(t *testing.T) {
+	reg := codec.Binary(codec.New())
+	reg.BinaryRegister("foo", func() any { return mockDataA{} })
+
+	if err := reg.Encode(&bytes.Buffer{}, "foo", mockDataA{}); err == nil {
+		t.Fatalf("Encode() should fail for data that does not implement Marshaler")
+	}
+
+	if _, err := reg.Decode(strings.NewReader("foo"), "foo"); err == nil {
+		t.Fatalf("Decode() should fail for data that does not implement Unmarshaler")
+	}
+}
+
+func BenchmarkBinaryRegistry(b *testing.B) {
+	reg := codec.Binary(codec.New())
+	reg.BinaryRegister("foo", func() any { return mockMessage{} })
+	benchmarkEncoding(b, reg.Registry, newMockMessage())
+}
+
+func BenchmarkGobRegistry(b *testing.B) {
+	reg := codec.Gob(codec.New())
+	codec.GobRegister[mockMessage](reg, "goes.codec.benchmark.message")
+	benchmarkEncoding(b, reg.Registry, newMockMessage())
+}
+
+func benchmarkEncoding(b *testing.B, reg *codec.Registry, data mockMessage) {
+	name := reg.Registered()[0]
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var buf bytes.Buffer
+		if err := reg.Encode(&buf, name, data); err != nil {
+			b.Fatalf("Encode() failed with %q", err)
+		}
+		if _, err := reg.Decode(&buf, name); err != nil {
+			b.Fatalf("Decode() failed with %q", err)
+		}
+	}
+}
+
+// mockMessage is a medium-sized message that implements the Marshal and
+// Unmarshal methods of protobuf messages.
+type mockMessage struct {
+	ID     string
+	Name   string
+	Count  int64
+	Values []int64
+	Tags   []string
+}
+
+func newMockMessage() mockMessage {
+	msg := mockMessage{
+		ID:    "0b1e4a9e-7d35-4c3c-9d5e-3d4c52b8f6a1",
+		Name:  "foo",
+		Count: 42,
+	}
+	for i := 0; i < 32; i++ {
+		msg.Values = append(msg.Values, int64(i*i))
+		msg.Tags = append(msg.Tags, strings.Repeat("x", i))
+	}
+	return msg
+}
+
+func (msg mockMessage) Marshal() ([]byte, error) {
+	var b []byte
+	b = appendString(b, msg.ID)
+	b = appendString(b, msg.Name)
+	b = appendVarint(b, msg.Count)
+	b = appendUvarint(b, uint64(len(msg.Values)))
+	for _, v := range msg.Values {
+		b = appendVarint(b, v)
+	}
+	b = appendUvarint(b, uint64(len(msg.Tags)))
+	for _, tag := range msg.Tags {
+		b = appendString(b, tag)
+	}
+	return b, nil
+}
+
+func (msg *mockMessage) Unmarshal(b []byte) error {
+	r := bytes.NewReader(b)
+
+	var err error
+	if msg.ID, err = readString(r); err != nil {
+		return err
+	}
+	if msg.Name, err = readString(r); err != nil {
+		return err
+	}
+	if msg.Count, err = binary.ReadVarint(r); err != nil {
+		return err
+	}
+
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return err
+	}
+	msg.Values = make([]int64, n)
+	for i := range msg.Values {
+		if msg.Values[i], err = binary.ReadVarint(r); err != nil {
+			return err
+		}
+	}
+
+	if n, err = binary.ReadUvarint(r); err != nil {
+		return err
+	}
+	msg.Tags = make([]string, n)
+	for i := range msg.Tags {
+		if msg.Tags[i], err = readString(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func appendVarint(b []byte, v int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+func appendString(b []byte, s string) []byte {
+	b = appendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+func readString(r *bytes.Reader) (string, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return "", err
+	}
+	if uint64(r.Len()) < n {
+		return "", errors.New("unexpected end of message")
+	}
+	b := make([]byte, n)
+	r.Read(b)
+	return string(b), nil
+}
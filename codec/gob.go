@@ -66,6 +66,30 @@ func GobRegister[T any](r *GobRegistry, name string) {
 	r.gobRegister(name, val)
 }
 
+// GobRegisterStrict registers data of type T with the given name like
+// GobRegister does. If data has already been registered under the given name,
+// GobRegisterStrict does not overwrite it and returns an error that unwraps to
+// ErrAlreadyRegistered instead.
+func GobRegisterStrict[T any](r *GobRegistry, name string) error {
+	if err := registerStrictWithFactoryFunc[T](
+		r.Registry,
+		name,
+		gobEncoder[T]{name},
+		gobDecoder[T]{name: name, makeFunc: func() (v T) { return }},
+		func() any {
+			var v T
+			return v
+		},
+	); err != nil {
+		return err
+	}
+
+	var val T
+	r.gobRegister(name, val)
+
+	return nil
+}
+
 // GobRegister registers data with the given name into the underlying registry.
 // makeFunc is used create instances of the data and encoding/gob will be used
 // to encode and decode the data returned by makeFunc.
@@ -73,6 +97,26 @@ func (reg *GobRegistry) GobRegister(name string, makeFunc func() any) {
 	gobRegisterAny(reg, name, makeFunc)
 }
 
+// GobRegisterStrict registers data with the given name like GobRegister does.
+// If data has already been registered under the given name, GobRegisterStrict
+// does not overwrite it and returns an error that unwraps to
+// ErrAlreadyRegistered instead.
+func (reg *GobRegistry) GobRegisterStrict(name string, makeFunc func() any) error {
+	if err := registerStrictWithFactoryFunc[any](
+		reg.Registry,
+		name,
+		gobEncoder[any]{name},
+		gobDecoder[any]{name: name, makeFunc: makeFunc},
+		makeFunc,
+	); err != nil {
+		return err
+	}
+
+	reg.gobRegister(name, makeFunc())
+
+	return nil
+}
+
 func gobRegisterAny(r *GobRegistry, name string, makeFunc func() any) {
 	registerWithFactoryFunc[any](
 		r.Registry,
@@ -2,6 +2,7 @@ package codec_test
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -32,3 +33,38 @@ func TestGobRegistry(t *testing.T) {
 		t.Fatalf("decoded data should be %v; is %v\n%s", want, decoded, cmp.Diff(want, decoded))
 	}
 }
+
+func TestGobRegisterStrict(t *testing.T) {
+	reg := codec.Gob(codec.New())
+
+	if err := codec.GobRegisterStrict[mockDataA](reg, "foo"); err != nil {
+		t.Fatalf("GobRegisterStrict() failed with %q", err)
+	}
+
+	if err := codec.GobRegisterStrict[mockDataV1](reg, "foo"); !errors.Is(err, codec.ErrAlreadyRegistered) {
+		t.Fatalf("GobRegisterStrict() should fail with %q when registering a name twice; got %v", codec.ErrAlreadyRegistered, err)
+	}
+
+	if err := reg.GobRegisterStrict("foo", func() any { return mockDataV1{} }); !errors.Is(err, codec.ErrAlreadyRegistered) {
+		t.Fatalf("GobRegisterStrict() should fail with %q when registering a name twice; got %v", codec.ErrAlreadyRegistered, err)
+	}
+
+	if err := reg.GobRegisterStrict("bar", func() any { return mockDataV2{} }); err != nil {
+		t.Fatalf("GobRegisterStrict() failed with %q", err)
+	}
+
+	var buf bytes.Buffer
+	want := mockDataA{A: "foo"}
+	if err := reg.Encode(&buf, "foo", want); err != nil {
+		t.Fatalf("Encode() failed with %q", err)
+	}
+
+	decoded, err := reg.Decode(&buf, "foo")
+	if err != nil {
+		t.Fatalf("Decode() failed with %q", err)
+	}
+
+	if decoded != want {
+		t.Fatalf("GobRegisterStrict() should not overwrite the first registration; decoded data is %v", decoded)
+	}
+}
@@ -1,6 +1,7 @@
 package codec
 
 import (
+	"context"
 	"encoding"
 	"errors"
 	"fmt"
@@ -9,6 +10,16 @@ import (
 
 var errNotCustomMarshaler = errors.New("not custom")
 
+// isCustomMarshaler returns whether data can be encoded by
+// encodeCustomMarshaler.
+func isCustomMarshaler(data any) bool {
+	switch data.(type) {
+	case encoding.BinaryMarshaler, encoding.TextMarshaler:
+		return true
+	}
+	return false
+}
+
 // Tries to encode the given data using user-provided marshalers. If the data
 // does not implement either encoding.BinaryMarshaler or encoding.TextMarshaler,
 // errNotCustomMarshaler is returned.
@@ -46,16 +57,16 @@ func encodeText(w io.Writer, m encoding.TextMarshaler) error {
 
 // Tries to decode the given data using user-provided marshalers. If the data
 // does not implement either encoding.BinaryUnmarshaler or encoding.TextUnmarshaler,
-// errNotCustomMarshaler is returned.
+// errNotCustomMarshaler is returned without reading from r. Otherwise, r is
+// read until EOF, which is why DecodeN rejects such data (see isUndelimited).
 func decodeCustomMarshaler[T any](r io.Reader, data *T) error {
-	b, err := io.ReadAll(r)
-	if err != nil {
-		return fmt.Errorf("reader: %w", err)
-	}
-
 	idata := any(data)
 
 	if m, ok := idata.(encoding.BinaryUnmarshaler); ok {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("reader: %w", err)
+		}
 		if err = m.UnmarshalBinary(b); err != nil {
 			err = fmt.Errorf("unmarshal binary: %w", err)
 		}
@@ -63,6 +74,10 @@ func decodeCustomMarshaler[T any](r io.Reader, data *T) error {
 	}
 
 	if m, ok := idata.(encoding.TextUnmarshaler); ok {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("reader: %w", err)
+		}
 		if err = m.UnmarshalText(b); err != nil {
 			err = fmt.Errorf("unmarshal text: %w", err)
 		}
@@ -71,3 +86,91 @@ func decodeCustomMarshaler[T any](r io.Reader, data *T) error {
 
 	return errNotCustomMarshaler
 }
+
+// isCustomUnmarshaler returns whether data can be decoded by
+// decodeCustomMarshaler.
+func isCustomUnmarshaler(data any) bool {
+	switch data.(type) {
+	case encoding.BinaryUnmarshaler, encoding.TextUnmarshaler:
+		return true
+	}
+	return false
+}
+
+// countingReader counts the bytes that are read from the underlying reader.
+// countingReader implements io.ByteReader so that decoders which would
+// otherwise buffer their input (like encoding/gob) do not read beyond the end
+// of a single value.
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += n
+	return n, err
+}
+
+func (r *countingReader) ReadByte() (byte, error) {
+	if br, ok := r.r.(io.ByteReader); ok {
+		b, err := br.ReadByte()
+		if err == nil {
+			r.n++
+		}
+		return b, err
+	}
+
+	var b [1]byte
+	if _, err := io.ReadFull(r.r, b[:]); err != nil {
+		return 0, err
+	}
+	r.n++
+
+	return b[0], nil
+}
+
+// byteWiseReader reads a single byte per call to Read. DecodeN passes its
+// input wrapped in a byteWiseReader to decoders, which prevents buffering
+// decoders (like encoding/json) from reading beyond the end of a single value.
+type byteWiseReader struct{ io.ByteReader }
+
+func (r byteWiseReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	p[0] = b
+	return 1, nil
+}
+
+// ctxWriter aborts writes to the underlying writer when its Context is
+// canceled.
+type ctxWriter struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+func (w ctxWriter) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return w.w.Write(p)
+}
+
+// ctxReader aborts reads from the underlying reader when its Context is
+// canceled.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
@@ -3,8 +3,7 @@ package codec
 import (
 	"encoding/json"
 	"io"
-
-	"github.com/mitchellh/mapstructure"
+	"reflect"
 )
 
 // A JSONRegistry allows registering data into a Registry using factory
@@ -23,6 +22,10 @@ func JSON(reg *Registry) *JSONRegistry {
 	return &JSONRegistry{Registry: reg}
 }
 
+// JSONRegister registers data of type T with the given name into the
+// underlying registry of r. encoding/json will be used to encode and decode the
+// data. Types that implement json.Marshaler and json.Unmarshaler are encoded
+// and decoded using their own implementations.
 func JSONRegister[T any](r *JSONRegistry, name string) {
 	Register[T](
 		r.Registry,
@@ -32,6 +35,23 @@ func JSONRegister[T any](r *JSONRegistry, name string) {
 	)
 }
 
+// JSONRegisterStrict registers data of type T with the given name like
+// JSONRegister does. If data has already been registered under the given name,
+// JSONRegisterStrict does not overwrite it and returns an error that unwraps to
+// ErrAlreadyRegistered instead.
+func JSONRegisterStrict[T any](r *JSONRegistry, name string) error {
+	return registerStrictWithFactoryFunc[T](
+		r.Registry,
+		name,
+		jsonEncoder[T]{},
+		jsonDecoder[T]{name: name, makeFunc: func() (v T) { return v }},
+		func() any {
+			var v T
+			return v
+		},
+	)
+}
+
 // JSONRegister registers data with the given name into the underlying registry.
 // makeFunc is used create instances of the data and encoding/json will be used
 // to encode and decode the data returned by makeFunc.
@@ -59,15 +79,35 @@ type jsonDecoder[T any] struct {
 func (dec jsonDecoder[T]) Decode(r io.Reader) (T, error) {
 	data := dec.makeFunc()
 
-	untyped := make(map[string]any)
-
-	if err := json.NewDecoder(r).Decode(&untyped); err != nil {
-		return data, err
+	// If the factory returns a non-nil pointer, decode directly into it.
+	if rv := reflect.ValueOf(data); rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		return data, json.NewDecoder(r).Decode(any(data))
 	}
 
-	if err := mapstructure.Decode(untyped, &data); err != nil {
-		return data, err
+	// If T is an interface type, the factory returns the concrete type, so we
+	// have to decode into a pointer to a copy of that concrete value.
+	// Otherwise the json package would decode into a map.
+	if any(data) != nil {
+		ptr := newPtr(data)
+		if err := json.NewDecoder(r).Decode(ptr); err != nil {
+			return data, err
+		}
+		return deref(ptr).(T), nil
 	}
 
-	return data, nil
+	return data, json.NewDecoder(r).Decode(&data)
+}
+
+// JSONRegisterStrict registers data with the given name like JSONRegister
+// does. If data has already been registered under the given name,
+// JSONRegisterStrict does not overwrite it and returns an error that unwraps to
+// ErrAlreadyRegistered instead.
+func (r *JSONRegistry) JSONRegisterStrict(name string, makeFunc func() any) error {
+	return registerStrictWithFactoryFunc[any](
+		r.Registry,
+		name,
+		jsonEncoder[any]{},
+		jsonDecoder[any]{name: name, makeFunc: makeFunc},
+		makeFunc,
+	)
 }
@@ -2,6 +2,9 @@ package codec_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -32,3 +35,108 @@ func TestJSONRegistry(t *testing.T) {
 		t.Fatalf("decoded data should be %v; is %v\n%s", want, decoded, cmp.Diff(want, decoded))
 	}
 }
+
+func TestJSONRegistry_nested(t *testing.T) {
+	reg := codec.JSON(codec.New())
+
+	reg.JSONRegister("foo", func() any { return mockJSONData{} })
+
+	want := mockJSONData{
+		Name: "foo",
+		Nested: mockJSONNested{
+			Values: []int{1, 2, 3},
+			Tags:   map[string]string{"foo": "bar"},
+		},
+		Custom: mockJSONMarshaler{Value: "baz"},
+	}
+
+	var buf bytes.Buffer
+	if err := reg.Encode(&buf, "foo", want); err != nil {
+		t.Fatalf("Encode() failed with %q", err)
+	}
+
+	if !json.Valid(buf.Bytes()) {
+		t.Fatalf("encoded data should be valid JSON; got %q", buf.String())
+	}
+
+	var untyped map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &untyped); err != nil {
+		t.Fatalf("failed to unmarshal encoded data: %v", err)
+	}
+
+	if untyped["custom"] != "custom:baz" {
+		t.Fatalf("custom field should be encoded using its MarshalJSON method; got %v", untyped["custom"])
+	}
+
+	decoded, err := reg.Decode(bytes.NewReader(buf.Bytes()), "foo")
+	if err != nil {
+		t.Fatalf("Decode() failed with %q", err)
+	}
+
+	if !cmp.Equal(want, decoded) {
+		t.Fatalf("decoded data should be %v; is %v\n%s", want, decoded, cmp.Diff(want, decoded))
+	}
+}
+
+type mockJSONData struct {
+	Name   string            `json:"name"`
+	Nested mockJSONNested    `json:"nested"`
+	Custom mockJSONMarshaler `json:"custom"`
+}
+
+type mockJSONNested struct {
+	Values []int             `json:"values"`
+	Tags   map[string]string `json:"tags"`
+}
+
+type mockJSONMarshaler struct {
+	Value string
+}
+
+func (m mockJSONMarshaler) MarshalJSON() ([]byte, error) {
+	return json.Marshal("custom:" + m.Value)
+}
+
+func (m *mockJSONMarshaler) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	m.Value = strings.TrimPrefix(s, "custom:")
+	return nil
+}
+
+func TestJSONRegisterStrict(t *testing.T) {
+	reg := codec.JSON(codec.New())
+
+	if err := codec.JSONRegisterStrict[mockDataA](reg, "foo"); err != nil {
+		t.Fatalf("JSONRegisterStrict() failed with %q", err)
+	}
+
+	if err := codec.JSONRegisterStrict[mockDataV1](reg, "foo"); !errors.Is(err, codec.ErrAlreadyRegistered) {
+		t.Fatalf("JSONRegisterStrict() should fail with %q when registering a name twice; got %v", codec.ErrAlreadyRegistered, err)
+	}
+
+	if err := reg.JSONRegisterStrict("foo", func() any { return mockDataV1{} }); !errors.Is(err, codec.ErrAlreadyRegistered) {
+		t.Fatalf("JSONRegisterStrict() should fail with %q when registering a name twice; got %v", codec.ErrAlreadyRegistered, err)
+	}
+
+	if err := reg.JSONRegisterStrict("bar", func() any { return mockDataV2{} }); err != nil {
+		t.Fatalf("JSONRegisterStrict() failed with %q", err)
+	}
+
+	var buf bytes.Buffer
+	want := mockDataA{A: "foo"}
+	if err := reg.Encode(&buf, "foo", want); err != nil {
+		t.Fatalf("Encode() failed with %q", err)
+	}
+
+	decoded, err := reg.Decode(&buf, "foo")
+	if err != nil {
+		t.Fatalf("Decode() failed with %q", err)
+	}
+
+	if decoded != want {
+		t.Fatalf("JSONRegisterStrict() should not overwrite the first registration; decoded data is %v", decoded)
+	}
+}
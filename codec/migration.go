@@ -0,0 +1,138 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// schemaVersionHeader prefixes encoded data that is tagged with a schema
+// version. The header is followed by the uvarint-encoded schema version.
+var schemaVersionHeader = []byte("\x00goes:version:")
+
+// A Migration migrates data from one schema version to the next.
+type Migration func(old any) (any, error)
+
+// RegisterMigration registers a Migration for the data that is registered
+// under the given name. The Migration migrates the data from schema version
+// `from` to schema version `from+1`. Schema versions start at 1.
+//
+// The current schema version of the data is the highest version that can be
+// reached through the registered migrations. When encoding data whose current
+// schema version is greater than 1, the encoded bytes are tagged with the
+// schema version. When decoding data that is tagged with an older schema
+// version, or that isn't tagged at all (which means schema version 1), the
+// chain of migrations is run to bring the data to the current schema version:
+//
+//	type fooV1 struct { Name string }
+//	type fooV2 struct { FirstName, LastName string }
+//
+//	reg := codec.JSON(codec.New())
+//	codec.JSONRegister[fooV2](reg, "foo")
+//	codec.RegisterMigration(reg.Registry, "foo", 1, func(old any) (any, error) {
+//		var v1 fooV1
+//		if err := json.Unmarshal(old.([]byte), &v1); err != nil {
+//			return nil, err
+//		}
+//		first, last, _ := strings.Cut(v1.Name, " ")
+//		return fooV2{FirstName: first, LastName: last}, nil
+//	})
+//
+// The first Migration of a chain receives the encoded bytes of the old data as
+// a []byte. Each subsequent Migration receives the value that was returned by
+// the previous Migration. If the last Migration returns a []byte, the bytes are
+// decoded using the registered Decoder.
+func RegisterMigration(r *Registry, name string, from int, fn func(old any) (any, error)) {
+	r.Lock()
+	defer r.Unlock()
+
+	migrations, ok := r.migrations[name]
+	if !ok {
+		migrations = make(map[int]Migration)
+		r.migrations[name] = migrations
+	}
+	migrations[from] = fn
+}
+
+// SchemaVersion returns the current schema version of the data that is
+// registered under the given name. If no migrations are registered for the
+// data, SchemaVersion returns 1.
+func SchemaVersion(r *Registry, name string) int {
+	r.RLock()
+	defer r.RUnlock()
+	return r.schemaVersion(name)
+}
+
+func (reg *Registry) schemaVersion(name string) int {
+	version := 1
+	for from := range reg.migrations[name] {
+		if from+1 > version {
+			version = from + 1
+		}
+	}
+	return version
+}
+
+func migrate[D any](r *Registry, name string, from, to int, b []byte) (D, error) {
+	var zero D
+
+	var data any = b
+	for v := from; v < to; v++ {
+		fn, ok := r.migrations[name][v]
+		if !ok {
+			return zero, fmt.Errorf("missing migration from schema version %d [name=%v]", v, name)
+		}
+
+		var err error
+		if data, err = fn(data); err != nil {
+			return zero, fmt.Errorf("migrate from schema version %d: %w [name=%v]", v, err, name)
+		}
+	}
+
+	if b, ok := data.([]byte); ok {
+		return decode[D](r, bytes.NewReader(b), name)
+	}
+
+	if v, ok := data.(D); ok {
+		return v, nil
+	}
+
+	return zero, fmt.Errorf("cannot cast %T to %T", data, zero)
+}
+
+func writeSchemaVersion(w io.Writer, version int) error {
+	buf := make([]byte, len(schemaVersionHeader)+binary.MaxVarintLen64)
+	n := copy(buf, schemaVersionHeader)
+	n += binary.PutUvarint(buf[n:], uint64(version))
+	_, err := w.Write(buf[:n])
+	return err
+}
+
+// readSchemaVersion reads the schema version of the encoded data in r. If the
+// data is not tagged with a schema version, readSchemaVersion returns version 1
+// together with the bytes that have already been read from r.
+func readSchemaVersion(r io.Reader) (int, []byte, error) {
+	peeked := make([]byte, len(schemaVersionHeader))
+	n, err := io.ReadFull(r, peeked)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return 0, nil, err
+	}
+
+	if n < len(peeked) || !bytes.Equal(peeked, schemaVersionHeader) {
+		return 1, peeked[:n], nil
+	}
+
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = &countingReader{r: r}
+	}
+
+	version, err := binary.ReadUvarint(br)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return int(version), nil, nil
+}
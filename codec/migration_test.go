@@ -0,0 +1,131 @@
+package codec_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/modernice/goes/codec"
+)
+
+type mockDataV1 struct {
+	Name string
+}
+
+type mockDataV2 struct {
+	FirstName string
+	LastName  string
+}
+
+type mockDataV3 struct {
+	FirstName string
+	LastName  string
+	Age       int
+}
+
+func TestRegisterMigration(t *testing.T) {
+	old := codec.JSON(codec.New())
+	codec.JSONRegister[mockDataV1](old, "foo")
+
+	var buf bytes.Buffer
+	if err := old.Encode(&buf, "foo", mockDataV1{Name: "Bob Foo"}); err != nil {
+		t.Fatalf("Encode() failed with %q", err)
+	}
+
+	reg := codec.JSON(codec.New())
+	codec.JSONRegister[mockDataV2](reg, "foo")
+	codec.RegisterMigration(reg.Registry, "foo", 1, migrateV1)
+
+	if v := codec.SchemaVersion(reg.Registry, "foo"); v != 2 {
+		t.Fatalf("SchemaVersion() should return %d; got %d", 2, v)
+	}
+
+	decoded, err := reg.Decode(&buf, "foo")
+	if err != nil {
+		t.Fatalf("Decode() failed with %q", err)
+	}
+
+	want := mockDataV2{FirstName: "Bob", LastName: "Foo"}
+	if decoded != want {
+		t.Fatalf("decoded data should be %v; is %v\n%s", want, decoded, cmp.Diff(want, decoded))
+	}
+}
+
+func TestRegisterMigration_encodeNotFound(t *testing.T) {
+	reg := codec.New()
+	codec.RegisterMigration(reg, "foo", 1, migrateV1)
+
+	var buf bytes.Buffer
+	if err := reg.Encode(&buf, "foo", mockDataV2{}); !errors.Is(err, codec.ErrNotFound) {
+		t.Fatalf("Encode() should fail with %q; got %v", codec.ErrNotFound, err)
+	}
+
+	if buf.Len() != 0 {
+		t.Fatalf("Encode() should not write to the writer if it fails with %q; wrote %d bytes", codec.ErrNotFound, buf.Len())
+	}
+}
+
+func TestRegisterMigration_chain(t *testing.T) {
+	old := codec.JSON(codec.New())
+	codec.JSONRegister[mockDataV1](old, "foo")
+
+	var buf bytes.Buffer
+	if err := old.Encode(&buf, "foo", mockDataV1{Name: "Bob Foo"}); err != nil {
+		t.Fatalf("Encode() failed with %q", err)
+	}
+
+	reg := codec.JSON(codec.New())
+	codec.JSONRegister[mockDataV3](reg, "foo")
+	codec.RegisterMigration(reg.Registry, "foo", 1, migrateV1)
+	codec.RegisterMigration(reg.Registry, "foo", 2, func(old any) (any, error) {
+		v2 := old.(mockDataV2)
+		return mockDataV3{FirstName: v2.FirstName, LastName: v2.LastName, Age: 42}, nil
+	})
+
+	decoded, err := reg.Decode(&buf, "foo")
+	if err != nil {
+		t.Fatalf("Decode() failed with %q", err)
+	}
+
+	want := mockDataV3{FirstName: "Bob", LastName: "Foo", Age: 42}
+	if decoded != want {
+		t.Fatalf("decoded data should be %v; is %v\n%s", want, decoded, cmp.Diff(want, decoded))
+	}
+}
+
+func TestRegisterMigration_currentVersion(t *testing.T) {
+	reg := codec.JSON(codec.New())
+	codec.JSONRegister[mockDataV2](reg, "foo")
+	codec.RegisterMigration(reg.Registry, "foo", 1, func(any) (any, error) {
+		t.Fatalf("migration should not be called for data with the current schema version")
+		return nil, nil
+	})
+
+	want := mockDataV2{FirstName: "Bob", LastName: "Foo"}
+
+	var buf bytes.Buffer
+	if err := reg.Encode(&buf, "foo", want); err != nil {
+		t.Fatalf("Encode() failed with %q", err)
+	}
+
+	decoded, err := reg.Decode(&buf, "foo")
+	if err != nil {
+		t.Fatalf("Decode() failed with %q", err)
+	}
+
+	if decoded != want {
+		t.Fatalf("decoded data should be %v; is %v\n%s", want, decoded, cmp.Diff(want, decoded))
+	}
+}
+
+func migrateV1(old any) (any, error) {
+	var v1 mockDataV1
+	if err := json.Unmarshal(old.([]byte), &v1); err != nil {
+		return nil, err
+	}
+	first, last, _ := strings.Cut(v1.Name, " ")
+	return mockDataV2{FirstName: first, LastName: last}, nil
+}
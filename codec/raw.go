@@ -0,0 +1,37 @@
+package codec
+
+import "io"
+
+// RawPayload is the data that is returned by the RawDecoder. It contains the
+// encoded data as it was read, without decoding it.
+//
+// RawPayload implements encoding.BinaryMarshaler, so that it is encoded as the
+// original bytes. Data that was decoded by a fallback RawDecoder can therefore
+// be encoded again without a registered Encoder, unless StrictEncoding is
+// enabled.
+type RawPayload struct {
+	Data []byte
+}
+
+// RawDecoder returns a Decoder that reads all data into a RawPayload. Use it
+// as the fallback decoder of a Registry to decode data that is not registered,
+// instead of failing with ErrNotFound:
+//
+//	reg := codec.New()
+//	reg.SetFallback(codec.RawDecoder())
+//	data, err := reg.Decode(r, "unknown")
+//	// data is a codec.RawPayload
+func RawDecoder() Decoder[any] {
+	return DecoderFunc[any](func(r io.Reader) (any, error) {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		return RawPayload{Data: b}, nil
+	})
+}
+
+// MarshalBinary returns the raw data.
+func (p RawPayload) MarshalBinary() ([]byte, error) {
+	return p.Data, nil
+}
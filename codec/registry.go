@@ -1,10 +1,12 @@
 package codec
 
 import (
-	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"reflect"
+	"sort"
 	"sync"
 )
 
@@ -16,6 +18,17 @@ var (
 	// ErrMissingFactory is returned when trying to instantiate data for which
 	// no factory function was provided.
 	ErrMissingFactory = errors.New("missing factory for data. forgot to register?")
+
+	// ErrAlreadyRegistered is returned by RegisterStrict, GobRegisterStrict and
+	// JSONRegisterStrict when data has already been registered under the given
+	// name.
+	ErrAlreadyRegistered = errors.New("data already registered")
+
+	// ErrUndelimited is returned by DecodeN for data whose encoded form has no
+	// end that a decoder could detect, which is the case for data that is
+	// encoded by its encoding.BinaryMarshaler or encoding.TextMarshaler and for
+	// data that is registered via a BinaryRegistry.
+	ErrUndelimited = errors.New("encoded data cannot be delimited")
 )
 
 // A Registry provides the Encoders and Decoders for event data or command
@@ -48,6 +61,28 @@ type Registry struct {
 	encoders  map[string]Encoder[any]
 	decoders  map[string]Decoder[any]
 	factories map[string]func() any
+	aliases   map[string]string
+
+	migrations map[string]map[int]Migration
+
+	fallback Decoder[any]
+	strict   bool
+}
+
+// Option is an option for a Registry.
+type Option func(*Registry)
+
+// StrictEncoding returns an Option that makes Encode fail with an error that
+// unwraps to ErrNotFound if no data is registered under the given name, even
+// if the data implements encoding.BinaryMarshaler or encoding.TextMarshaler.
+// Without strict encoding, such data is encoded using its own marshaler,
+// whether it is registered or not, and forgetting to register it is only
+// detected when decoding the data fails. Strict encoding is disabled by
+// default.
+func StrictEncoding(v bool) Option {
+	return func(r *Registry) {
+		r.strict = v
+	}
 }
 
 // Make creates and returns a new instance of the data that is registered under
@@ -60,6 +95,8 @@ func Make[D any](r *Registry, name string) (D, error) {
 	r.RLock()
 	defer r.RUnlock()
 
+	name = r.resolve(name)
+
 	if makeFunc, ok := r.factories[name]; ok && makeFunc != nil {
 		d := makeFunc()
 		if v, ok := d.(D); ok {
@@ -80,17 +117,54 @@ func Register[D any, Enc Encoder[D], Dec Decoder[D]](r *Registry, name string, e
 	})
 }
 
+// RegisterStrict registers the encoding for events with the given name, like
+// Register does. If data has already been registered under the given name,
+// RegisterStrict does not overwrite it and returns an error that unwraps to
+// ErrAlreadyRegistered instead. Use RegisterStrict to detect packages that
+// accidentally register different data under the same name.
+//
+// Use GobRegisterStrict and JSONRegisterStrict to register data that is
+// encoded using encoding/gob or encoding/json, e.g. command payloads.
+func RegisterStrict[D any, Enc Encoder[D], Dec Decoder[D]](r *Registry, name string, enc Enc, dec Dec) error {
+	return registerStrictWithFactoryFunc[D](r, name, enc, dec, func() any {
+		var v D
+		return v
+	})
+}
+
 func registerWithFactoryFunc[D any, Enc Encoder[D], Dec Decoder[D]](r *Registry, name string, enc Enc, dec Dec, fn func() any) {
 	r.Lock()
 	defer r.Unlock()
+	register[D](r, name, enc, dec, fn)
+}
+
+func registerStrictWithFactoryFunc[D any, Enc Encoder[D], Dec Decoder[D]](r *Registry, name string, enc Enc, dec Dec, fn func() any) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.encoders[name]; ok {
+		return fmt.Errorf("%w [name=%v]", ErrAlreadyRegistered, name)
+	}
+
+	register[D](r, name, enc, dec, fn)
+
+	return nil
+}
 
+func register[D any, Enc Encoder[D], Dec Decoder[D]](r *Registry, name string, enc Enc, dec Dec, fn func() any) {
 	r.encoders[name] = EncoderFunc[any](func(w io.Writer, data any) error {
 		return enc.Encode(w, data.(D))
 	})
 
-	r.decoders[name] = DecoderFunc[any](func(r io.Reader) (any, error) {
-		return dec.Decode(r)
-	})
+	// Decoders of untyped data are stored as is, so that isUndelimited can
+	// detect the Decoder of a BinaryRegistry.
+	if d, ok := any(dec).(Decoder[any]); ok {
+		r.decoders[name] = d
+	} else {
+		r.decoders[name] = DecoderFunc[any](func(r io.Reader) (any, error) {
+			return dec.Decode(r)
+		})
+	}
 
 	r.factories[name] = fn
 }
@@ -99,46 +173,113 @@ func registerWithFactoryFunc[D any, Enc Encoder[D], Dec Decoder[D]](r *Registry,
 // registered Encoder. If no Encoder is registered for the given name, an error
 // that unwraps to ErrNotFound is returned.
 func Encode[D any](r *Registry, w io.Writer, name string, data D) error {
+	return EncodeContext(context.Background(), r, w, name, data)
+}
+
+// EncodeContext encodes data like Encode does, but aborts the encoding when
+// ctx is canceled. The Context is checked before every write to w, so that
+// large payloads that are written to a slow io.Writer in multiple chunks can
+// be canceled. If ctx is canceled, an error that unwraps to ctx.Err() is
+// returned.
+func EncodeContext[D any](ctx context.Context, r *Registry, w io.Writer, name string, data D) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if ctx.Done() != nil {
+		w = ctxWriter{ctx: ctx, w: w}
+	}
+
 	r.RLock()
 	defer r.RUnlock()
 
-	if err := encodeCustomMarshaler(w, data); !errors.Is(err, errNotCustomMarshaler) {
-		return err
+	name = r.resolve(name)
+
+	// Check if the data can be encoded before writing the schema version, so
+	// that nothing is written to w if it cannot.
+	enc, registered := r.encoders[name]
+	if !registered && (r.strict || !isCustomMarshaler(data)) {
+		return fmt.Errorf("get encoder: %w [name=%v]", ErrNotFound, name)
 	}
 
-	if enc, ok := r.encoders[name]; ok {
-		return enc.Encode(w, data)
+	if v := r.schemaVersion(name); v > 1 {
+		if err := writeSchemaVersion(w, v); err != nil {
+			return fmt.Errorf("write schema version: %w", err)
+		}
+	}
+
+	if err := encodeCustomMarshaler(w, data); !errors.Is(err, errNotCustomMarshaler) {
+		return err
 	}
 
-	return fmt.Errorf("get encoder: %w [name=%v]", ErrNotFound, name)
+	return enc.Encode(w, data)
 }
 
 // Decode decodes the data that is registered under the given name using the
-// registered Decoder. If no Decoder is registered for the give name, an error
-// that unwraps to ErrNotFound is returned.
+// registered Decoder. If no Decoder is registered for the give name, the
+// fallback Decoder of the Registry is used. If the Registry has no fallback
+// Decoder either, an error that unwraps to ErrNotFound is returned.
 func Decode[D any](r *Registry, in io.Reader, name string) (D, error) {
+	return DecodeContext[D](context.Background(), r, in, name)
+}
+
+// DecodeContext decodes data like Decode does, but aborts the decoding when ctx
+// is canceled. The Context is checked before every read from in, which also
+// applies to custom unmarshalers that read all of in at once. If ctx is
+// canceled, an error that unwraps to ctx.Err() is returned.
+func DecodeContext[D any](ctx context.Context, r *Registry, in io.Reader, name string) (D, error) {
 	var zero D
 
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	if ctx.Done() != nil {
+		in = ctxReader{ctx: ctx, r: in}
+	}
+
 	r.RLock()
 	defer r.RUnlock()
 
+	name = r.resolve(name)
+
+	if current := r.schemaVersion(name); current > 1 {
+		version, peeked, err := readSchemaVersion(in)
+		if err != nil {
+			return zero, fmt.Errorf("read schema version: %w", err)
+		}
+
+		if version > current {
+			return zero, fmt.Errorf("unknown schema version %d [name=%v, current=%d]", version, name, current)
+		}
+
+		if version < current {
+			rest, err := io.ReadAll(in)
+			if err != nil {
+				return zero, fmt.Errorf("reader: %w", err)
+			}
+			return migrate[D](r, name, version, current, append(peeked, rest...))
+		}
+	}
+
+	return decode[D](r, in, name)
+}
+
+func decode[D any](r *Registry, in io.Reader, name string) (D, error) {
+	var zero D
+
 	if _, ok := r.factories[name]; ok {
 		data, err := Make[D](r, name)
 		if err != nil {
 			return zero, err
 		}
 
-		var buf bytes.Buffer
-		in = io.TeeReader(in, &buf)
-
 		if err := decodeCustomMarshaler(in, &data); err != errNotCustomMarshaler {
 			if err != nil {
 				err = fmt.Errorf("custom unmarshaler: %w", err)
 			}
 			return data, err
 		}
-
-		in = &buf
 	}
 
 	if dec, ok := r.decoders[name]; ok {
@@ -149,16 +290,220 @@ func Decode[D any](r *Registry, in io.Reader, name string) (D, error) {
 		return decoded.(D), nil
 	}
 
+	if r.fallback != nil {
+		decoded, err := r.fallback.Decode(in)
+		if err != nil {
+			return zero, fmt.Errorf("fallback decoder: %w [name=%v]", err, name)
+		}
+
+		data, ok := decoded.(D)
+		if !ok {
+			return zero, fmt.Errorf("cannot cast %T to %T [name=%v]", decoded, zero, name)
+		}
+
+		return data, nil
+	}
+
 	return zero, fmt.Errorf("get decoder: %w [name=%v]", ErrNotFound, name)
 }
 
+// SetFallback sets the Decoder that is used to decode data whose name is not
+// registered. Without a fallback Decoder, decoding unregistered data fails
+// with ErrNotFound, which aborts the consumption of events from other services
+// that are not registered locally. RawDecoder returns a Decoder that decodes
+// such data into a RawPayload, so that it can be skipped or logged:
+//
+//	reg.SetFallback(codec.RawDecoder())
+//
+// A nil Decoder removes the fallback Decoder.
+func (reg *Registry) SetFallback(dec Decoder[any]) {
+	reg.Lock()
+	defer reg.Unlock()
+	reg.fallback = dec
+}
+
+// Alias makes the data that is registered under newName also available under
+// oldName. Encoding, decoding and instantiating data under oldName uses the
+// Encoder, Decoder and factory function that are registered under newName, so
+// that Decode(oldName) returns the same type as Decode(newName). Use Alias to
+// rename data without re-encoding data that was stored under the old name:
+//
+//	reg := codec.JSON(codec.New())
+//	codec.JSONRegister[AccountCreated](reg, "account.created")
+//	codec.Alias(reg.Registry, "account.created", "user.created")
+//	data, err := reg.Decode(r, "user.created") // AccountCreated
+//
+// newName does not need to be registered yet when Alias is called. Data that
+// is registered under oldName itself is shadowed by the alias. If newName is
+// an alias itself, oldName becomes an alias of the name that newName refers
+// to. Registered does not return aliases.
+//
+// Data that is registered using (*GobRegistry).GobRegister is encoded
+// together with its gob name, which contains the registered name by default.
+// To decode such data under an alias, use a GobNameFunc that returns the gob
+// name that was used to encode the data.
+func Alias(r *Registry, newName, oldName string) {
+	r.Lock()
+	defer r.Unlock()
+	r.aliases[oldName] = r.resolve(newName)
+}
+
+// resolve returns the name that the given alias refers to, or the name itself
+// if it is not an alias.
+func (reg *Registry) resolve(name string) string {
+	if target, ok := reg.aliases[name]; ok {
+		return target
+	}
+	return name
+}
+
+// Unregister removes the Encoder, Decoder and factory function that are
+// registered under the given name, and the alias with the given name.
+// Unregister is a no-op if nothing is registered under the given name.
+func (reg *Registry) Unregister(name string) {
+	reg.Lock()
+	defer reg.Unlock()
+	delete(reg.aliases, name)
+	delete(reg.encoders, name)
+	delete(reg.decoders, name)
+	delete(reg.factories, name)
+	delete(reg.migrations, name)
+}
+
+// Registered returns the sorted names of all registered data.
+func (reg *Registry) Registered() []string {
+	reg.RLock()
+	defer reg.RUnlock()
+	names := make([]string, 0, len(reg.encoders))
+	for name := range reg.encoders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RegisteredType describes data that is registered into a Registry.
+type RegisteredType struct {
+	// Name is the name that the data is registered under.
+	Name string
+
+	// Type is the type of the data that is returned by the factory function
+	// of the registered data. Type is nil if the data was registered without a
+	// factory function, or if the factory function returns a nil interface.
+	Type reflect.Type
+}
+
+// Describe returns the names and types of all registered data, sorted by name.
+// Use Describe to generate documentation of the registered events or commands,
+// or to validate that producers and consumers agree on the registered names.
+// Aliases are not returned.
+func (reg *Registry) Describe() []RegisteredType {
+	reg.RLock()
+	defer reg.RUnlock()
+
+	out := make([]RegisteredType, 0, len(reg.encoders))
+	for name := range reg.encoders {
+		rt := RegisteredType{Name: name}
+		if fn := reg.factories[name]; fn != nil {
+			rt.Type = reflect.TypeOf(fn())
+		}
+		out = append(out, rt)
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].Name < out[b].Name })
+
+	return out
+}
+
+// Clone returns a copy of the Registry. Data that is registered into the
+// returned Registry is not registered into the original Registry and vice versa.
+func (reg *Registry) Clone() *Registry {
+	reg.RLock()
+	defer reg.RUnlock()
+
+	clone := New(StrictEncoding(reg.strict))
+	clone.fallback = reg.fallback
+	for name, enc := range reg.encoders {
+		clone.encoders[name] = enc
+	}
+	for name, dec := range reg.decoders {
+		clone.decoders[name] = dec
+	}
+	for name, fn := range reg.factories {
+		clone.factories[name] = fn
+	}
+	for alias, name := range reg.aliases {
+		clone.aliases[alias] = name
+	}
+	for name, migrations := range reg.migrations {
+		clone.migrations[name] = make(map[int]Migration, len(migrations))
+		for from, fn := range migrations {
+			clone.migrations[name][from] = fn
+		}
+	}
+
+	return clone
+}
+
+// DecodeN decodes a single value that is registered under the given name from
+// in and returns the decoded value together with the number of bytes that were
+// read from in. In contrast to Decode, DecodeN does not read beyond the end of
+// the decoded value, which allows to decode multiple values that were encoded
+// back-to-back into the same buffer:
+//
+//	var buf bytes.Buffer
+//	reg.Encode(&buf, "foo", fooData{...})
+//	reg.Encode(&buf, "bar", barData{...})
+//	foo, n, err := codec.DecodeN[any](reg, &buf, "foo")
+//	bar, m, err := codec.DecodeN[any](reg, &buf, "bar")
+//
+// This works for data that is registered via a GobRegistry or a JSONRegistry.
+// Data that implements encoding.BinaryMarshaler, encoding.TextMarshaler or
+// their Unmarshaler counterparts is encoded without a delimiter, as is data
+// that is registered via a BinaryRegistry. For such data, DecodeN returns an
+// error that unwraps to ErrUndelimited without reading from in. Custom
+// Decoders must not read beyond the end of a value for DecodeN to work as
+// expected.
+func DecodeN[D any](r *Registry, in io.Reader, name string) (D, int, error) {
+	if isUndelimited[D](r, name) {
+		var zero D
+		return zero, 0, fmt.Errorf("%w [name=%v]", ErrUndelimited, name)
+	}
+
+	// Decoders like encoding/json buffer their input, so the decoder must
+	// only be able to read a single byte at a time.
+	cr := &countingReader{r: in}
+	data, err := Decode[D](r, byteWiseReader{cr}, name)
+	return data, cr.n, err
+}
+
+// isUndelimited returns whether the data that is registered under the given
+// name is decoded by reading all remaining input.
+func isUndelimited[D any](r *Registry, name string) bool {
+	r.RLock()
+	_, binary := r.decoders[r.resolve(name)].(binaryDecoder)
+	r.RUnlock()
+
+	if binary {
+		return true
+	}
+
+	data, err := Make[D](r, name)
+	return err == nil && (isCustomMarshaler(data) || isCustomUnmarshaler(&data))
+}
+
 // New returns a new Registry.
-func New() *Registry {
-	return &Registry{
-		encoders:  make(map[string]Encoder[any]),
-		decoders:  make(map[string]Decoder[any]),
-		factories: make(map[string]func() any),
+func New(opts ...Option) *Registry {
+	r := &Registry{
+		encoders:   make(map[string]Encoder[any]),
+		decoders:   make(map[string]Decoder[any]),
+		factories:  make(map[string]func() any),
+		aliases:    make(map[string]string),
+		migrations: make(map[string]map[int]Migration),
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 // // Register registers the given Encoder and Decoder under the given name.
@@ -177,12 +522,25 @@ func (reg *Registry) Encode(w io.Writer, name string, data any) error {
 }
 
 // Decode decodes the data that is registered under the given name using the
-// registered Decoder. If no Decoder is registered for the give name, an error
-// that unwraps to ErrNotFound is returned.
+// registered Decoder. If no Decoder is registered for the give name, the
+// fallback Decoder of the Registry is used. If the Registry has no fallback
+// Decoder either, an error that unwraps to ErrNotFound is returned.
 func (reg *Registry) Decode(r io.Reader, name string) (any, error) {
 	return Decode[any](reg, r, name)
 }
 
+// EncodeContext encodes data like Encode does, but aborts the encoding when
+// ctx is canceled.
+func (reg *Registry) EncodeContext(ctx context.Context, w io.Writer, name string, data any) error {
+	return EncodeContext(ctx, reg, w, name, data)
+}
+
+// DecodeContext decodes data like Decode does, but aborts the decoding when
+// ctx is canceled.
+func (reg *Registry) DecodeContext(ctx context.Context, r io.Reader, name string) (any, error) {
+	return DecodeContext[any](ctx, reg, r, name)
+}
+
 // New creates and returns a new instance of the data that is registered under
 // the given name. If no factory function was provided for this data,
 // ErrMissingFactory is returned.
@@ -2,8 +2,11 @@ package codec_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -67,6 +70,82 @@ func TestRegistry_Encode_Decode(t *testing.T) {
 	}
 }
 
+func TestRegistry_SetFallback(t *testing.T) {
+	reg := codec.New()
+	reg.SetFallback(codec.RawDecoder())
+
+	data, err := reg.Decode(strings.NewReader("foo-data"), "foo")
+	if err != nil {
+		t.Fatalf("Decode() failed with %q", err)
+	}
+
+	want := codec.RawPayload{Data: []byte("foo-data")}
+	if !cmp.Equal(want, data) {
+		t.Fatalf("Decode() should return %v; got %v", want, data)
+	}
+
+	var buf bytes.Buffer
+	if err := reg.Encode(&buf, "foo", data); err != nil {
+		t.Fatalf("Encode() failed with %q", err)
+	}
+
+	if buf.String() != "foo-data" {
+		t.Fatalf("Encode() should write the raw data %q; got %q", "foo-data", buf.String())
+	}
+
+	reg.SetFallback(nil)
+
+	if _, err := reg.Decode(strings.NewReader("foo-data"), "foo"); !errors.Is(err, codec.ErrNotFound) {
+		t.Fatalf("Decode() should fail with %q without a fallback decoder; got %v", codec.ErrNotFound, err)
+	}
+}
+
+func TestRegisterStrict(t *testing.T) {
+	reg := codec.New()
+
+	enc := codec.EncoderFunc[mockDataA](func(w io.Writer, data mockDataA) error {
+		_, err := w.Write([]byte(data.A))
+		return err
+	})
+	dec := codec.DecoderFunc[mockDataA](func(r io.Reader) (mockDataA, error) {
+		b, err := io.ReadAll(r)
+		return mockDataA{A: string(b)}, err
+	})
+	otherEnc := codec.EncoderFunc[mockDataA](func(w io.Writer, data mockDataA) error {
+		_, err := w.Write([]byte("other"))
+		return err
+	})
+
+	if err := codec.RegisterStrict[mockDataA](reg, "foo", enc, dec); err != nil {
+		t.Fatalf("RegisterStrict() failed with %q", err)
+	}
+
+	if err := codec.RegisterStrict[mockDataA](reg, "foo", otherEnc, dec); !errors.Is(err, codec.ErrAlreadyRegistered) {
+		t.Fatalf("RegisterStrict() should fail with %q when registering a name twice; got %v", codec.ErrAlreadyRegistered, err)
+	}
+
+	var buf bytes.Buffer
+	if err := reg.Encode(&buf, "foo", mockDataA{A: "foo"}); err != nil {
+		t.Fatalf("Encode() failed with %q", err)
+	}
+
+	if got := buf.String(); got != "foo" {
+		t.Fatalf("RegisterStrict() should not overwrite the first registration; encoded data is %q", got)
+	}
+
+	// Register still overwrites existing registrations.
+	codec.Register[mockDataA](reg, "foo", otherEnc, dec)
+
+	buf.Reset()
+	if err := reg.Encode(&buf, "foo", mockDataA{A: "foo"}); err != nil {
+		t.Fatalf("Encode() failed with %q", err)
+	}
+
+	if got := buf.String(); got != "other" {
+		t.Fatalf("Register() should overwrite the existing registration; encoded data is %q", got)
+	}
+}
+
 func TestRegistry_New_ErrMissingFactory(t *testing.T) {
 	reg := codec.New()
 
@@ -91,6 +170,344 @@ func TestRegistry_New(t *testing.T) {
 	}
 }
 
+func TestRegistry_Unregister(t *testing.T) {
+	reg := codec.JSON(codec.New())
+	codec.JSONRegister[mockDataA](reg, "foo")
+	codec.JSONRegister[mockDataA](reg, "bar")
+
+	reg.Unregister("foo")
+	reg.Unregister("baz")
+
+	if err := reg.Encode(&bytes.Buffer{}, "foo", mockDataA{}); !errors.Is(err, codec.ErrNotFound) {
+		t.Fatalf("Encode() should fail with %q for unregistered data; got %v", codec.ErrNotFound, err)
+	}
+
+	if _, err := reg.Decode(&bytes.Buffer{}, "foo"); !errors.Is(err, codec.ErrNotFound) {
+		t.Fatalf("Decode() should fail with %q for unregistered data; got %v", codec.ErrNotFound, err)
+	}
+
+	if _, err := reg.New("foo"); !errors.Is(err, codec.ErrMissingFactory) {
+		t.Fatalf("New() should fail with %q for unregistered data; got %v", codec.ErrMissingFactory, err)
+	}
+
+	if err := reg.Encode(&bytes.Buffer{}, "bar", mockDataA{}); err != nil {
+		t.Fatalf("Encode() failed with %q", err)
+	}
+}
+
+func TestRegistry_Registered(t *testing.T) {
+	reg := codec.JSON(codec.New())
+
+	if names := reg.Registered(); len(names) != 0 {
+		t.Fatalf("Registered() should return no names; got %v", names)
+	}
+
+	codec.JSONRegister[mockDataA](reg, "foo")
+	codec.JSONRegister[mockDataA](reg, "baz")
+	codec.JSONRegister[mockDataA](reg, "bar")
+
+	want := []string{"bar", "baz", "foo"}
+	if names := reg.Registered(); !cmp.Equal(want, names) {
+		t.Fatalf("Registered() should return %v; got %v", want, names)
+	}
+
+	reg.Unregister("baz")
+
+	want = []string{"bar", "foo"}
+	if names := reg.Registered(); !cmp.Equal(want, names) {
+		t.Fatalf("Registered() should return %v; got %v", want, names)
+	}
+}
+
+func TestRegistry_Describe(t *testing.T) {
+	reg := codec.JSON(codec.New())
+	codec.JSONRegister[mockDataA](reg, "foo")
+	codec.JSONRegister[mockJSONData](reg, "bar")
+	codec.Alias(reg.Registry, "foo", "baz")
+
+	types := reg.Describe()
+
+	want := []codec.RegisteredType{
+		{Name: "bar", Type: reflect.TypeOf(mockJSONData{})},
+		{Name: "foo", Type: reflect.TypeOf(mockDataA{})},
+	}
+
+	if len(types) != len(want) {
+		t.Fatalf("Describe() should return %d types; got %d", len(want), len(types))
+	}
+
+	for i, rt := range types {
+		if rt.Name != want[i].Name || rt.Type != want[i].Type {
+			t.Errorf("Describe()[%d] should be %v; got %v", i, want[i], rt)
+		}
+	}
+}
+
+func TestRegistry_Clone(t *testing.T) {
+	reg := codec.JSON(codec.New())
+	codec.JSONRegister[mockDataA](reg, "foo")
+
+	clone := codec.JSON(reg.Clone())
+	codec.JSONRegister[mockDataA](clone, "bar")
+
+	if err := clone.Encode(&bytes.Buffer{}, "foo", mockDataA{}); err != nil {
+		t.Fatalf("Encode() should succeed for data that was registered before cloning; got %q", err)
+	}
+
+	if err := clone.Encode(&bytes.Buffer{}, "bar", mockDataA{}); err != nil {
+		t.Fatalf("Encode() failed with %q", err)
+	}
+
+	if err := reg.Encode(&bytes.Buffer{}, "bar", mockDataA{}); !errors.Is(err, codec.ErrNotFound) {
+		t.Fatalf("Encode() should fail with %q for data that was registered into the clone; got %v", codec.ErrNotFound, err)
+	}
+
+	if _, err := reg.Decode(&bytes.Buffer{}, "bar"); !errors.Is(err, codec.ErrNotFound) {
+		t.Fatalf("Decode() should fail with %q for data that was registered into the clone; got %v", codec.ErrNotFound, err)
+	}
+}
+
+func TestAlias(t *testing.T) {
+	reg := codec.JSON(codec.New())
+	codec.JSONRegister[mockDataA](reg, "account.created")
+
+	var stored bytes.Buffer
+	if err := reg.Encode(&stored, "account.created", mockDataA{A: "foo"}); err != nil {
+		t.Fatalf("Encode() failed with %q", err)
+	}
+
+	if _, err := reg.Decode(bytes.NewReader(stored.Bytes()), "user.created"); !errors.Is(err, codec.ErrNotFound) {
+		t.Fatalf("Decode() should fail with %q before the alias is registered; got %v", codec.ErrNotFound, err)
+	}
+
+	codec.Alias(reg.Registry, "account.created", "user.created")
+
+	decoded, err := reg.Decode(bytes.NewReader(stored.Bytes()), "user.created")
+	if err != nil {
+		t.Fatalf("Decode() failed with %q", err)
+	}
+
+	if want := (mockDataA{A: "foo"}); decoded != want {
+		t.Fatalf("decoded data should be %v; is %v", want, decoded)
+	}
+
+	if err := reg.Encode(&bytes.Buffer{}, "user.created", mockDataA{}); err != nil {
+		t.Fatalf("Encode() should succeed for an alias; got %q", err)
+	}
+
+	if v, err := reg.New("user.created"); err != nil {
+		t.Fatalf("New() failed with %q", err)
+	} else if _, ok := v.(mockDataA); !ok {
+		t.Fatalf("New() should return %T for an alias; got %T", mockDataA{}, v)
+	}
+
+	want := []string{"account.created"}
+	if names := reg.Registered(); !cmp.Equal(want, names) {
+		t.Fatalf("Registered() should not return aliases; got %v", names)
+	}
+}
+
+func TestAlias_chain(t *testing.T) {
+	reg := codec.JSON(codec.New())
+	codec.Alias(reg.Registry, "foo", "bar")
+	codec.Alias(reg.Registry, "bar", "baz")
+	codec.JSONRegister[mockDataA](reg, "foo")
+
+	if v, err := reg.New("baz"); err != nil {
+		t.Fatalf("New() failed with %q", err)
+	} else if _, ok := v.(mockDataA); !ok {
+		t.Fatalf("New() should return %T for an alias of an alias; got %T", mockDataA{}, v)
+	}
+}
+
+func TestStrictEncoding(t *testing.T) {
+	reg := codec.New()
+
+	if err := reg.Encode(&bytes.Buffer{}, "foo", mockBinaryData{A: "foo"}); err != nil {
+		t.Fatalf("Encode() should use the custom marshaler of unregistered data by default; got %q", err)
+	}
+
+	reg = codec.New(codec.StrictEncoding(true))
+
+	if err := reg.Encode(&bytes.Buffer{}, "foo", mockBinaryData{A: "foo"}); !errors.Is(err, codec.ErrNotFound) {
+		t.Fatalf("Encode() should fail with %q for unregistered data; got %v", codec.ErrNotFound, err)
+	}
+
+	if err := reg.Clone().Encode(&bytes.Buffer{}, "foo", mockBinaryData{A: "foo"}); !errors.Is(err, codec.ErrNotFound) {
+		t.Fatalf("cloned Registry should keep strict encoding; got %v", err)
+	}
+
+	codec.JSON(reg).JSONRegister("foo", func() any { return mockBinaryData{} })
+
+	var buf bytes.Buffer
+	if err := reg.Encode(&buf, "foo", mockBinaryData{A: "foo"}); err != nil {
+		t.Fatalf("Encode() failed with %q", err)
+	}
+
+	if got := buf.String(); got != "foo" {
+		t.Fatalf("registered data should be encoded using its custom marshaler; got %q", got)
+	}
+}
+
+func TestDecodeN_undelimited(t *testing.T) {
+	tests := map[string]func(*codec.Registry){
+		"custom marshaler": func(reg *codec.Registry) {
+			codec.JSON(reg).JSONRegister("foo", func() any { return mockBinaryData{} })
+		},
+		"binary registry": func(reg *codec.Registry) {
+			codec.Binary(reg).BinaryRegister("foo", func() any { return mockMessage{} })
+		},
+	}
+
+	for name, register := range tests {
+		t.Run(name, func(t *testing.T) {
+			reg := codec.New()
+			register(reg)
+
+			buf := bytes.NewBufferString("foobar")
+
+			if _, _, err := codec.DecodeN[any](reg, buf, "foo"); !errors.Is(err, codec.ErrUndelimited) {
+				t.Fatalf("DecodeN() should fail with %q; got %v", codec.ErrUndelimited, err)
+			}
+
+			if buf.Len() != len("foobar") {
+				t.Fatalf("DecodeN() should not read from its input; %d bytes remaining", buf.Len())
+			}
+		})
+	}
+}
+
+type mockBinaryData struct {
+	A string
+}
+
+func (data mockBinaryData) MarshalBinary() ([]byte, error) {
+	return []byte(data.A), nil
+}
+
+func (data *mockBinaryData) UnmarshalBinary(b []byte) error {
+	data.A = string(b)
+	return nil
+}
+
+func TestEncodeContext_canceled(t *testing.T) {
+	reg := codec.JSON(codec.New())
+	codec.JSONRegister[mockDataA](reg, "foo")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var buf bytes.Buffer
+	if err := reg.EncodeContext(ctx, &buf, "foo", mockDataA{A: "foo"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("EncodeContext() should fail with %q; got %v", context.Canceled, err)
+	}
+
+	if buf.Len() != 0 {
+		t.Fatalf("EncodeContext() should not write to a canceled writer; wrote %q", buf.String())
+	}
+}
+
+func TestDecodeContext(t *testing.T) {
+	reg := codec.JSON(codec.New())
+	codec.JSONRegister[mockBinaryData](reg, "foo")
+
+	decoded, err := codec.DecodeContext[mockBinaryData](context.Background(), reg.Registry, strings.NewReader("foo"), "foo")
+	if err != nil {
+		t.Fatalf("DecodeContext() failed with %q", err)
+	}
+
+	if want := (mockBinaryData{A: "foo"}); decoded != want {
+		t.Fatalf("decoded data should be %v; is %v", want, decoded)
+	}
+}
+
+func TestDecodeContext_canceled(t *testing.T) {
+	reg := codec.JSON(codec.New())
+	codec.JSONRegister[mockBinaryData](reg, "foo")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The reader cancels the Context after the first chunk has been read,
+	// before the custom unmarshaler has read the whole payload.
+	r := &chunkReader{chunks: []string{"foo", "bar", "baz"}, afterFirst: cancel}
+
+	if _, err := codec.DecodeContext[mockBinaryData](ctx, reg.Registry, r, "foo"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("DecodeContext() should fail with %q; got %v", context.Canceled, err)
+	}
+
+	if len(r.chunks) != 2 {
+		t.Fatalf("DecodeContext() should stop reading after cancellation; %d chunks left", len(r.chunks))
+	}
+}
+
+type chunkReader struct {
+	chunks     []string
+	afterFirst func()
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if len(r.chunks) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, r.chunks[0])
+	r.chunks = r.chunks[1:]
+	if r.afterFirst != nil {
+		r.afterFirst()
+		r.afterFirst = nil
+	}
+	return n, nil
+}
+
 type mockDataA struct {
 	A string
 }
+
+func TestDecodeN(t *testing.T) {
+	tests := map[string]func(*codec.Registry){
+		"gob": func(reg *codec.Registry) {
+			codec.GobRegister[mockDataA](codec.Gob(reg), "foo")
+		},
+		"json": func(reg *codec.Registry) {
+			codec.JSONRegister[mockDataA](codec.JSON(reg), "foo")
+		},
+	}
+
+	for name, register := range tests {
+		t.Run(name, func(t *testing.T) {
+			reg := codec.New()
+			register(reg)
+
+			want := []mockDataA{{A: "foo"}, {A: "bar"}, {A: "baz"}}
+
+			var buf bytes.Buffer
+			for _, data := range want {
+				if err := reg.Encode(&buf, "foo", data); err != nil {
+					t.Fatalf("Encode() failed with %q", err)
+				}
+			}
+			size := buf.Len()
+
+			var total int
+			for _, data := range want {
+				decoded, n, err := codec.DecodeN[mockDataA](reg, &buf, "foo")
+				if err != nil {
+					t.Fatalf("DecodeN() failed with %q", err)
+				}
+
+				if decoded != data {
+					t.Fatalf("decoded data should be %v; is %v\n%s", data, decoded, cmp.Diff(data, decoded))
+				}
+
+				if n <= 0 {
+					t.Fatalf("DecodeN() should return the number of consumed bytes; got %d", n)
+				}
+				total += n
+			}
+
+			if total+buf.Len() != size {
+				t.Fatalf("consumed bytes and remaining bytes should add up to %d; got %d + %d", size, total, buf.Len())
+			}
+		})
+	}
+}
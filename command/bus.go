@@ -2,6 +2,7 @@ package command
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/modernice/goes/command/cmdbus/report"
@@ -63,6 +64,16 @@ type DispatchConfig struct {
 	//
 	// A non-nil Reporter makes the dispatch synchronous.
 	Reporter Reporter
+
+	// If ExpireAfter is positive, the Command expires when it is not passed
+	// to its handler within the given Duration after the dispatch. Expired
+	// Commands are dropped instead of being executed.
+	ExpireAfter time.Duration
+
+	// CorrelationID is the correlation id of the dispatched Command. If
+	// CorrelationID is uuid.Nil, the correlation id of the dispatch Context is
+	// used, or the id of the Command itself if the Context has none.
+	CorrelationID uuid.UUID
 }
 
 // A Reporter reports execution results of a Command.
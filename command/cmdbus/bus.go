@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"sync"
 	"time"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/modernice/goes/event/handler"
 	"github.com/modernice/goes/helper/streams"
 	"github.com/modernice/goes/internal/concurrent"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var _ command.Bus = (*Bus)(nil)
@@ -36,6 +38,10 @@ const (
 	// command bus returns an error that unwraps to ErrReceiveTimeout.
 	// The default timeout is 10s. A zero Duration means no timeout.
 	DefaultReceiveTimeout = 10 * time.Second
+
+	// metricsBufferSize is the number of execution results that are buffered
+	// for the metrics callback before new results are dropped.
+	metricsBufferSize = 1024
 )
 
 var (
@@ -43,6 +49,18 @@ var (
 	// to a Handler before a given deadline.
 	ErrAssignTimeout = errors.New("failed to assign command because of timeout")
 
+	// ErrExecutionTimeout is returned by a Bus when a synchronously dispatched
+	// Command is not executed before the configured ExecutionTimeout.
+	ErrExecutionTimeout = errors.New("command was not executed before timeout")
+
+	// ErrStopped is returned by a Bus when it is stopped while waiting for a
+	// dispatched Command to be accepted or executed.
+	ErrStopped = errors.New("command bus stopped")
+
+	// ErrExpired is returned by a Bus when it drops a Command because the
+	// Command has not been passed to its handler before its deadline.
+	ErrExpired = errors.New("command dropped because it expired")
+
 	// ErrReceiveTimeout is emitted by a Bus when the DrainTimeout is exceeded
 	// when receiving remaining Commands from a canceled Command subscription.
 	ErrReceiveTimeout = errors.New("command dropped because of receive timeout")
@@ -65,14 +83,22 @@ type Bus struct {
 
 	subMux        sync.RWMutex
 	subscriptions map[string]*subscription
-	requested     map[uuid.UUID]command.Cmd[any]
+	requested     map[uuid.UUID]requestedCommand
 
 	dispatchMux sync.RWMutex
 	dispatched  map[uuid.UUID]dispatcher
 	assigned    map[uuid.UUID]dispatcher
 
-	assignTimeout  time.Duration
-	receiveTimeout time.Duration
+	assignTimeout    time.Duration
+	receiveTimeout   time.Duration
+	executionTimeout time.Duration
+
+	metrics     func(string, time.Duration, error)
+	metricsChan chan executionMetric
+
+	deadLetter bool
+
+	tracer trace.Tracer
 
 	enc codec.Encoding
 	bus event.Bus
@@ -82,11 +108,28 @@ type Bus struct {
 	fail func(error)
 }
 
+type executionMetric struct {
+	name    string
+	runtime time.Duration
+	err     error
+}
+
 type subscription struct {
 	commands chan command.Context
 	errs     chan error
 }
 
+type requestedCommand struct {
+	cmd           command.Cmd[any]
+	deadline      time.Time
+	correlationID uuid.UUID
+	traceContext  map[string]string
+}
+
+func (c requestedCommand) expired() bool {
+	return !c.deadline.IsZero() && !time.Now().Before(c.deadline)
+}
+
 type dispatcher struct {
 	cmd             command.Command
 	cfg             command.DispatchConfig
@@ -120,6 +163,60 @@ func ReceiveTimeout(dur time.Duration) Option {
 	}
 }
 
+// ExecutionTimeout returns an Option that configures the timeout for the
+// execution of synchronously dispatched Commands. If a Command that has been
+// accepted by a handler is not executed within the configured timeout,
+// Dispatch returns an error that unwraps to ErrExecutionTimeout.
+//
+// A zero Duration means no timeout, which is the default.
+func ExecutionTimeout(dur time.Duration) Option {
+	return func(b *Bus) {
+		b.executionTimeout = dur
+	}
+}
+
+// WithMetrics returns an Option that registers a callback for the execution
+// results of commands. The callback is called with the name, runtime and
+// execution error of a command whenever the Bus receives a CommandExecuted
+// event, including events of commands that were dispatched by other Buses.
+//
+// The callback is called from a dedicated goroutine so that it never blocks
+// the Bus. Up to 1024 execution results are buffered; when the buffer is full
+// because the callback is too slow, new results are dropped.
+func WithMetrics(fn func(name string, runtime time.Duration, err error)) Option {
+	return func(b *Bus) {
+		b.metrics = fn
+	}
+}
+
+// WithTracing returns an Option that enables OpenTelemetry tracing for the Bus.
+// When a Command is dispatched, the span context of the dispatch Context is
+// injected into the CommandDispatched event. The Bus that handles the Command
+// extracts the span context and starts a child span that lasts until the
+// handler finishes the Command. The span records the name, runtime and
+// execution error of the Command, and the Context that is passed to the
+// handler carries the span, so that spans started by the handler become its
+// children.
+//
+// Both the dispatching and the handling Bus must have tracing enabled for
+// spans to be linked across Buses.
+func WithTracing(tracer trace.Tracer) Option {
+	return func(b *Bus) {
+		b.tracer = tracer
+	}
+}
+
+// WithDeadLetter returns an Option that enables the dead-letter mechanism of
+// the Bus. When enabled, the Bus publishes a CommandDropped event for every
+// dispatched Command that could not be assigned to a Handler within the
+// AssignTimeout. The event contains the encoded Command, so that a dead-letter
+// consumer can inspect or re-dispatch it.
+func WithDeadLetter(v bool) Option {
+	return func(b *Bus) {
+		b.deadLetter = v
+	}
+}
+
 // Deprecated: Use ReceiveTimeout instead.
 func DrainTimeout(dur time.Duration) Option {
 	return ReceiveTimeout(dur)
@@ -130,7 +227,7 @@ func New(enc codec.Encoding, events event.Bus, opts ...Option) *Bus {
 	b := &Bus{
 		Handler:        handler.New(events),
 		subscriptions:  make(map[string]*subscription),
-		requested:      make(map[uuid.UUID]command.Cmd[any]),
+		requested:      make(map[uuid.UUID]requestedCommand),
 		dispatched:     make(map[uuid.UUID]dispatcher),
 		assigned:       make(map[uuid.UUID]dispatcher),
 		assignTimeout:  DefaultAssignTimeout,
@@ -143,6 +240,10 @@ func New(enc codec.Encoding, events event.Bus, opts ...Option) *Bus {
 		opt(b)
 	}
 
+	if b.metrics != nil {
+		b.metricsChan = make(chan executionMetric, metricsBufferSize)
+	}
+
 	event.HandleWith(b, b.commandDispatched, CommandDispatched)
 	event.HandleWith(b, b.commandRequested, CommandRequested)
 	event.HandleWith(b, b.commandAssigned, CommandAssigned)
@@ -164,6 +265,10 @@ func (b *Bus) Run(ctx context.Context) (<-chan error, error) {
 	b.errs, b.fail = concurrent.Errors(ctx)
 	out, _ := streams.FanIn(b.errs, errs)
 
+	if b.metrics != nil {
+		go b.reportMetrics(ctx)
+	}
+
 	return out, nil
 }
 
@@ -213,6 +318,12 @@ func (b *Bus) Run(ctx context.Context) (<-chan error, error) {
 //		log.Println(execError.Err)
 //	}
 //
+// A dispatch fails with an error that unwraps to ErrAssignTimeout if no handler
+// accepts the Command within the AssignTimeout. A synchronous dispatch fails
+// with an error that unwraps to ErrExecutionTimeout if the accepted Command is
+// not executed within the ExecutionTimeout. If the Bus is stopped while
+// waiting, Dispatch fails with an error that unwraps to ErrStopped.
+//
 // Execution result
 //
 // By default, Dispatch does not return information about the execution of a
@@ -239,61 +350,259 @@ func (b *Bus) Dispatch(ctx context.Context, cmd command.Command, opts ...command
 
 	cfg := dispatch.Configure(opts...)
 
-	var load bytes.Buffer
-	if err := b.enc.Encode(&load, cmd.Name(), cmd.Payload()); err != nil {
-		return fmt.Errorf("encode payload: %w", err)
+	evt, err := b.dispatchedEvent(ctx, cmd, cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := b.bus.Publish(ctx, evt); err != nil {
+		return fmt.Errorf("publish %q event: %w", evt.Name(), err)
+	}
+
+	aborted := make(chan struct{})
+	defer close(aborted)
+
+	d := b.registerDispatch(cmd, cfg, aborted)
+	defer b.cleanupDispatch(cmd.ID())
+
+	timeout, stop := b.assignTimer()
+	defer stop()
+
+	return b.await(ctx, d, timeout)
+}
+
+// DispatchMany dispatches multiple Commands at once. The CommandDispatched
+// events of all Commands are published in a single call to the underlying
+// event Bus, and DispatchMany waits until every Command has been accepted by a
+// handler. The configured AssignTimeout applies to the dispatch as a whole.
+//
+// The dispatch options apply to every Command of the batch. A synchronous
+// dispatch waits until every Command has been executed.
+//
+// DispatchMany is not transactional. Each Command is assigned to and executed
+// by its handler independently, and a failed dispatch of one Command does not
+// prevent or undo the execution of the other Commands. If the dispatch of one
+// or more Commands fails, DispatchMany returns a *DispatchManyError that
+// contains the error of each failed Command:
+//
+//	var b *cmdbus.Bus
+//	err := b.DispatchMany(context.TODO(), []command.Command{cmdA, cmdB, cmdC})
+//	var dispatchErr *cmdbus.DispatchManyError
+//	if errors.As(err, &dispatchErr) {
+//		for _, err := range dispatchErr.Errors {
+//			log.Printf("%s: %v", err.Cmd.Name(), err.Err)
+//		}
+//	}
+func (b *Bus) DispatchMany(ctx context.Context, cmds []command.Command, opts ...command.DispatchOption) error {
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	if !b.Running() {
+		errs, err := b.Run(context.Background())
+		if err != nil {
+			return err
+		}
+
+		go logErrors(errs)
+	}
+
+	cfg := dispatch.Configure(opts...)
+
+	events := make([]event.Event, len(cmds))
+	for i, cmd := range cmds {
+		evt, err := b.dispatchedEvent(ctx, cmd, cfg)
+		if err != nil {
+			return fmt.Errorf("%q command: %w", cmd.Name(), err)
+		}
+		events[i] = evt
+	}
+
+	aborted := make(chan struct{})
+	defer close(aborted)
+
+	dispatchers := make([]dispatcher, len(cmds))
+	for i, cmd := range cmds {
+		dispatchers[i] = b.registerDispatch(cmd, cfg, aborted)
+		defer b.cleanupDispatch(cmd.ID())
+	}
+
+	if err := b.bus.Publish(ctx, events...); err != nil {
+		return fmt.Errorf("publish %q events: %w", CommandDispatched, err)
+	}
+
+	timeout, stop := b.assignTimer()
+	defer stop()
+
+	// The Commands are awaited concurrently, so that the execution result of
+	// one Command does not block the results of the others.
+	results := make([]error, len(dispatchers))
+	var wg sync.WaitGroup
+	wg.Add(len(dispatchers))
+	for i, d := range dispatchers {
+		go func(i int, d dispatcher) {
+			defer wg.Done()
+			results[i] = b.await(ctx, d, timeout)
+		}(i, d)
+	}
+	wg.Wait()
+
+	var failed []*DispatchError
+	for i, err := range results {
+		if err != nil {
+			failed = append(failed, &DispatchError{Cmd: dispatchers[i].cmd, Err: err})
+		}
+	}
+
+	if len(failed) > 0 {
+		return &DispatchManyError{Errors: failed}
+	}
+
+	return nil
+}
+
+func (b *Bus) dispatchedEvent(ctx context.Context, cmd command.Command, cfg command.DispatchConfig) (event.Event, error) {
+	load, err := b.encodePayload(cmd)
+	if err != nil {
+		return nil, err
 	}
 
 	id, name := cmd.Aggregate().Split()
 
-	evt := event.New(CommandDispatched, CommandDispatchedData{
+	var deadline time.Time
+	if cfg.ExpireAfter > 0 {
+		deadline = time.Now().Add(cfg.ExpireAfter)
+	}
+
+	correlationID := cfg.CorrelationID
+	if correlationID == uuid.Nil {
+		correlationID = command.CorrelationID(ctx)
+	}
+	if correlationID == uuid.Nil {
+		correlationID = cmd.ID()
+	}
+
+	return event.New(CommandDispatched, CommandDispatchedData{
 		ID:            cmd.ID(),
 		Name:          cmd.Name(),
 		AggregateName: name,
 		AggregateID:   id,
-		Payload:       load.Bytes(),
-	})
+		Payload:       load,
+		Deadline:      deadline,
+		CorrelationID: correlationID,
+		TraceContext:  b.injectTrace(ctx),
+	}).Any(), nil
+}
 
-	if err := b.bus.Publish(ctx, evt.Any()); err != nil {
-		return fmt.Errorf("publish %q event: %w", evt.Name(), err)
+func (b *Bus) droppedEvent(cmd command.Command, reason error) (event.Event, error) {
+	load, err := b.encodePayload(cmd)
+	if err != nil {
+		return nil, err
 	}
 
-	out := make(chan error)
-	accepted := make(chan struct{})
-	aborted := make(chan struct{})
-	defer close(aborted)
+	id, name := cmd.Aggregate().Split()
 
-	b.dispatchMux.Lock()
-	b.dispatched[cmd.ID()] = dispatcher{
+	return event.New(CommandDropped, CommandDroppedData{
+		ID:            cmd.ID(),
+		Name:          cmd.Name(),
+		AggregateName: name,
+		AggregateID:   id,
+		Payload:       load,
+		Reason:        reason.Error(),
+	}).Any(), nil
+}
+
+func (b *Bus) encodePayload(cmd command.Command) ([]byte, error) {
+	var load bytes.Buffer
+	if err := b.enc.Encode(&load, cmd.Name(), cmd.Payload()); err != nil {
+		return nil, fmt.Errorf("encode payload: %w", err)
+	}
+	return load.Bytes(), nil
+}
+
+// drop publishes a CommandDropped event for the given Command if the
+// dead-letter mechanism of the Bus is enabled.
+func (b *Bus) drop(ctx context.Context, cmd command.Command, reason error) {
+	if !b.deadLetter {
+		return
+	}
+
+	evt, err := b.droppedEvent(cmd, reason)
+	if err != nil {
+		b.fail(fmt.Errorf("[goes/command/cmdbus.Bus@drop] Failed to create %q event for %q command: %w", CommandDropped, cmd.Name(), err))
+		return
+	}
+
+	if err := b.bus.Publish(ctx, evt); err != nil {
+		b.fail(fmt.Errorf("[goes/command/cmdbus.Bus@drop] Failed to publish %q event for %q command: %w", CommandDropped, cmd.Name(), err))
+	}
+}
+
+func (b *Bus) registerDispatch(cmd command.Command, cfg command.DispatchConfig, aborted chan struct{}) dispatcher {
+	d := dispatcher{
 		cmd:             cmd,
 		cfg:             cfg,
-		accepted:        accepted,
-		out:             out,
+		accepted:        make(chan struct{}),
+		out:             make(chan error),
 		dispatchAborted: aborted,
 	}
+
+	b.dispatchMux.Lock()
+	b.dispatched[cmd.ID()] = d
 	b.dispatchMux.Unlock()
 
-	defer b.cleanupDispatch(cmd.ID())
+	return d
+}
 
-	var timeout <-chan time.Time
-	if b.assignTimeout > 0 {
-		timer := time.NewTimer(b.assignTimeout)
-		defer timer.Stop()
-		timeout = timer.C
+// assignTimer returns a channel that is closed when the assign timeout of the
+// Bus is exceeded. The returned channel is nil if there is no assign timeout.
+func (b *Bus) assignTimer() (<-chan struct{}, func()) {
+	if b.assignTimeout <= 0 {
+		return nil, func() {}
 	}
 
+	timeout := make(chan struct{})
+	timer := time.AfterFunc(b.assignTimeout, func() { close(timeout) })
+
+	return timeout, func() { timer.Stop() }
+}
+
+func (b *Bus) await(ctx context.Context, d dispatcher, timeout <-chan struct{}) error {
+	stopped := b.Context().Done()
+
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
+	case <-stopped:
+		return fmt.Errorf("%w [command=%v, id=%v]", ErrStopped, d.cmd.Name(), d.cmd.ID())
 	case <-timeout:
-		return ErrAssignTimeout
-	case <-accepted:
+		// The timeout may be shared by multiple dispatches and may have fired
+		// after the command was accepted, in which case the select above picks
+		// a random ready case.
+		select {
+		case <-d.accepted:
+		default:
+			b.drop(ctx, d.cmd, ErrAssignTimeout)
+			return fmt.Errorf("%w [command=%v, id=%v]", ErrAssignTimeout, d.cmd.Name(), d.cmd.ID())
+		}
+	case <-d.accepted:
+	}
+
+	var execTimeout <-chan time.Time
+	if d.cfg.Synchronous && b.executionTimeout > 0 {
+		timer := time.NewTimer(b.executionTimeout)
+		defer timer.Stop()
+		execTimeout = timer.C
 	}
 
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
-	case err, failed := <-out:
+	case <-stopped:
+		return fmt.Errorf("%w [command=%v, id=%v]", ErrStopped, d.cmd.Name(), d.cmd.ID())
+	case <-execTimeout:
+		return fmt.Errorf("%w [command=%v, id=%v, timeout=%v]", ErrExecutionTimeout, d.cmd.Name(), d.cmd.ID(), b.executionTimeout)
+	case err, failed := <-d.out:
 		if failed {
 			return err
 		}
@@ -368,18 +677,35 @@ func (b *Bus) Subscribe(ctx context.Context, names ...string) (<-chan command.Ct
 		b.subMux.Lock()
 		defer b.subMux.Unlock()
 
+		// the subscriptions of all names share the same channels
 		for _, name := range names {
-			if sub, ok := b.subscriptions[name]; ok {
-				close(sub.commands)
-				close(sub.errs)
-			}
 			delete(b.subscriptions, name)
 		}
+		close(out)
+		close(errs)
 	}()
 
 	return out, errs, nil
 }
 
+// SubscribedNames returns the names of the commands that b currently has
+// active subscriptions for, sorted by name. A subscription is active until the
+// Context that was passed to Subscribe is canceled. SubscribedNames can be used
+// to check if the handlers of a service have been registered, for example in
+// a health check.
+func (b *Bus) SubscribedNames() []string {
+	b.subMux.RLock()
+	defer b.subMux.RUnlock()
+
+	names := make([]string, 0, len(b.subscriptions))
+	for name := range b.subscriptions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
 func (b *Bus) commandDispatched(evt event.Of[CommandDispatchedData]) {
 	data := evt.Data()
 
@@ -405,7 +731,17 @@ func (b *Bus) commandDispatched(evt event.Of[CommandDispatchedData]) {
 		return
 	}
 
-	b.requested[data.ID] = command.New(data.Name, load, command.ID(data.ID), command.Aggregate(data.AggregateName, data.AggregateID))
+	correlationID := data.CorrelationID
+	if correlationID == uuid.Nil {
+		correlationID = data.ID
+	}
+
+	b.requested[data.ID] = requestedCommand{
+		cmd:           command.New(data.Name, load, command.ID(data.ID), command.Aggregate(data.AggregateName, data.AggregateID)),
+		deadline:      data.Deadline,
+		correlationID: correlationID,
+		traceContext:  data.TraceContext,
+	}
 }
 
 func (b *Bus) handles(name string) bool {
@@ -451,10 +787,11 @@ func (b *Bus) commandAssigned(evt event.Of[CommandAssignedData]) {
 	data := evt.Data()
 
 	// if the bus did not request the command, return
-	cmd, ok := b.requested[data.ID]
+	req, ok := b.requested[data.ID]
 	if !ok {
 		return
 	}
+	cmd := req.cmd
 
 	// otherwise remove the command from the requested commands
 	delete(b.requested, data.ID)
@@ -483,6 +820,12 @@ func (b *Bus) commandAssigned(evt event.Of[CommandAssignedData]) {
 		return
 	}
 
+	// unless the command has expired
+	if req.expired() {
+		b.dropExpired(cmd, sub)
+		return
+	}
+
 	var timeout <-chan time.Time
 	if b.receiveTimeout > 0 {
 		timer := time.NewTimer(b.receiveTimeout)
@@ -490,23 +833,45 @@ func (b *Bus) commandAssigned(evt event.Of[CommandAssignedData]) {
 		timeout = timer.C
 	}
 
+	base, span := b.startSpan(command.WithCorrelationID(b.Context(), req.correlationID), cmd, req.traceContext)
+
 	select {
 	case <-b.Context().Done():
+		endSpan(span, finish.Config{Err: b.Context().Err()})
 	case <-timeout:
+		err := fmt.Errorf("dropping %q command: %w", cmd.Name(), ErrReceiveTimeout)
+		endSpan(span, finish.Config{Err: err})
 		select {
 		case <-b.Context().Done():
-		case sub.errs <- fmt.Errorf("dropping %q command: %w", cmd.Name(), ErrReceiveTimeout):
+		case sub.errs <- err:
 		}
 	case sub.commands <- command.NewContext[any](
-		b.Context(),
+		base,
 		cmd,
 		command.WhenDone(func(ctx context.Context, cfg finish.Config) error {
+			endSpan(span, cfg)
 			return b.markDone(ctx, cmd, cfg)
 		}),
 	):
 	}
 }
 
+// dropExpired drops an expired command. The command is marked as done with an
+// error that wraps ErrExpired, so that a synchronous dispatch of the command
+// fails, and ErrExpired is reported to the subscription of the command.
+func (b *Bus) dropExpired(cmd command.Command, sub *subscription) {
+	err := fmt.Errorf("dropping %q command: %w", cmd.Name(), ErrExpired)
+
+	if markErr := b.markDone(b.Context(), cmd, finish.Config{Err: err}); markErr != nil {
+		b.fail(fmt.Errorf("[goes/command/cmdbus.Bus@dropExpired] Failed to mark %q command as done: %w", cmd.Name(), markErr))
+	}
+
+	select {
+	case <-b.Context().Done():
+	case sub.errs <- err:
+	}
+}
+
 func (b *Bus) markDone(ctx context.Context, cmd command.Command, cfg finish.Config) error {
 	var errmsg string
 
@@ -516,6 +881,7 @@ func (b *Bus) markDone(ctx context.Context, cmd command.Command, cfg finish.Conf
 
 	evt := event.New(CommandExecuted, CommandExecutedData{
 		ID:      cmd.ID(),
+		Name:    cmd.Name(),
 		Runtime: cfg.Runtime,
 		Error:   errmsg,
 	})
@@ -558,6 +924,8 @@ func (b *Bus) commandAccepted(evt event.Of[CommandAcceptedData]) {
 func (b *Bus) commandExecuted(evt event.Of[CommandExecutedData]) {
 	data := evt.Data()
 
+	b.collectMetric(data)
+
 	// if the bus is not waiting for the execution of the command, return
 	b.dispatchMux.RLock()
 	cmd, ok := b.assigned[data.ID]
@@ -618,6 +986,34 @@ func (b *Bus) commandExecuted(evt event.Of[CommandExecutedData]) {
 	close(cmd.out)
 }
 
+func (b *Bus) collectMetric(data CommandExecutedData) {
+	if b.metricsChan == nil {
+		return
+	}
+
+	var err error
+	if data.Error != "" {
+		err = errors.New(data.Error)
+	}
+
+	// drop the metric if the callback can't keep up
+	select {
+	case b.metricsChan <- executionMetric{name: data.Name, runtime: data.Runtime, err: err}:
+	default:
+	}
+}
+
+func (b *Bus) reportMetrics(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case m := <-b.metricsChan:
+			b.metrics(m.name, m.runtime, m.err)
+		}
+	}
+}
+
 // logging errors to stderr if the command bus was started by Dispatch() or Subscribe().
 func logErrors(errs <-chan error) {
 	for err := range errs {
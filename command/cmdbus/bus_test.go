@@ -1,6 +1,7 @@
 package cmdbus_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
 	"github.com/modernice/goes/codec"
 	"github.com/modernice/goes/command"
 	"github.com/modernice/goes/command/cmdbus"
@@ -71,6 +73,157 @@ L:
 	assertEqualCommands(t, cmdCtx, cmd.Any())
 }
 
+func TestBus_DispatchMany(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bus, _, _ := newBus(ctx)
+
+	commands, errs, err := bus.Subscribe(ctx, "foo-cmd")
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	cmds := []command.Command{
+		command.New("foo-cmd", mockPayload{A: "foo"}).Any(),
+		command.New("foo-cmd", mockPayload{A: "bar"}).Any(),
+		command.New("foo-cmd", mockPayload{A: "baz"}).Any(),
+	}
+
+	dispatchErr := make(chan error, 1)
+	go func() { dispatchErr <- bus.(*cmdbus.Bus).DispatchMany(ctx, cmds) }()
+
+	received := make(map[string]command.Context)
+	for len(received) < len(cmds) {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("timed out. received %d/%d commands", len(received), len(cmds))
+		case err := <-errs:
+			t.Fatal(err)
+		case cmdCtx := <-commands:
+			received[cmdCtx.ID().String()] = cmdCtx
+		}
+	}
+
+	if err := <-dispatchErr; err != nil {
+		t.Fatalf("DispatchMany() failed with %q", err)
+	}
+
+	for _, cmd := range cmds {
+		cmdCtx, ok := received[cmd.ID().String()]
+		if !ok {
+			t.Fatalf("command %q was not received", cmd.ID())
+		}
+		assertEqualCommands(t, cmdCtx, cmd)
+	}
+}
+
+func TestBus_DispatchMany_error(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	reg := codec.JSON(codec.New())
+	codec.JSONRegister[mockPayload](reg, "foo-cmd")
+	codec.JSONRegister[mockPayload](reg, "bar-cmd")
+
+	bus, _, _ := newBusWith(ctx, reg.Registry, eventbus.New(), cmdbus.AssignTimeout(500*time.Millisecond))
+
+	commands, errs, err := bus.Subscribe(ctx, "foo-cmd")
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-errs:
+			case <-commands:
+			}
+		}
+	}()
+
+	foo := command.New("foo-cmd", mockPayload{A: "foo"}).Any()
+	bar := command.New("bar-cmd", mockPayload{A: "bar"}).Any()
+
+	err = bus.(*cmdbus.Bus).DispatchMany(ctx, []command.Command{foo, bar})
+
+	var dispatchErr *cmdbus.DispatchManyError
+	if !errors.As(err, &dispatchErr) {
+		t.Fatalf("DispatchMany() should fail with a %T; got %T (%v)", dispatchErr, err, err)
+	}
+
+	if len(dispatchErr.Errors) != 1 {
+		t.Fatalf("DispatchManyError should contain %d error; got %d", 1, len(dispatchErr.Errors))
+	}
+
+	if failed := dispatchErr.Errors[0]; failed.Cmd.ID() != bar.ID() {
+		t.Fatalf("%q command should have failed; got %q", bar.Name(), failed.Cmd.Name())
+	}
+
+	if !errors.Is(dispatchErr.Errors[0], cmdbus.ErrAssignTimeout) {
+		t.Fatalf("DispatchError should unwrap to %q; got %q", cmdbus.ErrAssignTimeout, dispatchErr.Errors[0].Err)
+	}
+
+	if !errors.Is(err, cmdbus.ErrAssignTimeout) {
+		t.Fatalf("DispatchManyError should match %q", cmdbus.ErrAssignTimeout)
+	}
+}
+
+func TestBus_DispatchMany_sync(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bus, _, _ := newBus(ctx)
+
+	commands, errs, err := bus.Subscribe(ctx, "foo-cmd")
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	mockError := errors.New("mock error")
+	foo := command.New("foo-cmd", mockPayload{A: "foo"}).Any()
+	bar := command.New("foo-cmd", mockPayload{A: "bar"}).Any()
+
+	dispatchErr := make(chan error, 1)
+	go func() {
+		dispatchErr <- bus.(*cmdbus.Bus).DispatchMany(ctx, []command.Command{foo, bar}, dispatch.Sync())
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("timed out. received %d/%d commands", i, 2)
+		case err := <-errs:
+			t.Fatal(err)
+		case cmdCtx := <-commands:
+			var opts []finish.Option
+			if cmdCtx.ID() == bar.ID() {
+				opts = append(opts, finish.WithError(mockError))
+			}
+			if err := cmdCtx.Finish(ctx, opts...); err != nil {
+				t.Fatalf("Finish() failed with %q", err)
+			}
+		}
+	}
+
+	err = <-dispatchErr
+
+	var manyErr *cmdbus.DispatchManyError
+	if !errors.As(err, &manyErr) {
+		t.Fatalf("DispatchMany() should fail with a %T; got %T (%v)", manyErr, err, err)
+	}
+
+	if len(manyErr.Errors) != 1 || manyErr.Errors[0].Cmd.ID() != bar.ID() {
+		t.Fatalf("only the %q command should have failed; got %v", bar.ID(), manyErr)
+	}
+
+	if _, ok := cmdbus.ExecError[any](err); !ok {
+		t.Fatalf("DispatchManyError should contain an %T; got %v", &cmdbus.ExecutionError[any]{}, err)
+	}
+}
+
 func TestBus_Dispatch_Report(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -202,6 +355,69 @@ L:
 	}
 }
 
+func TestWithMetrics(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	type metric struct {
+		name    string
+		runtime time.Duration
+		err     error
+	}
+
+	metrics := make(chan metric, 1)
+	bus, _, _ := newBus(ctx, cmdbus.WithMetrics(func(name string, runtime time.Duration, err error) {
+		metrics <- metric{name, runtime, err}
+	}))
+
+	commands, errs, err := bus.Subscribe(ctx, "foo-cmd")
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	cmd := command.New("foo-cmd", mockPayload{A: "foo"})
+
+	dispatchErr := make(chan error, 1)
+	go func() { dispatchErr <- bus.Dispatch(ctx, cmd.Any()) }()
+
+	var cmdCtx command.Context
+	select {
+	case <-ctx.Done():
+		t.Fatal("timed out")
+	case err := <-errs:
+		t.Fatal(err)
+	case cmdCtx = <-commands:
+	}
+
+	if err := <-dispatchErr; err != nil {
+		t.Fatalf("Dispatch() failed with %q", err)
+	}
+
+	mockError := errors.New("mock error")
+	if err := cmdCtx.Finish(ctx, finish.WithRuntime(3*time.Second), finish.WithError(mockError)); err != nil {
+		t.Fatalf("mark as done: %v", err)
+	}
+
+	var m metric
+	select {
+	case <-ctx.Done():
+		t.Fatal("timed out. metrics callback not called?")
+	case m = <-metrics:
+	}
+
+	if m.name != "foo-cmd" {
+		t.Errorf("metrics callback should be called with name %q; got %q", "foo-cmd", m.name)
+	}
+
+	if m.runtime != 3*time.Second {
+		t.Errorf("metrics callback should be called with runtime %v; got %v", 3*time.Second, m.runtime)
+	}
+
+	if m.err == nil || m.err.Error() != mockError.Error() {
+		t.Errorf("metrics callback should be called with error %q; got %v", mockError, m.err)
+	}
+}
+
 func TestAssignTimeout(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -243,6 +459,263 @@ func TestAssignTimeout_0(t *testing.T) {
 	}
 }
 
+func TestWithDeadLetter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus, ebus, reg := newBus(ctx, cmdbus.AssignTimeout(200*time.Millisecond), cmdbus.WithDeadLetter(true))
+
+	dropped, errs, err := ebus.Subscribe(ctx, cmdbus.CommandDropped)
+	if err != nil {
+		t.Fatalf("failed to subscribe to %q events: %v", cmdbus.CommandDropped, err)
+	}
+
+	cmd := command.New("foo-cmd", mockPayload{A: "foo"})
+
+	if err := bus.Dispatch(context.Background(), cmd.Any()); !errors.Is(err, cmdbus.ErrAssignTimeout) {
+		t.Fatalf("Dispatch should fail with %q; got %q", cmdbus.ErrAssignTimeout, err)
+	}
+
+	var evt event.Event
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("didn't receive %q event after %s", cmdbus.CommandDropped, time.Second)
+	case err := <-errs:
+		t.Fatal(err)
+	case evt = <-dropped:
+	}
+
+	data, ok := evt.Data().(cmdbus.CommandDroppedData)
+	if !ok {
+		t.Fatalf("event data should be %T; got %T", data, evt.Data())
+	}
+
+	if data.ID != cmd.ID() {
+		t.Errorf("ID should be %s; got %s", cmd.ID(), data.ID)
+	}
+
+	if data.Name != cmd.Name() {
+		t.Errorf("Name should be %q; got %q", cmd.Name(), data.Name)
+	}
+
+	if data.Reason != cmdbus.ErrAssignTimeout.Error() {
+		t.Errorf("Reason should be %q; got %q", cmdbus.ErrAssignTimeout.Error(), data.Reason)
+	}
+
+	load, err := reg.Decode(bytes.NewReader(data.Payload), cmd.Name())
+	if err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+
+	if load != cmd.Payload() {
+		t.Errorf("Payload should be %v; got %v", cmd.Payload(), load)
+	}
+}
+
+func TestWithDeadLetter_disabled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus, ebus, _ := newBus(ctx, cmdbus.AssignTimeout(100*time.Millisecond))
+
+	dropped, _, err := ebus.Subscribe(ctx, cmdbus.CommandDropped)
+	if err != nil {
+		t.Fatalf("failed to subscribe to %q events: %v", cmdbus.CommandDropped, err)
+	}
+
+	cmd := command.New("foo-cmd", mockPayload{})
+
+	if err := bus.Dispatch(context.Background(), cmd.Any()); !errors.Is(err, cmdbus.ErrAssignTimeout) {
+		t.Fatalf("Dispatch should fail with %q; got %q", cmdbus.ErrAssignTimeout, err)
+	}
+
+	select {
+	case evt := <-dropped:
+		t.Fatalf("no %q event should be published; got %v", cmdbus.CommandDropped, evt)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestExecutionTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus, _, _ := newBus(ctx, cmdbus.ExecutionTimeout(200*time.Millisecond))
+
+	commands, errs, err := bus.Subscribe(ctx, "foo-cmd")
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-errs:
+			case <-commands:
+				// never finish the command
+			}
+		}
+	}()
+
+	cmd := command.New("foo-cmd", mockPayload{})
+
+	dispatchErrc := make(chan error)
+	go func() { dispatchErrc <- bus.Dispatch(context.Background(), cmd.Any(), dispatch.Sync()) }()
+
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("didn't receive error after %s", time.Second)
+	case err = <-dispatchErrc:
+	}
+
+	if !errors.Is(err, cmdbus.ErrExecutionTimeout) {
+		t.Errorf("Dispatch should fail with %q; got %q", cmdbus.ErrExecutionTimeout, err)
+	}
+}
+
+func TestBus_Dispatch_stopped(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus, _, _ := newBus(ctx, cmdbus.AssignTimeout(0))
+
+	cmd := command.New("foo-cmd", mockPayload{})
+
+	dispatchErrc := make(chan error)
+	go func() { dispatchErrc <- bus.Dispatch(context.Background(), cmd.Any()) }()
+
+	<-time.After(50 * time.Millisecond)
+	cancel()
+
+	var err error
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("didn't receive error after %s", time.Second)
+	case err = <-dispatchErrc:
+	}
+
+	if !errors.Is(err, cmdbus.ErrStopped) {
+		t.Errorf("Dispatch should fail with %q; got %q", cmdbus.ErrStopped, err)
+	}
+}
+
+func TestBus_SubscribedNames(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := cmdbus.New(codec.New(), eventbus.New())
+
+	if names := bus.SubscribedNames(); len(names) != 0 {
+		t.Fatalf("SubscribedNames() should return no names; got %v", names)
+	}
+
+	subCtx, cancelSub := context.WithCancel(ctx)
+	if _, _, err := bus.Subscribe(subCtx, "foo", "bar"); err != nil {
+		t.Fatalf("Subscribe() failed with %q", err)
+	}
+
+	if _, _, err := bus.Subscribe(ctx, "baz"); err != nil {
+		t.Fatalf("Subscribe() failed with %q", err)
+	}
+
+	want := []string{"bar", "baz", "foo"}
+	if names := bus.SubscribedNames(); !cmp.Equal(want, names) {
+		t.Fatalf("SubscribedNames() returned wrong names\n%s", cmp.Diff(want, names))
+	}
+
+	cancelSub()
+
+	want = []string{"baz"}
+	deadline := time.After(time.Second)
+	for {
+		names := bus.SubscribedNames()
+		if cmp.Equal(want, names) {
+			break
+		}
+
+		select {
+		case <-deadline:
+			t.Fatalf("SubscribedNames() returned wrong names after unsubscribing\n%s", cmp.Diff(want, names))
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestExpireAfter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus, _, _ := newBus(ctx)
+
+	commands, errs, err := bus.Subscribe(ctx, "foo-cmd")
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	cmd := command.New("foo-cmd", mockPayload{})
+
+	dispatchErrc := make(chan error)
+	go func() {
+		dispatchErrc <- bus.Dispatch(context.Background(), cmd.Any(), dispatch.Sync(), dispatch.ExpireAfter(time.Nanosecond))
+	}()
+
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("didn't receive error after %s", time.Second)
+	case ctx := <-commands:
+		t.Fatalf("expired command should not be received; got %v", ctx)
+	case err := <-errs:
+		if !errors.Is(err, cmdbus.ErrExpired) {
+			t.Fatalf("subscription should receive %q error; got %q", cmdbus.ErrExpired, err)
+		}
+	}
+
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("Dispatch didn't return after %s", time.Second)
+	case err := <-dispatchErrc:
+		if err == nil || !strings.Contains(err.Error(), cmdbus.ErrExpired.Error()) {
+			t.Fatalf("Dispatch should fail with %q; got %v", cmdbus.ErrExpired, err)
+		}
+	}
+}
+
+func TestExpireAfter_notExpired(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus, _, _ := newBus(ctx)
+
+	commands, errs, err := bus.Subscribe(ctx, "foo-cmd")
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	cmd := command.New("foo-cmd", mockPayload{})
+
+	dispatchErrc := make(chan error, 1)
+	go func() {
+		if err := bus.Dispatch(context.Background(), cmd.Any(), dispatch.ExpireAfter(time.Minute)); err != nil {
+			dispatchErrc <- err
+		}
+	}()
+
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("didn't receive command after %s", time.Second)
+	case err := <-dispatchErrc:
+		t.Fatalf("Dispatch failed with %q", err)
+	case err := <-errs:
+		t.Fatal(err)
+	case ctx := <-commands:
+		if ctx.ID() != cmd.ID() {
+			t.Fatalf("received command should have id %s; got %s", cmd.ID(), ctx.ID())
+		}
+	}
+}
+
 func TestReceiveTimeout(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -470,3 +943,95 @@ func assertEqualCommands(t *testing.T, cmd1, cmd2 command.Command) {
 		t.Errorf("Command Payload mismatch: %#v != %#v", cmd1.Payload(), cmd2.Payload())
 	}
 }
+
+func TestWithCorrelation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus, _, _ := newBus(ctx)
+
+	commands, errs, err := bus.Subscribe(ctx, "foo-cmd")
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	correlationID := uuid.New()
+	cmd := command.New("foo-cmd", mockPayload{})
+
+	dispatchErrc := make(chan error, 1)
+	go func() {
+		if err := bus.Dispatch(context.Background(), cmd.Any(), dispatch.WithCorrelation(correlationID)); err != nil {
+			dispatchErrc <- err
+		}
+	}()
+
+	var cmdCtx command.Context
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("didn't receive command after %s", time.Second)
+	case err := <-dispatchErrc:
+		t.Fatalf("Dispatch failed with %q", err)
+	case err := <-errs:
+		t.Fatalf("subscription failed with %q", err)
+	case cmdCtx = <-commands:
+	}
+
+	if id := command.CorrelationID(cmdCtx); id != correlationID {
+		t.Fatalf("CorrelationID() should return %s; got %s", correlationID, id)
+	}
+
+	sub := command.New("foo-cmd", mockPayload{A: "sub"})
+	go func() {
+		if err := bus.Dispatch(cmdCtx, sub.Any()); err != nil {
+			dispatchErrc <- err
+		}
+	}()
+
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("didn't receive command after %s", time.Second)
+	case err := <-dispatchErrc:
+		t.Fatalf("Dispatch failed with %q", err)
+	case err := <-errs:
+		t.Fatalf("subscription failed with %q", err)
+	case cmdCtx = <-commands:
+	}
+
+	if id := command.CorrelationID(cmdCtx); id != correlationID {
+		t.Fatalf("commands dispatched by a handler should inherit the correlation id %s; got %s", correlationID, id)
+	}
+}
+
+func TestWithCorrelation_default(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus, _, _ := newBus(ctx)
+
+	commands, errs, err := bus.Subscribe(ctx, "foo-cmd")
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	cmd := command.New("foo-cmd", mockPayload{})
+
+	dispatchErrc := make(chan error, 1)
+	go func() {
+		if err := bus.Dispatch(context.Background(), cmd.Any()); err != nil {
+			dispatchErrc <- err
+		}
+	}()
+
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("didn't receive command after %s", time.Second)
+	case err := <-dispatchErrc:
+		t.Fatalf("Dispatch failed with %q", err)
+	case err := <-errs:
+		t.Fatalf("subscription failed with %q", err)
+	case cmdCtx := <-commands:
+		if id := command.CorrelationID(cmdCtx); id != cmd.ID() {
+			t.Fatalf("CorrelationID() should default to the command id %s; got %s", cmd.ID(), id)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Package cmdbustest provides a command bus for testing code that dispatches
+// commands, without wiring up a cmdbus.Bus and an event bus.
+package cmdbustest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/modernice/goes/command"
+)
+
+var _ command.Bus = (*RecordingBus)(nil)
+
+// RecordingBus is a command.Bus that records dispatched commands instead of
+// dispatching them to handlers. Use Dispatched to assert on the commands that
+// were dispatched by the code under test:
+//
+//	bus := cmdbustest.NewRecordingBus()
+//	svc := NewService(bus)
+//	svc.PlaceOrder(context.TODO(), orderID)
+//	cmds := bus.Dispatched()
+//	// assert cmds
+type RecordingBus struct {
+	err error
+
+	mux        sync.Mutex
+	dispatched []command.Command
+}
+
+// Option is an option for a RecordingBus.
+type Option func(*RecordingBus)
+
+// WithError returns an Option that makes the RecordingBus return err from
+// Dispatch. Dispatched commands are still recorded.
+func WithError(err error) Option {
+	return func(b *RecordingBus) {
+		b.err = err
+	}
+}
+
+// NewRecordingBus returns a new RecordingBus.
+func NewRecordingBus(opts ...Option) *RecordingBus {
+	var b RecordingBus
+	for _, opt := range opts {
+		opt(&b)
+	}
+	return &b
+}
+
+// Dispatch records the command and returns the error that was provided by
+// WithError, if any. Dispatch options are ignored.
+func (b *RecordingBus) Dispatch(_ context.Context, cmd command.Command, _ ...command.DispatchOption) error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.dispatched = append(b.dispatched, cmd)
+	return b.err
+}
+
+// Subscribe returns channels that never receive a command or error. Both
+// channels are closed when ctx is canceled.
+func (b *RecordingBus) Subscribe(ctx context.Context, _ ...string) (<-chan command.Context, <-chan error, error) {
+	out := make(chan command.Context)
+	errs := make(chan error)
+	go func() {
+		<-ctx.Done()
+		close(out)
+		close(errs)
+	}()
+	return out, errs, nil
+}
+
+// Dispatched returns the recorded commands in the order they were dispatched.
+func (b *RecordingBus) Dispatched() []command.Command {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	out := make([]command.Command, len(b.dispatched))
+	copy(out, b.dispatched)
+	return out
+}
+
+// Reset removes the recorded commands.
+func (b *RecordingBus) Reset() {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.dispatched = nil
+}
@@ -0,0 +1,80 @@
+package cmdbustest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/command"
+	"github.com/modernice/goes/command/cmdbus/cmdbustest"
+)
+
+type placeOrderPayload struct {
+	Items []string
+}
+
+// placeOrder is the unit under test. It dispatches a command to place an order
+// and a command to reserve each item of the order.
+func placeOrder(ctx context.Context, bus command.Dispatcher, id uuid.UUID, items ...string) error {
+	if err := bus.Dispatch(ctx, command.New("order.place", placeOrderPayload{Items: items}, command.Aggregate("order", id)).Any()); err != nil {
+		return fmt.Errorf("dispatch %q command: %w", "order.place", err)
+	}
+
+	for _, item := range items {
+		if err := bus.Dispatch(ctx, command.New("stock.reserve", item).Any()); err != nil {
+			return fmt.Errorf("dispatch %q command: %w", "stock.reserve", err)
+		}
+	}
+
+	return nil
+}
+
+func TestRecordingBus(t *testing.T) {
+	bus := cmdbustest.NewRecordingBus()
+	id := uuid.New()
+
+	if err := placeOrder(context.Background(), bus, id, "foo", "bar"); err != nil {
+		t.Fatalf("placeOrder() failed with %q", err)
+	}
+
+	cmds := bus.Dispatched()
+	if len(cmds) != 3 {
+		t.Fatalf("%d commands should have been dispatched; got %d", 3, len(cmds))
+	}
+
+	if cmds[0].Name() != "order.place" {
+		t.Errorf("first command should be a %q command; got %q", "order.place", cmds[0].Name())
+	}
+
+	if aid, _ := cmds[0].Aggregate().Split(); aid != id {
+		t.Errorf("%q command should target aggregate %s; got %s", "order.place", id, aid)
+	}
+
+	for i, item := range []string{"foo", "bar"} {
+		cmd := cmds[i+1]
+		if cmd.Name() != "stock.reserve" || cmd.Payload() != item {
+			t.Errorf("command %d should be a %q command for %q; got %q command for %v", i+1, "stock.reserve", item, cmd.Name(), cmd.Payload())
+		}
+	}
+
+	bus.Reset()
+
+	if cmds := bus.Dispatched(); len(cmds) != 0 {
+		t.Fatalf("Dispatched() should return no commands after Reset(); got %d", len(cmds))
+	}
+}
+
+func TestWithError(t *testing.T) {
+	mockError := errors.New("mock error")
+	bus := cmdbustest.NewRecordingBus(cmdbustest.WithError(mockError))
+
+	if err := placeOrder(context.Background(), bus, uuid.New(), "foo"); !errors.Is(err, mockError) {
+		t.Fatalf("placeOrder() should fail with %q; got %v", mockError, err)
+	}
+
+	if cmds := bus.Dispatched(); len(cmds) != 1 {
+		t.Fatalf("failed dispatches should be recorded; got %d commands", len(cmds))
+	}
+}
@@ -1,6 +1,11 @@
 package dispatch
 
-import "github.com/modernice/goes/command"
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/command"
+)
 
 // Configure returns a Config from Options.
 func Configure(opts ...command.DispatchOption) command.DispatchConfig {
@@ -41,3 +46,24 @@ func Report(r command.Reporter) command.DispatchOption {
 		cfg.Reporter = r
 	}
 }
+
+// ExpireAfter returns an Option that makes a Command expire if it is not passed
+// to its handler within the Duration d after the dispatch. An expired Command
+// is dropped by the handling Bus instead of being executed. A zero or negative
+// Duration means the Command never expires, which is the default.
+func ExpireAfter(d time.Duration) command.DispatchOption {
+	return func(cfg *command.DispatchConfig) {
+		cfg.ExpireAfter = d
+	}
+}
+
+// WithCorrelation returns an Option that sets the correlation id of a
+// dispatched Command. The correlation id is passed to the handler of the
+// Command, which can read it using command.CorrelationID. Without this Option,
+// the correlation id of the dispatch Context is used, or the id of the Command
+// itself if the Context carries no correlation id.
+func WithCorrelation(id uuid.UUID) command.DispatchOption {
+	return func(cfg *command.DispatchConfig) {
+		cfg.CorrelationID = id
+	}
+}
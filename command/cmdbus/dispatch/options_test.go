@@ -2,7 +2,9 @@ package dispatch_test
 
 import (
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/modernice/goes/command/cmdbus/dispatch"
 	"github.com/modernice/goes/command/cmdbus/report"
 )
@@ -22,3 +24,20 @@ func TestReport(t *testing.T) {
 		t.Fatalf("cfg.Report should point to %p; got %v", &rep, cfg.Reporter)
 	}
 }
+
+func TestExpireAfter(t *testing.T) {
+	cfg := dispatch.Configure(dispatch.ExpireAfter(time.Minute))
+
+	if cfg.ExpireAfter != time.Minute {
+		t.Fatalf("cfg.ExpireAfter should be %v; got %v", time.Minute, cfg.ExpireAfter)
+	}
+}
+
+func TestWithCorrelation(t *testing.T) {
+	id := uuid.New()
+	cfg := dispatch.Configure(dispatch.WithCorrelation(id))
+
+	if cfg.CorrelationID != id {
+		t.Fatalf("cfg.CorrelationID should be %s; got %s", id, cfg.CorrelationID)
+	}
+}
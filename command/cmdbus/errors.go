@@ -3,6 +3,7 @@ package cmdbus
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/modernice/goes/command"
 )
@@ -30,3 +31,53 @@ func (err *ExecutionError[P]) Error() string {
 func (err *ExecutionError[P]) Unwrap() error {
 	return err.Err
 }
+
+// DispatchError is the error of a single Command that could not be dispatched
+// by Bus.DispatchMany.
+type DispatchError struct {
+	Cmd command.Command
+	Err error
+}
+
+func (err *DispatchError) Error() string {
+	return fmt.Sprintf("dispatch %q command (%s): %v", err.Cmd.Name(), err.Cmd.ID(), err.Err)
+}
+
+func (err *DispatchError) Unwrap() error {
+	return err.Err
+}
+
+// DispatchManyError is returned by Bus.DispatchMany when one or more Commands
+// could not be dispatched. Errors contains the errors of the failed Commands in
+// the order they were passed to DispatchMany.
+type DispatchManyError struct {
+	Errors []*DispatchError
+}
+
+func (err *DispatchManyError) Error() string {
+	msgs := make([]string, len(err.Errors))
+	for i, e := range err.Errors {
+		msgs[i] = e.Error()
+	}
+	return fmt.Sprintf("%d commands failed: %s", len(err.Errors), strings.Join(msgs, "; "))
+}
+
+// Is returns whether the error of any of the failed Commands matches target.
+func (err *DispatchManyError) Is(target error) bool {
+	for _, e := range err.Errors {
+		if errors.Is(e, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error of the failed Commands that matches target.
+func (err *DispatchManyError) As(target any) bool {
+	for _, e := range err.Errors {
+		if errors.As(e, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -26,6 +26,10 @@ const (
 	// CommandExecuted is published by a Bus to notify other Buses that a
 	// Command has been executed.
 	CommandExecuted = "goes.command.executed"
+
+	// CommandDropped is published by a Bus that has the dead-letter option
+	// enabled when a dispatched Command could not be assigned to a Handler.
+	CommandDropped = "goes.command.dropped"
 )
 
 // CommandDispatchedData is the event Data for the CommandDispatched Event.
@@ -45,6 +49,21 @@ type CommandDispatchedData struct {
 
 	// Payload is the encoded domain-specific Command Payload.
 	Payload []byte
+
+	// Deadline is the time at which the Command expires. A zero Deadline
+	// means the Command never expires. Events that were published before the
+	// field was introduced decode to a zero Deadline. (optional)
+	Deadline time.Time
+
+	// CorrelationID is the correlation id of the Command. Events that were
+	// published before the field was introduced decode to uuid.Nil, in which
+	// case the id of the Command is used as its correlation id. (optional)
+	CorrelationID uuid.UUID
+
+	// TraceContext carries the serialized trace context of the dispatching
+	// Bus. It is only set if the dispatching Bus has tracing enabled.
+	// (optional)
+	TraceContext map[string]string
 }
 
 // CommandRequestedData is the event Data for the CommandRequested Event.
@@ -68,10 +87,34 @@ type CommandAcceptedData struct {
 // CommandExecutedData is the event Data for the CommandExecuted Event.
 type CommandExecutedData struct {
 	ID      uuid.UUID
+	Name    string
 	Runtime time.Duration
 	Error   string
 }
 
+// CommandDroppedData is the event Data for the CommandDropped Event. It
+// contains everything that is needed to dispatch the Command again.
+type CommandDroppedData struct {
+	// ID is the unique Command ID.
+	ID uuid.UUID
+
+	// Name is the name of the Command.
+	Name string
+
+	// AggregateName is the name of the aggregate the Command belongs to.
+	// (optional)
+	AggregateName string
+
+	// AggregateID is the ID of the aggregate the Command belongs to. (optional)
+	AggregateID uuid.UUID
+
+	// Payload is the encoded domain-specific Command Payload.
+	Payload []byte
+
+	// Reason describes why the Command was dropped.
+	Reason string
+}
+
 // RegisterEvents registers the command events into a Registry.
 func RegisterEvents(reg *codec.Registry) {
 	gob := codec.Gob(reg)
@@ -90,4 +133,7 @@ func RegisterEvents(reg *codec.Registry) {
 	gob.GobRegister(CommandExecuted, func() any {
 		return CommandExecutedData{}
 	})
+	gob.GobRegister(CommandDropped, func() any {
+		return CommandDroppedData{}
+	})
 }
@@ -0,0 +1,73 @@
+package cmdbus
+
+import (
+	"context"
+
+	"github.com/modernice/goes/command"
+	"github.com/modernice/goes/command/finish"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracePropagator serializes span contexts into CommandDispatched events using
+// the W3C Trace Context format.
+var tracePropagator = propagation.TraceContext{}
+
+// injectTrace returns the serialized span context of ctx, or nil if tracing is
+// disabled or ctx carries no span.
+func (b *Bus) injectTrace(ctx context.Context) map[string]string {
+	if b.tracer == nil {
+		return nil
+	}
+	carrier := propagation.MapCarrier{}
+	tracePropagator.Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// startSpan starts the span for the handling of cmd as a child of the span
+// that is serialized in traceContext. If tracing is disabled, startSpan
+// returns ctx and a nil span.
+func (b *Bus) startSpan(ctx context.Context, cmd command.Command, traceContext map[string]string) (context.Context, trace.Span) {
+	if b.tracer == nil {
+		return ctx, nil
+	}
+
+	ctx = tracePropagator.Extract(ctx, propagation.MapCarrier(traceContext))
+
+	id, name := cmd.Aggregate().Split()
+	return b.tracer.Start(
+		ctx,
+		cmd.Name(),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("goes.command.id", cmd.ID().String()),
+			attribute.String("goes.command.name", cmd.Name()),
+			attribute.String("goes.command.aggregate_name", name),
+			attribute.String("goes.command.aggregate_id", id.String()),
+		),
+	)
+}
+
+// endSpan records the runtime and execution error of a command and ends the
+// span. endSpan does nothing if span is nil.
+func endSpan(span trace.Span, cfg finish.Config) {
+	if span == nil {
+		return
+	}
+
+	if cfg.Runtime > 0 {
+		span.SetAttributes(attribute.Int64("goes.command.runtime_ms", cfg.Runtime.Milliseconds()))
+	}
+
+	if cfg.Err != nil {
+		span.RecordError(cfg.Err)
+		span.SetStatus(codes.Error, cfg.Err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,149 @@
+package cmdbus_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/modernice/goes/codec"
+	"github.com/modernice/goes/command"
+	"github.com/modernice/goes/command/cmdbus"
+	"github.com/modernice/goes/command/finish"
+	"github.com/modernice/goes/event/eventbus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestWithTracing(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("cmdbus_test")
+
+	enc := codec.Gob(codec.New())
+	enc.GobRegister("foo-cmd", func() any { return mockPayload{} })
+	ebus := eventbus.New()
+
+	dispatchBus, _, _ := newBusWith(ctx, enc.Registry, ebus, cmdbus.WithTracing(tracer))
+	handleBus, _, _ := newBusWith(ctx, enc.Registry, ebus, cmdbus.WithTracing(tracer))
+
+	commands, errs, err := handleBus.Subscribe(ctx, "foo-cmd")
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	dispatchCtx, parent := tracer.Start(ctx, "parent")
+	defer parent.End()
+
+	cmd := command.New("foo-cmd", mockPayload{A: "foo"})
+
+	dispatchErr := make(chan error, 1)
+	go func() { dispatchErr <- dispatchBus.Dispatch(dispatchCtx, cmd.Any()) }()
+
+	var cmdCtx command.Context
+	select {
+	case <-ctx.Done():
+		t.Fatal("timed out")
+	case err := <-errs:
+		t.Fatal(err)
+	case cmdCtx = <-commands:
+	}
+
+	if err := <-dispatchErr; err != nil {
+		t.Fatalf("Dispatch() failed with %q", err)
+	}
+
+	handlerSpan := trace.SpanFromContext(cmdCtx)
+	if !handlerSpan.SpanContext().IsValid() {
+		t.Fatalf("command context should carry a span")
+	}
+
+	mockError := errors.New("mock error")
+	if err := cmdCtx.Finish(ctx, finish.WithRuntime(3*time.Second), finish.WithError(mockError)); err != nil {
+		t.Fatalf("mark as done: %v", err)
+	}
+
+	ended := recorder.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("%d span should have been ended; got %d", 1, len(ended))
+	}
+	span := ended[0]
+
+	if span.SpanContext().SpanID() != handlerSpan.SpanContext().SpanID() {
+		t.Errorf("ended span should be the span of the command context")
+	}
+
+	if span.Name() != "foo-cmd" {
+		t.Errorf("span should be named %q; got %q", "foo-cmd", span.Name())
+	}
+
+	if span.SpanContext().TraceID() != parent.SpanContext().TraceID() {
+		t.Errorf("span should belong to trace %s; got %s", parent.SpanContext().TraceID(), span.SpanContext().TraceID())
+	}
+
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("span should be a child of span %s; got parent %s", parent.SpanContext().SpanID(), span.Parent().SpanID())
+	}
+
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, attr := range span.Attributes() {
+		attrs[attr.Key] = attr.Value
+	}
+
+	if v := attrs["goes.command.name"].AsString(); v != "foo-cmd" {
+		t.Errorf("span should have a %q attribute of %q; got %q", "goes.command.name", "foo-cmd", v)
+	}
+
+	if v := attrs["goes.command.runtime_ms"].AsInt64(); v != 3000 {
+		t.Errorf("span should have a %q attribute of %d; got %d", "goes.command.runtime_ms", 3000, v)
+	}
+
+	if status := span.Status(); status.Code != codes.Error || status.Description != mockError.Error() {
+		t.Errorf("span should have an error status with description %q; got %v", mockError, status)
+	}
+}
+
+func TestWithTracing_disabled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("cmdbus_test")
+
+	bus, _, _ := newBus(ctx)
+
+	commands, errs, err := bus.Subscribe(ctx, "foo-cmd")
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	dispatchCtx, parent := tracer.Start(ctx, "parent")
+	defer parent.End()
+
+	dispatchErr := make(chan error, 1)
+	go func() { dispatchErr <- bus.Dispatch(dispatchCtx, command.New("foo-cmd", mockPayload{}).Any()) }()
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("timed out")
+	case err := <-errs:
+		t.Fatal(err)
+	case cmdCtx := <-commands:
+		if trace.SpanFromContext(cmdCtx).SpanContext().IsValid() {
+			t.Fatalf("command context should not carry a span if tracing is disabled")
+		}
+	}
+
+	if err := <-dispatchErr; err != nil {
+		t.Fatalf("Dispatch() failed with %q", err)
+	}
+
+	if spans := recorder.Ended(); len(spans) != 0 {
+		t.Fatalf("no spans should have been ended; got %d", len(spans))
+	}
+}
@@ -15,6 +15,17 @@ import "github.com/modernice/goes/codec"
 //	codec.GobRegister[BarPayload](reg, "bar")
 //	codec.GobRegister[int](reg, "baz")
 //	codec.GobRegister[string](reg, "foobar")
+//
+// Example using encoding/json, e.g. to dispatch commands to services that are
+// not written in Go:
+//
+//	reg := codec.JSON(command.NewRegistry())
+//	codec.JSONRegister[FooPayload](reg, "foo")
+//	codec.JSONRegister[BarPayload](reg, "bar")
+//
+//	var buf bytes.Buffer
+//	err := reg.Encode(&buf, "foo", FooPayload{...})
+//	payload, err := reg.Decode(&buf, "foo") // payload is a FooPayload
 func NewRegistry() *codec.Registry {
 	return codec.New()
 }
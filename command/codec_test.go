@@ -0,0 +1,37 @@
+package command_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/modernice/goes/codec"
+	"github.com/modernice/goes/command"
+)
+
+func TestNewRegistry_json(t *testing.T) {
+	reg := codec.JSON(command.NewRegistry())
+	codec.JSONRegister[mockPayload](reg, "foo")
+
+	pl := mockPayload{A: true, B: "bar"}
+
+	var buf bytes.Buffer
+	if err := reg.Encode(&buf, "foo", pl); err != nil {
+		t.Fatalf("Encode() failed with %q", err)
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &raw); err != nil {
+		t.Fatalf("encoded payload should be valid JSON; unmarshal failed with %q", err)
+	}
+
+	decoded, err := reg.Decode(&buf, "foo")
+	if err != nil {
+		t.Fatalf("Decode() failed with %q", err)
+	}
+
+	if !cmp.Equal(pl, decoded) {
+		t.Fatalf("decoded payload differs from original payload.\n\n%s", cmp.Diff(pl, decoded))
+	}
+}
@@ -8,6 +8,32 @@ import (
 	"github.com/modernice/goes/command/finish"
 )
 
+type correlationKey struct{}
+
+// WithCorrelationID returns a copy of ctx that carries the given correlation
+// id. Commands that are dispatched with the returned Context share the
+// correlation id.
+func WithCorrelationID(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationID returns the correlation id that is carried by ctx, or uuid.Nil
+// if ctx carries no correlation id. The Context that is passed to a command
+// handler carries the correlation id of the dispatched command, so that
+// commands that are dispatched by the handler using this Context inherit the
+// correlation id:
+//
+//	func handle(ctx command.Context) error {
+//		id := command.CorrelationID(ctx)
+//		return bus.Dispatch(ctx, otherCmd) // otherCmd has the same correlation id
+//	}
+func CorrelationID(ctx context.Context) uuid.UUID {
+	if id, ok := ctx.Value(correlationKey{}).(uuid.UUID); ok {
+		return id
+	}
+	return uuid.Nil
+}
+
 // ContextOption is a Context option.
 type ContextOption func(*options)
 
@@ -52,6 +78,12 @@ func (ctx *cmdctx[P]) AggregateName() string {
 	return ctx.Aggregate().Name
 }
 
+// CorrelationID returns the correlation id of the command. Events that are
+// created with event.CausedBy(ctx) inherit the correlation id.
+func (ctx *cmdctx[P]) CorrelationID() uuid.UUID {
+	return CorrelationID(ctx)
+}
+
 func (ctx *cmdctx[P]) Finish(c context.Context, opts ...finish.Option) error {
 	ctx.mux.Lock()
 	defer ctx.mux.Unlock()
@@ -2,33 +2,116 @@ package command
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/modernice/goes/command/finish"
 	"github.com/modernice/goes/internal/xtime"
 )
 
+// ErrShutdown is returned by Handler.Handle if the Handler has been shut down.
+var ErrShutdown = errors.New("handler was shut down")
+
 // Handler wraps a Bus to provide a convenient way to subscribe to and handle commands.
 type Handler[P any] struct {
 	bus Bus
+
+	mux        sync.RWMutex
+	middleware []Middleware
+
+	stopMux sync.Mutex
+	stopped bool
+	stop    chan struct{}
+	running sync.WaitGroup
+}
+
+// Middleware wraps the execution of command handlers to add cross-cutting
+// behavior like logging, tracing or validation. A Middleware receives the next
+// function in the chain and returns a function that is called instead. The
+// returned function may short-circuit the chain by returning an error without
+// calling next.
+//
+//	logging := func(next func(context.Context, command.Command) error) func(context.Context, command.Command) error {
+//		return func(ctx context.Context, cmd command.Command) error {
+//			log.Printf("Handling %q command ...", cmd.Name())
+//			return next(ctx, cmd)
+//		}
+//	}
+type Middleware func(next func(context.Context, Command) error) func(context.Context, Command) error
+
+// HandleOption is an option for Handler.Handle.
+type HandleOption func(*handleConfig)
+
+type handleConfig struct {
+	concurrent    bool
+	maxConcurrent int
+
+	maxAttempts int
+	backoff     func(attempt int) time.Duration
+	retryable   func(error) bool
+}
+
+// MaxConcurrent returns a HandleOption that allows commands to be handled
+// concurrently. At most n commands are handled at the same time; further
+// commands are queued until a running handler returns. If n is 0, the number of
+// concurrently handled commands is unbounded.
+//
+// By default, commands are handled one after another.
+func MaxConcurrent(n int) HandleOption {
+	return func(cfg *handleConfig) {
+		cfg.concurrent = true
+		if n < 0 {
+			n = 0
+		}
+		cfg.maxConcurrent = n
+	}
+}
+
+// Retry returns a HandleOption that re-invokes the handler of a command when it
+// fails with a retryable error. The handler is called at most maxAttempts
+// times. Before the next attempt, the Handler waits for the Duration returned
+// by backoff, which is called with the number of the failed attempt, starting
+// at 1. A nil backoff retries immediately. retryable reports whether an error
+// is transient; if retryable is nil, every error is retried.
+//
+// The context of the command is respected across attempts: if it is canceled
+// while waiting for the next attempt, no further attempts are made. When the
+// handler fails after more than one attempt, the returned error wraps the
+// error of the last attempt together with the number of attempts.
+func Retry(maxAttempts int, backoff func(attempt int) time.Duration, retryable func(error) bool) HandleOption {
+	return func(cfg *handleConfig) {
+		cfg.maxAttempts = maxAttempts
+		cfg.backoff = backoff
+		cfg.retryable = retryable
+	}
 }
 
 // NewHandler wraps the provided Bus in a *Handler.
 func NewHandler[P any](bus Bus) *Handler[P] {
-	return &Handler[P]{bus}
+	return &Handler[P]{bus: bus, stop: make(chan struct{})}
+}
+
+// Use registers Middleware that wraps the handler functions of subsequent
+// calls to Handle. Middleware is called in the order it was registered, so the
+// first registered Middleware is the outermost.
+func (h *Handler[P]) Use(mw ...Middleware) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	h.middleware = append(h.middleware, mw...)
 }
 
 // Handle is a shortcut for
-//	NewHandler(bus).Handle(ctx, name, handler)
-func Handle[P any](ctx context.Context, bus Bus, name string, handler func(Ctx[P]) error) (<-chan error, error) {
-	return NewHandler[P](bus).Handle(ctx, name, handler)
+//	NewHandler(bus).Handle(ctx, name, handler, opts...)
+func Handle[P any](ctx context.Context, bus Bus, name string, handler func(Ctx[P]) error, opts ...HandleOption) (<-chan error, error) {
+	return NewHandler[P](bus).Handle(ctx, name, handler, opts...)
 }
 
 // MustHandle is a shortcut for
-//	NewHandler(bus).MustHandle(ctx, name, handler)
-func MustHandle[P any](ctx context.Context, bus Bus, name string, handler func(Ctx[P]) error) <-chan error {
-	return NewHandler[P](bus).MustHandle(ctx, name, handler)
+//	NewHandler(bus).MustHandle(ctx, name, handler, opts...)
+func MustHandle[P any](ctx context.Context, bus Bus, name string, handler func(Ctx[P]) error, opts ...HandleOption) <-chan error {
+	return NewHandler[P](bus).MustHandle(ctx, name, handler, opts...)
 }
 
 // Handle registers the provided function as a handler for the given command.
@@ -45,22 +128,77 @@ func MustHandle[P any](ctx context.Context, bus Bus, name string, handler func(C
 // 	- all errors returned by the provided handler function
 //	- errors returned by the `Finish` method of command.Context
 //
-// When ctx is canceled, the returned error channel is closed.
-func (h *Handler[P]) Handle(ctx context.Context, name string, handler func(Ctx[P]) error) (<-chan error, error) {
+// When ctx is canceled or the Handler is shut down, the returned error channel
+// is closed.
+//
+// Commands are handled one after another. Use the MaxConcurrent option to
+// handle multiple commands concurrently.
+func (h *Handler[P]) Handle(ctx context.Context, name string, handler func(Ctx[P]) error, opts ...HandleOption) (<-chan error, error) {
+	var cfg handleConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	h.stopMux.Lock()
+	defer h.stopMux.Unlock()
+
+	if h.stopped {
+		return nil, ErrShutdown
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
 	str, errs, err := h.bus.Subscribe(ctx, name)
 	if err != nil {
+		cancel()
 		return nil, fmt.Errorf("subscribe to %v Command: %w", name, err)
 	}
 
+	h.running.Add(1)
+
 	out := make(chan error)
-	go h.handle(ctx, handler, str, errs, out)
+	go func() {
+		defer h.running.Done()
+		h.handle(ctx, cancel, cfg, withRetry(cfg, h.withMiddleware(handler)), str, errs, out)
+	}()
 
 	return out, nil
 }
 
+// Shutdown gracefully shuts down the Handler. The Handler stops receiving
+// commands from the Bus and waits for the commands that are currently being
+// handled to finish. The subscriptions to the Bus are canceled before Shutdown
+// waits for the handler functions, so that the Bus does not block on passing
+// commands to a Handler that no longer receives them. Commands that the Bus
+// passes to the Handler while the subscriptions are being canceled are
+// finished with ErrShutdown. If ctx is canceled before all handler functions
+// have returned, Shutdown returns ctx.Err(). After Shutdown has been called,
+// Handle fails with ErrShutdown.
+func (h *Handler[P]) Shutdown(ctx context.Context) error {
+	h.stopMux.Lock()
+	if !h.stopped {
+		h.stopped = true
+		close(h.stop)
+	}
+	h.stopMux.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.running.Wait()
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-done:
+		return nil
+	}
+}
+
 // MustHandle does the same as Handle, but panics if the command subscription fails.
-func (h *Handler[P]) MustHandle(ctx context.Context, name string, handler func(Ctx[P]) error) <-chan error {
-	errs, err := h.Handle(ctx, name, handler)
+func (h *Handler[P]) MustHandle(ctx context.Context, name string, handler func(Ctx[P]) error, opts ...HandleOption) <-chan error {
+	errs, err := h.Handle(ctx, name, handler, opts...)
 	if err != nil {
 		panic(err)
 	}
@@ -69,20 +207,41 @@ func (h *Handler[P]) MustHandle(ctx context.Context, name string, handler func(C
 
 func (h *Handler[P]) handle(
 	ctx context.Context,
+	cancel context.CancelFunc,
+	cfg handleConfig,
 	handler func(Ctx[P]) error,
 	str <-chan Context,
 	errs <-chan error,
 	out chan<- error,
 ) {
+	var wg sync.WaitGroup
 	defer close(out)
+	defer wg.Wait()
+	defer h.reject(ctx, cancel, str, errs)
+
+	var sem chan struct{}
+	if cfg.concurrent && cfg.maxConcurrent > 0 {
+		sem = make(chan struct{}, cfg.maxConcurrent)
+	}
+
 	for {
 		if str == nil && errs == nil {
 			return
 		}
 
+		// Stop before receiving the next command if the Handler was shut
+		// down while a command was being handled.
+		select {
+		case <-h.stop:
+			return
+		default:
+		}
+
 		select {
 		case <-ctx.Done():
 			return
+		case <-h.stop:
+			return
 		case err, ok := <-errs:
 			if !ok {
 				errs = nil
@@ -93,41 +252,179 @@ func (h *Handler[P]) handle(
 				return
 			case out <- fmt.Errorf("command subscription: %w", err):
 			}
-		case ctx, ok := <-str:
+		case cmd, ok := <-str:
 			if !ok {
 				str = nil
 				break
 			}
 
-			casted, ok := TryCastContext[P](ctx)
-			if !ok {
+			if !cfg.concurrent {
+				h.handleCommand(handler, cmd, out)
+				break
+			}
+
+			if sem != nil {
 				select {
 				case <-ctx.Done():
 					return
-				case out <- fmt.Errorf("failed to cast context [from=%T, to=%T]", ctx, casted):
+				case sem <- struct{}{}:
 				}
 			}
 
-			start := xtime.Now()
-			err := handler(casted)
-			runtime := time.Since(start)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if sem != nil {
+					defer func() { <-sem }()
+				}
+				h.handleCommand(handler, cmd, out)
+			}()
+		}
+	}
+}
 
-			cmd := ctx
+// reject cancels the subscription of a handle loop and drains the command and
+// error channels of the subscription until the Bus closes them. Commands that
+// are received while the subscription is being canceled are finished with
+// ErrShutdown, or with ctx.Err() if the Handler was not shut down.
+func (h *Handler[P]) reject(ctx context.Context, cancel context.CancelFunc, str <-chan Context, errs <-chan error) {
+	reason := ctx.Err()
+	select {
+	case <-h.stop:
+		reason = ErrShutdown
+	default:
+	}
 
-			if err != nil {
-				select {
-				case <-ctx.Done():
-					return
-				case out <- fmt.Errorf("handle %q command: %w", cmd.Name(), err):
-				}
+	cancel()
+
+	for str != nil || errs != nil {
+		select {
+		case _, ok := <-errs:
+			if !ok {
+				errs = nil
 			}
+		case cmd, ok := <-str:
+			if !ok {
+				str = nil
+				break
+			}
+			cmd.Finish(cmd, finish.WithError(reason))
+		}
+	}
+}
 
-			if err := ctx.Finish(ctx, finish.WithError(err), finish.WithRuntime(runtime)); err != nil {
-				select {
-				case <-ctx.Done():
-					return
-				case out <- fmt.Errorf("finish %q command: %w", cmd.Name(), err):
+func (h *Handler[P]) handleCommand(handler func(Ctx[P]) error, ctx Context, out chan<- error) {
+	casted, ok := TryCastContext[P](ctx)
+	if !ok {
+		err := fmt.Errorf("failed to cast context [from=%T, to=%T]", ctx, casted)
+		select {
+		case <-ctx.Done():
+			return
+		case out <- err:
+		}
+
+		if err := ctx.Finish(ctx, finish.WithError(err)); err != nil {
+			select {
+			case <-ctx.Done():
+			case out <- fmt.Errorf("finish %q command: %w", ctx.Name(), err):
+			}
+		}
+		return
+	}
+
+	start := xtime.Now()
+	err := handler(casted)
+	runtime := time.Since(start)
+
+	cmd := ctx
+
+	if err != nil {
+		select {
+		case <-ctx.Done():
+			return
+		case out <- fmt.Errorf("handle %q command: %w", cmd.Name(), err):
+		}
+	}
+
+	if err := ctx.Finish(ctx, finish.WithError(err), finish.WithRuntime(runtime)); err != nil {
+		select {
+		case <-ctx.Done():
+			return
+		case out <- fmt.Errorf("finish %q command: %w", cmd.Name(), err):
+		}
+	}
+}
+
+// withMiddleware wraps handler with the registered Middleware. If a Middleware
+// passes a context or command to next that is not the original command
+// context, a new command context is created for handler.
+func (h *Handler[P]) withMiddleware(handler func(Ctx[P]) error) func(Ctx[P]) error {
+	h.mux.RLock()
+	middleware := make([]Middleware, len(h.middleware))
+	copy(middleware, h.middleware)
+	h.mux.RUnlock()
+
+	if len(middleware) == 0 {
+		return handler
+	}
+
+	next := func(ctx context.Context, cmd Command) error {
+		cmdCtx, ok := ctx.(Context)
+		if !ok || cmdCtx.ID() != cmd.ID() {
+			cmdCtx = NewContext(ctx, cmd)
+		}
+
+		casted, ok := TryCastContext[P](cmdCtx)
+		if !ok {
+			return fmt.Errorf("failed to cast context [from=%T, to=%T]", cmdCtx, casted)
+		}
+
+		return handler(casted)
+	}
+
+	for i := len(middleware) - 1; i >= 0; i-- {
+		next = middleware[i](next)
+	}
+
+	return func(ctx Ctx[P]) error {
+		cmdCtx := CastContext[any](ctx)
+		return next(cmdCtx, cmdCtx)
+	}
+}
+
+func withRetry[P any](cfg handleConfig, handler func(Ctx[P]) error) func(Ctx[P]) error {
+	if cfg.maxAttempts <= 1 {
+		return handler
+	}
+
+	return func(ctx Ctx[P]) error {
+		var attempt int
+		for {
+			attempt++
+
+			err := handler(ctx)
+			if err == nil {
+				return nil
+			}
+
+			if attempt >= cfg.maxAttempts || (cfg.retryable != nil && !cfg.retryable(err)) {
+				if attempt > 1 {
+					return fmt.Errorf("%d attempts: %w", attempt, err)
 				}
+				return err
+			}
+
+			var wait time.Duration
+			if cfg.backoff != nil {
+				wait = cfg.backoff(attempt)
+			}
+
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return fmt.Errorf("%d attempts: %w", attempt, err)
+			case <-timer.C:
 			}
 		}
 	}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -10,6 +11,10 @@ import (
 	"github.com/modernice/goes/helper/streams"
 )
 
+// ErrInvalidPayload is returned by command handlers that were registered with
+// HandleCommand if the payload of a command has an unexpected type.
+var ErrInvalidPayload = errors.New("invalid command payload")
+
 // BaseHandler can be embedded into an aggregate to implement the aggregate
 // interface. Provided methods are
 //	- RegisterCommandHandler(string, func(command.Context) error)
@@ -135,6 +140,31 @@ func (base *BaseHandler) HandleCommand(ctx command.Context) error {
 	return nil
 }
 
+// HandleCommand registers fn as the handler for the given command. The payload
+// of the command is asserted to P before fn is called. Unlike
+// command.RegisterHandler, which panics on a payload of the wrong type, the
+// registered handler returns an error that wraps ErrInvalidPayload.
+//
+//	type FooPayload struct { Foo string }
+//
+//	func NewFoo(id uuid.UUID) *Foo {
+//		foo := &Foo{Base: aggregate.New("foo", id), BaseHandler: handler.NewBase()}
+//		handler.HandleCommand(foo, "foo", func(ctx context.Context, cmd command.Of[FooPayload]) error {
+//			return foo.Foo(cmd.Payload().Foo)
+//		})
+//		return foo
+//	}
+func HandleCommand[P any](r command.Registerer, name string, fn func(context.Context, command.Of[P]) error) {
+	r.RegisterCommandHandler(name, func(ctx command.Context) error {
+		cmd, ok := command.TryCast[P, any](ctx)
+		if !ok {
+			var zero P
+			return fmt.Errorf("%w: cannot cast %T to %T [command=%v]", ErrInvalidPayload, ctx.Payload(), zero, name)
+		}
+		return fn(ctx, cmd)
+	})
+}
+
 // Aggregate is an aggregate that handles commands by itself.
 type Aggregate interface {
 	aggregate.Aggregate
@@ -187,9 +217,22 @@ func New[A Aggregate](newFunc func(uuid.UUID) A, repo aggregate.Repository, bus
 	}
 }
 
+// Use registers Middleware that wraps the command handlers of the aggregate.
+// Middleware must be registered before calling Handle.
+func (h *Of[A]) Use(mw ...command.Middleware) {
+	h.handler.Use(mw...)
+}
+
+// Shutdown gracefully shuts down the handler. It stops receiving commands and
+// waits until the commands that are currently being handled are finished, or
+// until ctx is canceled. See command.Handler.Shutdown.
+func (h *Of[A]) Shutdown(ctx context.Context) error {
+	return h.handler.Shutdown(ctx)
+}
+
 // MustHandle is like Handle but panics if there is an error.
-func (h *Of[A]) MustHandle(ctx context.Context) <-chan error {
-	errs, err := h.Handle(ctx)
+func (h *Of[A]) MustHandle(ctx context.Context, opts ...command.HandleOption) <-chan error {
+	errs, err := h.Handle(ctx, opts...)
 	if err != nil {
 		panic(fmt.Errorf("[goes/command/handler.Of@MustHandle] %w", err))
 	}
@@ -197,8 +240,9 @@ func (h *Of[A]) MustHandle(ctx context.Context) <-chan error {
 }
 
 // Handle subscribes to and handles the commands for which a handler has been
-// registered. Command errors are sent into the returned error channel.
-func (h *Of[A]) Handle(ctx context.Context) (<-chan error, error) {
+// registered. Command errors are sent into the returned error channel. The
+// provided options are applied to the handlers of every command.
+func (h *Of[A]) Handle(ctx context.Context, opts ...command.HandleOption) (<-chan error, error) {
 	names := h.newFunc(uuid.New()).CommandNames()
 
 	var out []<-chan error
@@ -208,7 +252,7 @@ func (h *Of[A]) Handle(ctx context.Context) (<-chan error, error) {
 			return h.repo.Use(ctx, a, func() error {
 				return a.HandleCommand(ctx)
 			})
-		})
+		}, opts...)
 		if err != nil {
 			return streams.FanInAll(out...), err
 		}
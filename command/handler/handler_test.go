@@ -2,6 +2,7 @@ package handler_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/google/uuid"
@@ -45,6 +46,46 @@ func TestBaseHandler_HandleCommand(t *testing.T) {
 	}
 }
 
+func TestHandleCommand(t *testing.T) {
+	h := handler.NewBase()
+
+	var handled fooPayload
+	handler.HandleCommand(h, "foo", func(ctx context.Context, cmd command.Of[fooPayload]) error {
+		handled = cmd.Payload()
+		return nil
+	})
+
+	cmd := command.NewContext[any](context.Background(), command.New[any]("foo", fooPayload{Foo: "foo"}))
+
+	if err := h.HandleCommand(cmd); err != nil {
+		t.Fatalf("HandleCommand() failed with %q", err)
+	}
+
+	if handled.Foo != "foo" {
+		t.Fatalf("handler should be called with payload %v; got %v", fooPayload{Foo: "foo"}, handled)
+	}
+}
+
+func TestHandleCommand_invalidPayload(t *testing.T) {
+	h := handler.NewBase()
+
+	var called bool
+	handler.HandleCommand(h, "foo", func(ctx context.Context, cmd command.Of[fooPayload]) error {
+		called = true
+		return nil
+	})
+
+	cmd := command.NewContext[any](context.Background(), command.New[any]("foo", "foo"))
+
+	if err := h.HandleCommand(cmd); !errors.Is(err, handler.ErrInvalidPayload) {
+		t.Fatalf("HandleCommand() should fail with %q; got %v", handler.ErrInvalidPayload, err)
+	}
+
+	if called {
+		t.Fatalf("handler should not be called for an invalid payload")
+	}
+}
+
 func TestOf_Handle(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -214,6 +255,10 @@ func TestAfterHandle_wildcard(t *testing.T) {
 	}
 }
 
+type fooPayload struct {
+	Foo string
+}
+
 type HandlerAggregate struct {
 	*aggregate.Base
 	*handler.BaseHandler
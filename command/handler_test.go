@@ -1,9 +1,538 @@
 package command_test
 
 import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
 	"github.com/modernice/goes/codec"
+	"github.com/modernice/goes/command"
+	"github.com/modernice/goes/command/cmdbus"
+	"github.com/modernice/goes/command/cmdbus/dispatch"
+	"github.com/modernice/goes/command/finish"
+	"github.com/modernice/goes/event/eventbus"
 )
 
+func TestMaxConcurrent(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bus := newMockBus()
+
+	var mux sync.Mutex
+	var running, maxRunning int
+	release := make(chan struct{})
+	started := make(chan struct{}, 10)
+
+	errs, err := command.Handle(ctx, bus, "foo-cmd", func(ctx command.Context) error {
+		mux.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mux.Unlock()
+
+		started <- struct{}{}
+		<-release
+
+		mux.Lock()
+		running--
+		mux.Unlock()
+
+		return nil
+	}, command.MaxConcurrent(3))
+	if err != nil {
+		t.Fatalf("Handle() failed with %q", err)
+	}
+	go func() {
+		for err := range errs {
+			panic(err)
+		}
+	}()
+
+	finished := make(chan struct{}, 10)
+	go func() {
+		for i := 0; i < 10; i++ {
+			bus.commands <- command.NewContext[any](ctx, command.New[any]("foo-cmd", mockPayload{}), command.WhenDone(func(context.Context, finish.Config) error {
+				finished <- struct{}{}
+				return nil
+			}))
+		}
+	}()
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("timed out. %d/%d handlers started", i, 3)
+		case <-started:
+		}
+	}
+
+	select {
+	case <-started:
+		t.Fatalf("at most %d handlers should run concurrently", 3)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+
+	for i := 0; i < 10; i++ {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("timed out. %d/%d commands finished", i, 10)
+		case <-finished:
+		}
+	}
+
+	mux.Lock()
+	defer mux.Unlock()
+
+	if maxRunning != 3 {
+		t.Fatalf("%d handlers should have run concurrently; got %d", 3, maxRunning)
+	}
+}
+
+func TestHandler_Use(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bus := newMockBus()
+	h := command.NewHandler[mockPayload](bus)
+
+	var calls []string
+	middleware := func(name string) command.Middleware {
+		return func(next func(context.Context, command.Command) error) func(context.Context, command.Command) error {
+			return func(ctx context.Context, cmd command.Command) error {
+				calls = append(calls, name)
+				return next(ctx, cmd)
+			}
+		}
+	}
+
+	h.Use(middleware("foo"), middleware("bar"))
+
+	errs, err := h.Handle(ctx, "foo-cmd", func(ctx command.Ctx[mockPayload]) error {
+		calls = append(calls, "handler:"+ctx.Payload().B)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Handle() failed with %q", err)
+	}
+	go func() {
+		for err := range errs {
+			panic(err)
+		}
+	}()
+
+	finished := make(chan error)
+	bus.commands <- command.NewContext[any](ctx, command.New[any]("foo-cmd", mockPayload{B: "baz"}), command.WhenDone(func(_ context.Context, cfg finish.Config) error {
+		finished <- cfg.Err
+		return nil
+	}))
+
+	if err := <-finished; err != nil {
+		t.Fatalf("command should be finished without error; got %q", err)
+	}
+
+	want := []string{"foo", "bar", "handler:baz"}
+	if !cmp.Equal(want, calls) {
+		t.Fatalf("middleware and handler should be called in order %v; got %v", want, calls)
+	}
+}
+
+func TestHandler_Use_shortCircuit(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bus := newMockBus()
+	h := command.NewHandler[any](bus)
+
+	mockError := errors.New("mock error")
+	h.Use(func(next func(context.Context, command.Command) error) func(context.Context, command.Command) error {
+		return func(ctx context.Context, cmd command.Command) error {
+			return mockError
+		}
+	})
+
+	var handled bool
+	errs, err := h.Handle(ctx, "foo-cmd", func(ctx command.Context) error {
+		handled = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Handle() failed with %q", err)
+	}
+
+	finished := make(chan error, 1)
+	bus.commands <- command.NewContext[any](ctx, command.New[any]("foo-cmd", mockPayload{}), command.WhenDone(func(_ context.Context, cfg finish.Config) error {
+		finished <- cfg.Err
+		return nil
+	}))
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("timed out")
+	case err := <-errs:
+		if !errors.Is(err, mockError) {
+			t.Fatalf("Handle() should report %q; got %q", mockError, err)
+		}
+	}
+
+	if err := <-finished; !errors.Is(err, mockError) {
+		t.Fatalf("command should be finished with %q; got %q", mockError, err)
+	}
+
+	if handled {
+		t.Fatalf("handler should not be called when middleware returns an error")
+	}
+}
+
+func TestRetry(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bus := newMockBus()
+
+	mockError := errors.New("mock error")
+	var attempts []int
+	var backoffs []int
+
+	errs, err := command.Handle(ctx, bus, "foo-cmd", func(ctx command.Context) error {
+		attempts = append(attempts, len(attempts)+1)
+		if len(attempts) < 3 {
+			return mockError
+		}
+		return nil
+	}, command.Retry(5, func(attempt int) time.Duration {
+		backoffs = append(backoffs, attempt)
+		return time.Millisecond
+	}, func(err error) bool {
+		return errors.Is(err, mockError)
+	}))
+	if err != nil {
+		t.Fatalf("Handle() failed with %q", err)
+	}
+	go func() {
+		for err := range errs {
+			panic(err)
+		}
+	}()
+
+	finished := make(chan error)
+	bus.commands <- command.NewContext[any](ctx, command.New[any]("foo-cmd", mockPayload{}), command.WhenDone(func(_ context.Context, cfg finish.Config) error {
+		finished <- cfg.Err
+		return nil
+	}))
+
+	if err := <-finished; err != nil {
+		t.Fatalf("command should be finished without error; got %q", err)
+	}
+
+	if len(attempts) != 3 {
+		t.Fatalf("handler should have been called %d times; got %d", 3, len(attempts))
+	}
+
+	if want := []int{1, 2}; !cmp.Equal(want, backoffs) {
+		t.Fatalf("backoff should have been called with %v; got %v", want, backoffs)
+	}
+}
+
+func TestRetry_maxAttempts(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bus := newMockBus()
+
+	mockError := errors.New("mock error")
+	var attempts int
+
+	errs, err := command.Handle(ctx, bus, "foo-cmd", func(ctx command.Context) error {
+		attempts++
+		return mockError
+	}, command.Retry(3, nil, nil))
+	if err != nil {
+		t.Fatalf("Handle() failed with %q", err)
+	}
+	go func() {
+		for range errs {
+		}
+	}()
+
+	finished := make(chan error)
+	bus.commands <- command.NewContext[any](ctx, command.New[any]("foo-cmd", mockPayload{}), command.WhenDone(func(_ context.Context, cfg finish.Config) error {
+		finished <- cfg.Err
+		return nil
+	}))
+
+	err = <-finished
+	if !errors.Is(err, mockError) {
+		t.Fatalf("command should be finished with %q; got %q", mockError, err)
+	}
+
+	if !strings.Contains(err.Error(), "3 attempts") {
+		t.Fatalf("error should contain the number of attempts; got %q", err)
+	}
+
+	if attempts != 3 {
+		t.Fatalf("handler should have been called %d times; got %d", 3, attempts)
+	}
+}
+
+func TestHandler_Shutdown(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bus := newMockBus()
+	h := command.NewHandler[any](bus)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var handled bool
+
+	errs, err := h.Handle(ctx, "foo-cmd", func(ctx command.Context) error {
+		close(started)
+		<-release
+		handled = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Handle() failed with %q", err)
+	}
+
+	go func() {
+		bus.commands <- command.NewContext[any](ctx, command.New[any]("foo-cmd", mockPayload{}))
+	}()
+
+	<-started
+
+	shutdown := make(chan error)
+	go func() { shutdown <- h.Shutdown(ctx) }()
+
+	select {
+	case err := <-shutdown:
+		t.Fatalf("Shutdown() should not return before the handler has finished; returned %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+
+	select {
+	case <-ctx.Done():
+		t.Fatalf("timed out waiting for Shutdown() to return")
+	case err := <-shutdown:
+		if err != nil {
+			t.Fatalf("Shutdown() failed with %q", err)
+		}
+	}
+
+	if !handled {
+		t.Fatalf("Shutdown() should return after the handler has finished")
+	}
+
+	for err := range errs {
+		t.Fatalf("handler should not fail; failed with %q", err)
+	}
+
+	if _, err := h.Handle(ctx, "foo-cmd", func(command.Context) error { return nil }); !errors.Is(err, command.ErrShutdown) {
+		t.Fatalf("Handle() should fail with %q after Shutdown(); got %v", command.ErrShutdown, err)
+	}
+}
+
+func TestHandler_Shutdown_dispatchDuringShutdown(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bus := cmdbus.New(newEncoder(), eventbus.New(), cmdbus.ReceiveTimeout(0), cmdbus.AssignTimeout(500*time.Millisecond))
+	h := command.NewHandler[any](bus)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+
+	errs, err := h.Handle(ctx, "foo-cmd", func(ctx command.Context) error {
+		once.Do(func() {
+			close(started)
+			<-release
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Handle() failed with %q", err)
+	}
+	go func() {
+		for range errs {
+		}
+	}()
+
+	if err := bus.Dispatch(ctx, command.New[any]("foo-cmd", mockPayload{})); err != nil {
+		t.Fatalf("Dispatch() failed with %q", err)
+	}
+
+	<-started
+
+	shutdown := make(chan error)
+	go func() { shutdown <- h.Shutdown(ctx) }()
+
+	dispatched := make(chan error)
+	go func() {
+		dispatched <- bus.Dispatch(ctx, command.New[any]("foo-cmd", mockPayload{}), dispatch.Sync())
+	}()
+
+	// Give the bus time to pass the second command to the Handler.
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+
+	select {
+	case <-ctx.Done():
+		t.Fatalf("timed out waiting for Shutdown() to return")
+	case err := <-shutdown:
+		if err != nil {
+			t.Fatalf("Shutdown() failed with %q", err)
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatalf("timed out waiting for Dispatch() to return")
+	case err := <-dispatched:
+		if err == nil {
+			t.Fatalf("Dispatch() should fail for a command that is dispatched during Shutdown()")
+		}
+	}
+
+	// The bus must still be able to subscribe handlers after the shutdown.
+	handled := make(chan struct{})
+	if _, err := command.NewHandler[any](bus).Handle(ctx, "foo-cmd", func(command.Context) error {
+		close(handled)
+		return nil
+	}); err != nil {
+		t.Fatalf("Handle() failed with %q", err)
+	}
+
+	if err := bus.Dispatch(ctx, command.New[any]("foo-cmd", mockPayload{})); err != nil {
+		t.Fatalf("Dispatch() failed with %q", err)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatalf("timed out waiting for the command to be handled")
+	case <-handled:
+	}
+}
+
+func TestHandler_Shutdown_timeout(t *testing.T) {
+	bus := newMockBus()
+	h := command.NewHandler[any](bus)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+
+	if _, err := h.Handle(context.Background(), "foo-cmd", func(ctx command.Context) error {
+		close(started)
+		<-release
+		return nil
+	}); err != nil {
+		t.Fatalf("Handle() failed with %q", err)
+	}
+
+	go func() {
+		bus.commands <- command.NewContext[any](context.Background(), command.New[any]("foo-cmd", mockPayload{}))
+	}()
+
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := h.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown() should fail with %q; got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestHandler_Handle_invalidPayload(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	bus := newMockBus()
+
+	var called bool
+	errs, err := command.Handle(ctx, bus, "foo-cmd", func(command.Ctx[mockPayload]) error {
+		called = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Handle() failed with %q", err)
+	}
+
+	finished := make(chan error, 1)
+	go func() {
+		bus.commands <- command.NewContext[any](ctx, command.New[any]("foo-cmd", "foo"), command.WhenDone(func(_ context.Context, cfg finish.Config) error {
+			finished <- cfg.Err
+			return nil
+		}))
+	}()
+
+	select {
+	case <-ctx.Done():
+		t.Fatalf("timed out waiting for error")
+	case err := <-errs:
+		if err == nil {
+			t.Fatalf("handler should fail for an invalid payload")
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatalf("timed out waiting for the command to be finished")
+	case err := <-finished:
+		if err == nil {
+			t.Fatalf("command should be finished with an error")
+		}
+	}
+
+	if called {
+		t.Fatalf("handler should not be called for an invalid payload")
+	}
+}
+
+type mockBus struct {
+	commands chan command.Context
+}
+
+func newMockBus() *mockBus {
+	return &mockBus{commands: make(chan command.Context)}
+}
+
+func (b *mockBus) Dispatch(context.Context, command.Command, ...command.DispatchOption) error {
+	return nil
+}
+
+func (b *mockBus) Subscribe(ctx context.Context, _ ...string) (<-chan command.Context, <-chan error, error) {
+	out, errs := make(chan command.Context), make(chan error)
+	go func() {
+		defer close(out)
+		defer close(errs)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case cmd := <-b.commands:
+				select {
+				case <-ctx.Done():
+					return
+				case out <- cmd:
+				}
+			}
+		}
+	}()
+	return out, errs, nil
+}
+
 // func TestHandler_Handle(t *testing.T) {
 // 	enc := newEncoder()
 // 	ebus := eventbus.New()
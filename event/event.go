@@ -78,6 +78,13 @@ type Data[D any] struct {
 	AggregateName    string
 	AggregateID      uuid.UUID
 	AggregateVersion int
+
+	// CausationID is the id of the command or event that caused the event.
+	CausationID uuid.UUID
+
+	// CorrelationID is the id that is shared by all commands and events that
+	// originate from the same initial command or event.
+	CorrelationID uuid.UUID
 }
 
 // ID returns an Option that overrides the auto-generated UUID of an event.
@@ -103,6 +110,74 @@ func Aggregate(id uuid.UUID, name string, version int) Option {
 	}
 }
 
+// CausedBy returns an Option that sets the causation id of an event to the id
+// of the command or event that caused it, which is typically the command whose
+// handler raised the event:
+//
+//	var cmd command.Command
+//	evt := event.New("foo", data, event.CausedBy(cmd))
+//
+// If the correlation id of the event has not been set by a previous option,
+// the event inherits the correlation id of the cause. If the cause has no
+// correlation id, the id of the cause becomes the correlation id of the event.
+func CausedBy(cause interface{ ID() uuid.UUID }) Option {
+	causationID := cause.ID()
+	correlationID := causationID
+	if c, ok := cause.(interface{ CorrelationID() uuid.UUID }); ok && c.CorrelationID() != uuid.Nil {
+		correlationID = c.CorrelationID()
+	}
+	return func(evt *Evt[any]) {
+		evt.D.CausationID = causationID
+		if evt.D.CorrelationID == uuid.Nil {
+			evt.D.CorrelationID = correlationID
+		}
+	}
+}
+
+// CausedByID returns an Option that sets the causation id of an event. In
+// contrast to CausedBy, CausedByID does not set the correlation id. Use it to
+// restore events from their stored fields.
+func CausedByID(id uuid.UUID) Option {
+	return func(evt *Evt[any]) {
+		evt.D.CausationID = id
+	}
+}
+
+// CorrelatedWith returns an Option that sets the correlation id of an event.
+func CorrelatedWith(id uuid.UUID) Option {
+	return func(evt *Evt[any]) {
+		evt.D.CorrelationID = id
+	}
+}
+
+// CausationID returns the id of the command or event that caused the given
+// event, or uuid.Nil if the event provides no causation id.
+func CausationID[D any](evt Of[D]) uuid.UUID {
+	if evt, ok := any(evt).(interface{ CausationID() uuid.UUID }); ok {
+		return evt.CausationID()
+	}
+	return uuid.Nil
+}
+
+// CorrelationID returns the correlation id of the given event, or uuid.Nil if
+// the event provides no correlation id.
+func CorrelationID[D any](evt Of[D]) uuid.UUID {
+	if evt, ok := any(evt).(interface{ CorrelationID() uuid.UUID }); ok {
+		return evt.CorrelationID()
+	}
+	return uuid.Nil
+}
+
+// tracing returns an Option that copies the causation and correlation ids of
+// the given event.
+func tracing[D any](evt Of[D]) Option {
+	causationID, correlationID := CausationID(evt), CorrelationID(evt)
+	return func(evt *Evt[any]) {
+		CausedByID(causationID)(evt)
+		CorrelatedWith(correlationID)(evt)
+	}
+}
+
 // Previous returns an Option that puts an event into the event stream of an
 // aggregate. If prev provides non-zero aggregate data, the created event will
 // have the same data but with its version increased by 1.
@@ -123,6 +198,8 @@ func Previous[Data any](prev Of[Data]) Option {
 //	Aggregate(string, uuid.UUID, int): Put the event into the event stream of an aggregate
 // 	Previous(event.Event): Put the event into the event stream of an aggregate
 //	based on its previous event
+//	CausedBy(command.Command): Set the causation and correlation ids of the event
+//	CorrelatedWith(uuid.UUID): Set the correlation id of the event
 func New[D any](name string, data D, opts ...Option) Evt[D] {
 	evt := Evt[any]{D: Data[any]{
 		ID:   uuid.New(),
@@ -143,6 +220,8 @@ func New[D any](name string, data D, opts ...Option) Evt[D] {
 			AggregateName:    evt.D.AggregateName,
 			AggregateID:      evt.D.AggregateID,
 			AggregateVersion: evt.D.AggregateVersion,
+			CausationID:      evt.D.CausationID,
+			CorrelationID:    evt.D.CorrelationID,
 		},
 	}
 }
@@ -152,6 +231,56 @@ func New[D any](name string, data D, opts ...Option) Evt[D] {
 // checked using a.Time().Equal(b.Time()) for the two events a and b. Event data
 // is compared using the "==" equality operator.
 func Equal(events ...Of[any]) bool {
+	return EqualWith()(events...)
+}
+
+// EqualData compares events by their names, data, and aggregates, ignoring
+// their ids and times. Use EqualData to compare events that were created
+// independently, for example in tests. EqualData is a shorthand for
+// EqualWith(IgnoreID(), IgnoreTime())(events...).
+func EqualData(events ...Of[any]) bool {
+	return EqualWith(IgnoreID(), IgnoreTime())(events...)
+}
+
+// EqualOption is an option for EqualWith.
+type EqualOption func(*equalOptions)
+
+type equalOptions struct {
+	ignoreID   bool
+	ignoreTime bool
+}
+
+// IgnoreID returns an EqualOption that ignores the ids of the compared events.
+func IgnoreID() EqualOption {
+	return func(opts *equalOptions) {
+		opts.ignoreID = true
+	}
+}
+
+// IgnoreTime returns an EqualOption that ignores the times of the compared
+// events.
+func IgnoreTime() EqualOption {
+	return func(opts *equalOptions) {
+		opts.ignoreTime = true
+	}
+}
+
+// EqualWith returns a function that compares events like Equal does, but
+// allows to ignore fields of the events using the provided options:
+//
+//	equal := event.EqualWith(event.IgnoreID())
+//	equal(a, b)
+func EqualWith(opts ...EqualOption) func(events ...Of[any]) bool {
+	var cfg equalOptions
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return func(events ...Of[any]) bool {
+		return equal(cfg, events)
+	}
+}
+
+func equal(opts equalOptions, events []Of[any]) bool {
 	if len(events) < 2 {
 		return true
 	}
@@ -164,9 +293,9 @@ func Equal(events ...Of[any]) bool {
 
 		id, name, v := evt.Aggregate()
 
-		if !(evt.ID() == first.ID() &&
+		if !((opts.ignoreID || evt.ID() == first.ID()) &&
 			evt.Name() == first.Name() &&
-			evt.Time().Equal(first.Time()) &&
+			(opts.ignoreTime || evt.Time().Equal(first.Time())) &&
 			evt.Data() == first.Data() &&
 			id == fid &&
 			name == fname &&
@@ -200,6 +329,20 @@ func SortMulti[Events ~[]Of[D], D any](events Events, sorts ...SortOptions) Even
 	return sorted
 }
 
+// SortFunc sorts events using the provided less function and returns the
+// sorted events. Events that are equal according to less keep their original
+// order. The provided events are not modified.
+func SortFunc[Events ~[]Of[D], D any](events Events, less func(a, b Of[D]) bool) Events {
+	sorted := make(Events, len(events))
+	copy(sorted, events)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return less(sorted[i], sorted[j])
+	})
+
+	return sorted
+}
+
 // ID returns the event id.
 func (evt Evt[D]) ID() uuid.UUID {
 	return evt.D.ID
@@ -226,6 +369,16 @@ func (evt Evt[D]) Aggregate() (uuid.UUID, string, int) {
 	return evt.D.AggregateID, evt.D.AggregateName, evt.D.AggregateVersion
 }
 
+// CausationID returns the id of the command or event that caused the event.
+func (evt Evt[D]) CausationID() uuid.UUID {
+	return evt.D.CausationID
+}
+
+// CorrelationID returns the correlation id of the event.
+func (evt Evt[D]) CorrelationID() uuid.UUID {
+	return evt.D.CorrelationID
+}
+
 // Any returns the event with its data type set to `any`.
 func (evt Evt[D]) Any() Evt[any] {
 	return Any[D](evt)
@@ -252,11 +405,18 @@ func Cast[To, From any](evt Of[From]) Evt[To] {
 		ID(evt.ID()),
 		Time(evt.Time()),
 		Aggregate(evt.Aggregate()),
+		tracing(evt),
 	)
 }
 
 // TryCast casts the type paramater of given event to the type `To`. Cast
-// returns false if the event data cannot be casted to `To`.
+// returns false if the event data cannot be casted to `To`. Use TryCast to
+// recover a typed event from an event that was received as an event.Of[any]:
+//
+//	var evt event.Event
+//	if foo, ok := event.TryCast[FooData](evt); ok {
+//		log.Println(foo.Data().Foo)
+//	}
 func TryCast[To, From any](evt Of[From]) (Evt[To], bool) {
 	data, ok := any(evt.Data()).(To)
 	if !ok {
@@ -269,15 +429,35 @@ func TryCast[To, From any](evt Of[From]) (Evt[To], bool) {
 		ID(evt.ID()),
 		Time(evt.Time()),
 		Aggregate(evt.Aggregate()),
+		tracing(evt),
 	), true
 }
 
+// Map returns a copy of the given event with its data mapped by fn. The id,
+// name, time, aggregate, causation id and correlation id of the returned event
+// are the same as those of the given event:
+//
+//	var evt event.Of[legacy.UserCreated]
+//	mapped := event.Map(evt, func(data legacy.UserCreated) UserCreated {
+//		return UserCreated{Name: data.Username}
+//	})
+func Map[To, From any](evt Of[From], fn func(From) To) Evt[To] {
+	return New(
+		evt.Name(),
+		fn(evt.Data()),
+		ID(evt.ID()),
+		Time(evt.Time()),
+		Aggregate(evt.Aggregate()),
+		tracing(evt),
+	)
+}
+
 // Expand returns an Evt from an interface.
 func Expand[D any](evt Of[D]) Evt[D] {
 	if evt, ok := evt.(Evt[D]); ok {
 		return evt
 	}
-	return New(evt.Name(), evt.Data(), ID(evt.ID()), Time(evt.Time()), Aggregate(evt.Aggregate()))
+	return New(evt.Name(), evt.Data(), ID(evt.ID()), Time(evt.Time()), Aggregate(evt.Aggregate()), tracing(evt))
 }
 
 // Test tests an event against a query and returns whether the query would
@@ -292,6 +472,11 @@ func Test[Data any](q Query, evt Of[Data]) bool {
 		return false
 	}
 
+	if excluded := q.ExcludedNames(); len(excluded) > 0 &&
+		stringsContains(excluded, evt.Name()) {
+		return false
+	}
+
 	if ids := q.IDs(); len(ids) > 0 && !uuidsContains(ids, evt.ID()) {
 		return false
 	}
@@ -320,6 +505,11 @@ func Test[Data any](q Query, evt Of[Data]) bool {
 		return false
 	}
 
+	if excluded := q.ExcludedAggregateNames(); len(excluded) > 0 &&
+		stringsContains(excluded, name) {
+		return false
+	}
+
 	if ids := q.AggregateIDs(); len(ids) > 0 &&
 		!uuidsContains(ids, id) {
 		return false
@@ -358,6 +548,11 @@ func Test[Data any](q Query, evt Of[Data]) bool {
 		}
 	}
 
+	if key := q.ResumeAfter(); !key.IsZero() &&
+		SortKeyOf(evt).Compare(key, ResumeSortings(q)...) <= 0 {
+		return false
+	}
+
 	return true
 }
 
@@ -6,10 +6,12 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/modernice/goes/command"
 	"github.com/modernice/goes/event"
 	"github.com/modernice/goes/event/test"
 	"github.com/modernice/goes/helper/pick"
 	"github.com/modernice/goes/internal/xtime"
+	goestest "github.com/modernice/goes/test"
 )
 
 type mockData struct {
@@ -50,6 +52,16 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNew_clock(t *testing.T) {
+	at := time.Date(2022, 1, 1, 12, 0, 0, 123456789, time.UTC)
+	goestest.WithFrozenClock(t, at)
+
+	evt := event.New("foo", newMockData())
+	if !evt.Time().Equal(at) {
+		t.Errorf("evt.Time() should return %s; got %s", at, evt.Time())
+	}
+}
+
 func TestNew_time(t *testing.T) {
 	ts := xtime.Now().Add(time.Hour)
 	evt := event.New("foo", newMockData(), event.Time(ts))
@@ -167,6 +179,91 @@ func TestEqual(t *testing.T) {
 	}
 }
 
+func TestEqualWith(t *testing.T) {
+	id := uuid.New()
+	now := xtime.Now()
+	aggregateID := uuid.New()
+
+	tests := []struct {
+		name string
+		opts []event.EqualOption
+		a    event.Evt[mockData]
+		b    event.Evt[mockData]
+		want bool
+	}{
+		{
+			name: "IgnoreID",
+			opts: []event.EqualOption{event.IgnoreID()},
+			a:    event.New("foo", mockData{FieldA: "foo"}, event.Time(now)),
+			b:    event.New("foo", mockData{FieldA: "foo"}, event.Time(now)),
+			want: true,
+		},
+		{
+			name: "IgnoreID (different time)",
+			opts: []event.EqualOption{event.IgnoreID()},
+			a:    event.New("foo", mockData{FieldA: "foo"}, event.Time(now)),
+			b:    event.New("foo", mockData{FieldA: "foo"}, event.Time(now.Add(time.Second))),
+			want: false,
+		},
+		{
+			name: "IgnoreTime",
+			opts: []event.EqualOption{event.IgnoreTime()},
+			a:    event.New("foo", mockData{FieldA: "foo"}, event.ID(id), event.Time(now)),
+			b:    event.New("foo", mockData{FieldA: "foo"}, event.ID(id), event.Time(now.Add(time.Second))),
+			want: true,
+		},
+		{
+			name: "IgnoreTime (different id)",
+			opts: []event.EqualOption{event.IgnoreTime()},
+			a:    event.New("foo", mockData{FieldA: "foo"}, event.Time(now)),
+			b:    event.New("foo", mockData{FieldA: "foo"}, event.Time(now.Add(time.Second))),
+			want: false,
+		},
+		{
+			name: "IgnoreID+IgnoreTime",
+			opts: []event.EqualOption{event.IgnoreID(), event.IgnoreTime()},
+			a:    event.New("foo", mockData{FieldA: "foo"}, event.Aggregate(aggregateID, "foobar", 1)),
+			b:    event.New("foo", mockData{FieldA: "foo"}, event.Aggregate(aggregateID, "foobar", 1)),
+			want: true,
+		},
+		{
+			name: "IgnoreID+IgnoreTime (different data)",
+			opts: []event.EqualOption{event.IgnoreID(), event.IgnoreTime()},
+			a:    event.New("foo", mockData{FieldA: "foo"}),
+			b:    event.New("foo", mockData{FieldA: "bar"}),
+			want: false,
+		},
+		{
+			name: "IgnoreID+IgnoreTime (different aggregate)",
+			opts: []event.EqualOption{event.IgnoreID(), event.IgnoreTime()},
+			a:    event.New("foo", mockData{FieldA: "foo"}, event.Aggregate(aggregateID, "foobar", 1)),
+			b:    event.New("foo", mockData{FieldA: "foo"}, event.Aggregate(aggregateID, "foobar", 2)),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := event.EqualWith(tt.opts...)(tt.a.Any(), tt.b.Any()); got != tt.want {
+				t.Errorf("EqualWith() should return %v; got %v\nevent a: %#v\n\nevent b: %#v", tt.want, got, tt.a, tt.b)
+			}
+		})
+	}
+}
+
+func TestEqualData(t *testing.T) {
+	a := event.New("foo", mockData{FieldA: "foo"}).Any()
+	b := event.New("foo", mockData{FieldA: "foo"}, event.Time(xtime.Now().Add(time.Minute))).Any()
+
+	if !event.EqualData(a, b) {
+		t.Errorf("EqualData() should return true for events with different ids and times")
+	}
+
+	if event.Equal(a, b) {
+		t.Errorf("Equal() should return false for events with different ids and times")
+	}
+}
+
 func TestEqual_variadic(t *testing.T) {
 	id := uuid.New()
 	now := xtime.Now()
@@ -187,6 +284,146 @@ func TestEqual_variadic(t *testing.T) {
 	}
 }
 
+func TestCausedBy(t *testing.T) {
+	cmd := command.New("foo", "payload")
+
+	evt := event.New("foo", newMockData(), event.CausedBy(cmd))
+
+	if evt.CausationID() != cmd.ID() {
+		t.Errorf("CausationID() should return %s; got %s", cmd.ID(), evt.CausationID())
+	}
+
+	if evt.CorrelationID() != cmd.ID() {
+		t.Errorf("CorrelationID() should return the id of the cause %s; got %s", cmd.ID(), evt.CorrelationID())
+	}
+
+	next := event.New("bar", newMockData(), event.CausedBy(evt))
+
+	if next.CausationID() != evt.ID() {
+		t.Errorf("CausationID() should return %s; got %s", evt.ID(), next.CausationID())
+	}
+
+	if next.CorrelationID() != cmd.ID() {
+		t.Errorf("CorrelationID() should return the correlation id of the cause %s; got %s", cmd.ID(), next.CorrelationID())
+	}
+}
+
+func TestCorrelatedWith(t *testing.T) {
+	correlationID := uuid.New()
+	cmd := command.New("foo", "payload")
+
+	evt := event.New("foo", newMockData(), event.CorrelatedWith(correlationID), event.CausedBy(cmd))
+
+	if evt.CorrelationID() != correlationID {
+		t.Errorf("CorrelationID() should return %s; got %s", correlationID, evt.CorrelationID())
+	}
+
+	if evt.CausationID() != cmd.ID() {
+		t.Errorf("CausationID() should return %s; got %s", cmd.ID(), evt.CausationID())
+	}
+}
+
+func TestCausationID_CorrelationID(t *testing.T) {
+	causationID := uuid.New()
+	correlationID := uuid.New()
+
+	evt := event.New("foo", newMockData(), event.CausedByID(causationID), event.CorrelatedWith(correlationID))
+
+	events := map[string]event.Event{
+		"Any":  evt.Any(),
+		"Cast": event.Cast[any](evt.Event()),
+		"Map": event.Map(evt.Event(), func(data mockData) any {
+			return data
+		}),
+	}
+
+	for name, evt := range events {
+		t.Run(name, func(t *testing.T) {
+			if id := event.CausationID(evt); id != causationID {
+				t.Errorf("CausationID() should return %s; got %s", causationID, id)
+			}
+
+			if id := event.CorrelationID(evt); id != correlationID {
+				t.Errorf("CorrelationID() should return %s; got %s", correlationID, id)
+			}
+		})
+	}
+
+	if id := event.CausationID(event.New("foo", newMockData()).Event()); id != uuid.Nil {
+		t.Errorf("CausationID() should return %s for an event without cause; got %s", uuid.Nil, id)
+	}
+}
+
+func TestMap(t *testing.T) {
+	evt := event.New("foo", newMockData(), event.Aggregate(uuid.New(), "foo", 3))
+
+	mapped := event.Map(evt.Event(), func(data mockData) string {
+		return data.FieldA + "bar"
+	})
+
+	if mapped.Data() != "foobar" {
+		t.Errorf("mapped event should have data %q; got %q", "foobar", mapped.Data())
+	}
+
+	if mapped.ID() != evt.ID() {
+		t.Errorf("mapped event should have id %s; got %s", evt.ID(), mapped.ID())
+	}
+
+	if mapped.Name() != evt.Name() {
+		t.Errorf("mapped event should have name %q; got %q", evt.Name(), mapped.Name())
+	}
+
+	if !mapped.Time().Equal(evt.Time()) {
+		t.Errorf("mapped event should have time %v; got %v", evt.Time(), mapped.Time())
+	}
+
+	id, name, v := evt.Aggregate()
+	mid, mname, mv := mapped.Aggregate()
+	if mid != id || mname != name || mv != v {
+		t.Errorf("mapped event should have aggregate (%s, %q, %d); got (%s, %q, %d)", id, name, v, mid, mname, mv)
+	}
+}
+
+func TestTryCast(t *testing.T) {
+	evt := event.New("foo", newMockData(), event.Aggregate(uuid.New(), "foo", 3))
+
+	casted, ok := event.TryCast[mockData](evt.Any().Event())
+	if !ok {
+		t.Fatalf("TryCast() should succeed for an event with %T data", mockData{})
+	}
+
+	if !event.Equal(casted.Any(), evt.Any()) {
+		t.Errorf("casted event should equal the original event\n%#v\n\n%#v", evt, casted)
+	}
+
+	if casted.Data() != evt.Data() {
+		t.Errorf("casted event should have data %v; got %v", evt.Data(), casted.Data())
+	}
+}
+
+func TestTryCast_wrongType(t *testing.T) {
+	evt := event.New[any]("foo", newMockData())
+
+	casted, ok := event.TryCast[string](evt.Event())
+	if ok {
+		t.Fatalf("TryCast() should fail for an event with %T data", evt.Data())
+	}
+
+	if casted.ID() != uuid.Nil {
+		t.Errorf("TryCast() should return a zero event on failure; got %v", casted)
+	}
+}
+
+func TestCast_panics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Cast() should panic for an event with %T data", mockData{})
+		}
+	}()
+
+	event.Cast[string](event.New[any]("foo", newMockData()).Event())
+}
+
 func newMockData() mockData {
 	return mockData{FieldA: "foo", FieldB: true}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/google/uuid"
@@ -13,11 +14,22 @@ import (
 
 // New returns a thread-safe in-memory event store. The provided events are
 // immediately inserted into the store.
+//
+// The store assigns a sequence number to each inserted event, so the order of
+// insertion that is used for the AfterID cursor of a query is strict, even
+// for events that are inserted concurrently.
 func New(events ...event.Event) event.Store {
-	return &memstore{
-		idMap:  make(map[uuid.UUID]event.Event),
-		events: events,
+	s := &memstore{
+		idMap:     make(map[uuid.UUID]event.Event),
+		positions: make(map[uuid.UUID]uint64),
 	}
+	for _, evt := range events {
+		if _, ok := s.idMap[evt.ID()]; !ok {
+			s.add(evt)
+		}
+	}
+	s.reslice()
+	return s
 }
 
 var (
@@ -29,6 +41,20 @@ type memstore struct {
 	mux    sync.RWMutex
 	events []event.Event
 	idMap  map[uuid.UUID]event.Event
+
+	// positions are the insertion sequence numbers of the events.
+	positions map[uuid.UUID]uint64
+	seq       uint64
+
+	subsMux sync.Mutex
+	subs    []*subscriber
+}
+
+type subscriber struct {
+	names  map[string]bool
+	mux    sync.Mutex
+	queue  []event.Event
+	notify chan struct{}
 }
 
 func (s *memstore) Insert(ctx context.Context, events ...event.Event) error {
@@ -47,10 +73,56 @@ func (s *memstore) insert(ctx context.Context, evt event.Event) error {
 	defer s.reslice()
 	s.mux.Lock()
 	defer s.mux.Unlock()
+	s.add(evt)
+	s.broadcast(evt)
+	return nil
+}
+
+func (s *memstore) add(evt event.Event) {
+	s.seq++
 	s.idMap[evt.ID()] = evt
+	s.positions[evt.ID()] = s.seq
+}
+
+// InsertAtomic inserts events into the store. If any of the events already
+// exists in the store, none of the events are inserted.
+func (s *memstore) InsertAtomic(ctx context.Context, events ...event.Event) error {
+	defer s.reslice()
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	ids := make(map[uuid.UUID]bool, len(events))
+	for _, evt := range events {
+		if _, ok := s.idMap[evt.ID()]; ok || ids[evt.ID()] {
+			return fmt.Errorf("%s:%s %w", evt.Name(), evt.ID(), errDuplicateEvent)
+		}
+		ids[evt.ID()] = true
+	}
+
+	for _, evt := range events {
+		s.add(evt)
+		s.broadcast(evt)
+	}
+
 	return nil
 }
 
+func (s *memstore) InsertIdempotent(ctx context.Context, events ...event.Event) (int, error) {
+	defer s.reslice()
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var inserted int
+	for _, evt := range events {
+		if _, ok := s.idMap[evt.ID()]; ok {
+			continue
+		}
+		s.add(evt)
+		s.broadcast(evt)
+		inserted++
+	}
+	return inserted, nil
+}
+
 func (s *memstore) Find(ctx context.Context, id uuid.UUID) (event.Event, error) {
 	s.mux.RLock()
 	defer s.mux.RUnlock()
@@ -61,15 +133,33 @@ func (s *memstore) Find(ctx context.Context, id uuid.UUID) (event.Event, error)
 }
 
 func (s *memstore) Query(ctx context.Context, q event.Query) (<-chan event.Event, <-chan error, error) {
+	if q.AfterID() != uuid.Nil && (len(q.Sortings()) > 0 || !q.ResumeAfter().IsZero()) {
+		return nil, nil, event.ErrCursorWithSortings
+	}
+
 	s.mux.RLock()
 	defer s.mux.RUnlock()
+
+	cursor, err := s.cursor(q)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var events []event.Event
 	for _, evt := range s.events {
-		if query.Test(q, evt) {
+		if s.positions[evt.ID()] > cursor && query.Test(q, evt) {
 			events = append(events, evt)
 		}
 	}
-	events = event.SortMulti(events, q.Sortings()...)
+
+	if !q.ResumeAfter().IsZero() {
+		sortings := event.ResumeSortings(q)
+		events = event.SortFunc(events, func(a, b event.Event) bool {
+			return event.SortKeyOf(a).Compare(event.SortKeyOf(b), sortings...) < 0
+		})
+	} else if sortings := q.Sortings(); len(sortings) > 0 {
+		events = event.SortMulti(events, sortings...)
+	}
 
 	out := make(chan event.Event)
 	errs := make(chan error)
@@ -89,16 +179,156 @@ func (s *memstore) Query(ctx context.Context, q event.Query) (<-chan event.Event
 	return out, errs, nil
 }
 
+func (s *memstore) Count(ctx context.Context, q event.Query) (int, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	cursor, err := s.cursor(q)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	for _, evt := range s.events {
+		if s.positions[evt.ID()] > cursor && query.Test(q, evt) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// cursor returns the insertion sequence number of the AfterID event of q, or
+// 0 if q has no cursor.
+func (s *memstore) cursor(q event.Query) (uint64, error) {
+	id := q.AfterID()
+	if id == uuid.Nil {
+		return 0, nil
+	}
+	pos, ok := s.positions[id]
+	if !ok {
+		return 0, fmt.Errorf("%w [id=%v]", event.ErrCursorNotFound, id)
+	}
+	return pos, nil
+}
+
 func (s *memstore) Delete(ctx context.Context, events ...event.Event) error {
 	defer s.reslice()
 	s.mux.Lock()
 	defer s.mux.Unlock()
 	for _, evt := range events {
 		delete(s.idMap, evt.ID())
+		delete(s.positions, evt.ID())
 	}
 	return nil
 }
 
+func (s *memstore) DeleteQuery(ctx context.Context, q event.Query) (int, error) {
+	if !event.HasFilter(q) {
+		return 0, event.ErrEmptyQuery
+	}
+
+	defer s.reslice()
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	cursor, err := s.cursor(q)
+	if err != nil {
+		return 0, err
+	}
+
+	var deleted int
+	for _, evt := range s.events {
+		if s.positions[evt.ID()] > cursor && query.Test(q, evt) {
+			delete(s.idMap, evt.ID())
+			delete(s.positions, evt.ID())
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (s *memstore) Subscribe(ctx context.Context, names ...string) (<-chan event.Event, <-chan error, error) {
+	sub := &subscriber{
+		names:  make(map[string]bool, len(names)),
+		notify: make(chan struct{}, 1),
+	}
+	for _, name := range names {
+		sub.names[name] = true
+	}
+
+	s.subsMux.Lock()
+	s.subs = append(s.subs, sub)
+	s.subsMux.Unlock()
+
+	out, errs := make(chan event.Event), make(chan error)
+
+	go func() {
+		defer close(errs)
+		defer close(out)
+		defer s.unsubscribe(sub)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sub.notify:
+			}
+
+			for _, evt := range sub.drain() {
+				select {
+				case <-ctx.Done():
+					return
+				case out <- evt:
+				}
+			}
+		}
+	}()
+
+	return out, errs, nil
+}
+
+// broadcast queues evt for every subscriber of the event. broadcast does not
+// block, so that inserts are not slowed down by slow subscribers.
+func (s *memstore) broadcast(evt event.Event) {
+	s.subsMux.Lock()
+	defer s.subsMux.Unlock()
+	for _, sub := range s.subs {
+		if len(sub.names) > 0 && !sub.names[evt.Name()] {
+			continue
+		}
+
+		sub.mux.Lock()
+		sub.queue = append(sub.queue, evt)
+		sub.mux.Unlock()
+
+		select {
+		case sub.notify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (s *memstore) unsubscribe(sub *subscriber) {
+	s.subsMux.Lock()
+	defer s.subsMux.Unlock()
+	for i, ssub := range s.subs {
+		if ssub == sub {
+			s.subs = append(s.subs[:i], s.subs[i+1:]...)
+			return
+		}
+	}
+}
+
+func (sub *subscriber) drain() []event.Event {
+	sub.mux.Lock()
+	defer sub.mux.Unlock()
+	events := sub.queue
+	sub.queue = nil
+	return events
+}
+
+// reslice rebuilds the event slice from the id map. The events are ordered
+// by their insertion sequence.
 func (s *memstore) reslice() {
 	s.mux.Lock()
 	defer s.mux.Unlock()
@@ -106,4 +336,7 @@ func (s *memstore) reslice() {
 	for _, evt := range s.idMap {
 		s.events = append(s.events, evt)
 	}
+	sort.Slice(s.events, func(i, j int) bool {
+		return s.positions[s.events[i].ID()] < s.positions[s.events[j].ID()]
+	})
 }
@@ -1,12 +1,16 @@
 package eventstore_test
 
 import (
+	"context"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/modernice/goes/backend/testing/eventstoretest"
 	"github.com/modernice/goes/codec"
 	"github.com/modernice/goes/event"
 	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/goes/event/query"
+	"github.com/modernice/goes/event/test"
 )
 
 var _ event.Store = eventstore.New()
@@ -16,3 +20,39 @@ func TestMemstore(t *testing.T) {
 		return eventstore.New()
 	})
 }
+
+func TestMemstore_InsertAtomic(t *testing.T) {
+	existing := event.New[any]("foo", test.FooEventData{})
+	store := eventstore.New(existing)
+
+	atomic, ok := store.(event.AtomicInserter)
+	if !ok {
+		t.Fatalf("memstore should implement %T", (*event.AtomicInserter)(nil))
+	}
+
+	events := []event.Event{
+		event.New[any]("foo", test.FooEventData{}, event.Aggregate(uuid.New(), "foo", 1)),
+		existing,
+		event.New[any]("bar", test.BarEventData{}, event.Aggregate(uuid.New(), "bar", 1)),
+	}
+
+	if err := atomic.InsertAtomic(context.Background(), events...); err == nil {
+		t.Fatalf("InsertAtomic should fail if an event already exists")
+	}
+
+	for _, evt := range []event.Event{events[0], events[2]} {
+		if _, err := store.Find(context.Background(), evt.ID()); err == nil {
+			t.Fatalf("no event should have been inserted; found %q event", evt.Name())
+		}
+	}
+
+	events[1] = event.New[any]("baz", test.BazEventData{})
+
+	if err := atomic.InsertAtomic(context.Background(), events...); err != nil {
+		t.Fatalf("InsertAtomic failed with %q", err)
+	}
+
+	if count, err := store.Count(context.Background(), query.New()); err != nil || count != 4 {
+		t.Fatalf("store should contain %d events; got %d (err=%v)", 4, count, err)
+	}
+}
@@ -0,0 +1,49 @@
+package event
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/modernice/goes/codec"
+)
+
+// Hash returns a fingerprint of the semantic content of an event. The hash is
+// the SHA-256 of the event name, the aggregate name, id, and version, and the
+// event data encoded by enc. The id and time of the event are not part of the
+// hash, so events that were redelivered with new ids have the same hash. Use
+// Hash to deduplicate events from sources that do not preserve event ids,
+// where Store.InsertIdempotent cannot detect duplicates.
+//
+// The hash is only stable if enc encodes equal data into equal bytes. For
+// example, encoding/gob does not guarantee the order of map entries, while
+// encoding/json sorts the keys of maps.
+func Hash[D any](evt Of[D], enc codec.Encoding) ([32]byte, error) {
+	var data bytes.Buffer
+	if err := enc.Encode(&data, evt.Name(), evt.Data()); err != nil {
+		return [32]byte{}, fmt.Errorf("encode %q event data: %w", evt.Name(), err)
+	}
+
+	id, name, v := evt.Aggregate()
+
+	h := sha256.New()
+	writeHashField(h, []byte(evt.Name()))
+	writeHashField(h, []byte(name))
+	writeHashField(h, id[:])
+	binary.Write(h, binary.BigEndian, int64(v))
+	writeHashField(h, data.Bytes())
+
+	var sum [32]byte
+	copy(sum[:], h.Sum(nil))
+
+	return sum, nil
+}
+
+// writeHashField writes the length of b followed by b, so that the boundaries
+// of the hashed fields are unambiguous.
+func writeHashField(h io.Writer, b []byte) {
+	binary.Write(h, binary.BigEndian, uint64(len(b)))
+	h.Write(b)
+}
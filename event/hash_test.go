@@ -0,0 +1,61 @@
+package event_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/event/test"
+)
+
+func TestHash(t *testing.T) {
+	enc := test.NewEncoder()
+	aggregateID := uuid.New()
+
+	a := event.New("foo", test.FooEventData{A: "foo"}, event.Aggregate(aggregateID, "foobar", 1))
+	// redelivered with a new id and time
+	b := event.New("foo", test.FooEventData{A: "foo"}, event.Aggregate(aggregateID, "foobar", 1))
+
+	hashA, err := event.Hash[test.FooEventData](a, enc)
+	if err != nil {
+		t.Fatalf("Hash() failed with %q", err)
+	}
+
+	hashB, err := event.Hash[test.FooEventData](b, enc)
+	if err != nil {
+		t.Fatalf("Hash() failed with %q", err)
+	}
+
+	if hashA != hashB {
+		t.Fatalf("events with equal content should have equal hashes\n%x\n%x", hashA, hashB)
+	}
+
+	others := map[string]event.Evt[test.FooEventData]{
+		"name":              event.New("foobar", test.FooEventData{A: "foo"}, event.Aggregate(aggregateID, "foobar", 1)),
+		"data":              event.New("foo", test.FooEventData{A: "bar"}, event.Aggregate(aggregateID, "foobar", 1)),
+		"aggregate name":    event.New("foo", test.FooEventData{A: "foo"}, event.Aggregate(aggregateID, "baz", 1)),
+		"aggregate id":      event.New("foo", test.FooEventData{A: "foo"}, event.Aggregate(uuid.New(), "foobar", 1)),
+		"aggregate version": event.New("foo", test.FooEventData{A: "foo"}, event.Aggregate(aggregateID, "foobar", 2)),
+	}
+
+	for name, evt := range others {
+		t.Run(name, func(t *testing.T) {
+			hash, err := event.Hash[test.FooEventData](evt, enc)
+			if err != nil {
+				t.Fatalf("Hash() failed with %q", err)
+			}
+
+			if hash == hashA {
+				t.Fatalf("events with different %s should have different hashes", name)
+			}
+		})
+	}
+}
+
+func TestHash_unregistered(t *testing.T) {
+	evt := event.New("unregistered", test.UnregisteredEventData{})
+
+	if _, err := event.Hash[test.UnregisteredEventData](evt, test.NewEncoder()); err == nil {
+		t.Fatalf("Hash() should fail for unregistered event data")
+	}
+}
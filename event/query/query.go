@@ -11,11 +11,15 @@ import (
 // A Query is used by event stores to query events.
 type Query struct {
 	names          []string
+	excludedNames  []string
 	ids            []uuid.UUID
 	aggregateNames []string
+	excludedANames []string
 	aggregateIDs   []uuid.UUID
 	aggregates     []event.AggregateRef
 	sortings       []event.SortOptions
+	afterID        uuid.UUID
+	resumeAfter    event.SortKey
 
 	times             time.Constraints
 	aggregateVersions version.Constraints
@@ -54,6 +58,16 @@ func Name(names ...string) Option {
 	}
 }
 
+// ExcludeName returns an Option that excludes events with the given names from
+// the result. Exclusions are applied after the Name filter, so the result of a
+// Query that both includes and excludes a name does not contain events with
+// that name.
+func ExcludeName(names ...string) Option {
+	return func(b *builder) {
+		b.excludedNames = appendUnique(b.excludedNames, names...)
+	}
+}
+
 // ID returns an Option that filters events by their ids.
 func ID(ids ...uuid.UUID) Option {
 	return func(b *builder) {
@@ -91,6 +105,15 @@ func AggregateName(names ...string) Option {
 	}
 }
 
+// ExcludeAggregateName returns an Option that excludes events of aggregates
+// with the given names from the result. Exclusions are applied after the
+// AggregateName filter.
+func ExcludeAggregateName(names ...string) Option {
+	return func(b *builder) {
+		b.excludedANames = appendUnique(b.excludedANames, names...)
+	}
+}
+
 // AggregateID returns an Option that filters events by their aggregate ids.
 func AggregateID(ids ...uuid.UUID) Option {
 	return func(b *builder) {
@@ -182,6 +205,29 @@ func SortByTime() Option {
 	return SortBy(event.SortTime, event.SortAsc)
 }
 
+// AfterID returns an Option that only includes events that were inserted into
+// the event store after the event with the given id. Use AfterID to resume the
+// consumption of events from the last processed event. Events are returned in
+// the order of insertion, so AfterID cannot be combined with sortings. See
+// event.Query for the ordering guarantees. When AfterID is used multiple
+// times, the last id is used.
+func AfterID(id uuid.UUID) Option {
+	return func(b *builder) {
+		b.afterID = id
+	}
+}
+
+// ResumeAfter returns an Option that only includes events that are sorted
+// after the given SortKey by the sortings of the query. Queries without
+// sortings are sorted by time. Use Resume to resume a query from a token that
+// was created by ResumeToken. When ResumeAfter is used multiple times, the
+// last key is used.
+func ResumeAfter(key event.SortKey) Option {
+	return func(b *builder) {
+		b.resumeAfter = key
+	}
+}
+
 // Test tests the event evt against the Query q and returns true if q should
 // include evt in its results. Test can be used by in-memory event.Store
 // implementations to filter events based on the query.
@@ -222,13 +268,23 @@ func Merge(queries ...event.Query) Query {
 			opts,
 			ID(q.IDs()...),
 			Name(q.Names()...),
+			ExcludeName(q.ExcludedNames()...),
 			AggregateID(q.AggregateIDs()...),
 			AggregateName(q.AggregateNames()...),
+			ExcludeAggregateName(q.ExcludedAggregateNames()...),
 			AggregateVersion(versionOpts...),
 			Aggregates(q.Aggregates()...),
 			Time(timeOpts...),
 			SortByMulti(q.Sortings()...),
 		)
+
+		if id := q.AfterID(); id != uuid.Nil {
+			opts = append(opts, AfterID(id))
+		}
+
+		if key := q.ResumeAfter(); !key.IsZero() {
+			opts = append(opts, ResumeAfter(key))
+		}
 	}
 	return New(opts...)
 }
@@ -238,6 +294,11 @@ func (q Query) Names() []string {
 	return q.names
 }
 
+// ExcludedNames returns the event names to exclude from the result.
+func (q Query) ExcludedNames() []string {
+	return q.excludedNames
+}
+
 // IDs returns the event ids to query for.
 func (q Query) IDs() []uuid.UUID {
 	return q.ids
@@ -257,6 +318,11 @@ func (q Query) AggregateNames() []string {
 	return q.aggregateNames
 }
 
+// ExcludedAggregateNames returns the aggregate names to exclude from the result.
+func (q Query) ExcludedAggregateNames() []string {
+	return q.excludedANames
+}
+
 // AggregateIDs returns the aggregate ids to query for.
 func (q Query) AggregateIDs() []uuid.UUID {
 	return q.aggregateIDs
@@ -277,6 +343,30 @@ func (q Query) Sortings() []event.SortOptions {
 	return q.sortings
 }
 
+// AfterID returns the id of the event after which the result begins.
+func (q Query) AfterID() uuid.UUID {
+	return q.afterID
+}
+
+// ResumeAfter returns the SortKey of the event after which the sorted result
+// begins.
+func (q Query) ResumeAfter() event.SortKey {
+	return q.resumeAfter
+}
+
+func appendUnique(values []string, add ...string) []string {
+L:
+	for _, v := range add {
+		for _, existing := range values {
+			if existing == v {
+				continue L
+			}
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
 func (b builder) build() Query {
 	b.times = time.Filter(b.timeConstraints...)
 	b.aggregateVersions = version.Filter(b.versionConstraints...)
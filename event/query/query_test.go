@@ -43,6 +43,42 @@ func TestNew(t *testing.T) {
 				names:             []string{"foo", "bar", "baz", "foobar"},
 			},
 		},
+		{
+			name: "AfterID",
+			opts: []Option{
+				AfterID(ids[0]),
+				AfterID(ids[1]),
+			},
+			want: Query{
+				times:             time.Filter(),
+				aggregateVersions: version.Filter(),
+				afterID:           ids[1],
+			},
+		},
+		{
+			name: "ExcludeName",
+			opts: []Option{
+				ExcludeName("foo", "bar"),
+				ExcludeName("bar", "baz"),
+			},
+			want: Query{
+				times:             time.Filter(),
+				aggregateVersions: version.Filter(),
+				excludedNames:     []string{"foo", "bar", "baz"},
+			},
+		},
+		{
+			name: "ExcludeAggregateName",
+			opts: []Option{
+				ExcludeAggregateName("foo"),
+				ExcludeAggregateName("bar"),
+			},
+			want: Query{
+				times:             time.Filter(),
+				aggregateVersions: version.Filter(),
+				excludedANames:    []string{"foo", "bar"},
+			},
+		},
 		{
 			name: "ID",
 			opts: []Option{
@@ -202,6 +238,24 @@ func TestTest(t *testing.T) {
 				event.New[any]("baz", test.BazEventData{}): false,
 			},
 		},
+		{
+			name:  "ExcludeName",
+			query: New(Name("foo", "bar"), ExcludeName("bar")),
+			tests: map[event.Event]bool{
+				event.New[any]("foo", test.FooEventData{}): true,
+				event.New[any]("bar", test.BarEventData{}): false,
+				event.New[any]("baz", test.BazEventData{}): false,
+			},
+		},
+		{
+			name:  "ExcludeAggregateName",
+			query: New(ExcludeAggregateName("bar")),
+			tests: map[event.Event]bool{
+				event.New[any]("foo", test.FooEventData{}, event.Aggregate(aggregateID, "foo", 1)): true,
+				event.New[any]("foo", test.FooEventData{}, event.Aggregate(aggregateID, "bar", 1)): false,
+				event.New[any]("foo", test.FooEventData{}):                                         true,
+			},
+		},
 		{
 			name:  "ID",
 			query: New(ID(ids[:2]...)),
@@ -0,0 +1,106 @@
+package query
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	stdtime "time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+)
+
+// resumeTokenVersion is the first byte of a resume token and allows to change
+// the token format without breaking tokens that are already persisted.
+const resumeTokenVersion byte = 1
+
+// resumeTokenSize is the size of a resume token without the aggregate name:
+// the version, the event id, the time, the aggregate id and the aggregate
+// version of the event.
+const resumeTokenSize = 1 + 16 + 8 + 16 + 8
+
+// ErrInvalidResumeToken is returned by ParseResumeToken if a token was not
+// created by ResumeToken.
+var ErrInvalidResumeToken = errors.New("invalid resume token")
+
+// ResumeToken returns an opaque token for the given event that can be passed
+// to Resume to continue a query after that event. Consumers of an event query
+// can persist the token of the last processed event and resume from it after
+// a restart, instead of processing all events again:
+//
+//	var store event.Store
+//	var token []byte // loaded from persistent storage, nil on first run
+//	resume, err := query.Resume(token)
+//	// handle err
+//	str, errs, err := store.Query(context.TODO(), query.New(resume, query.SortByTime()))
+//	// handle err
+//	err := streams.Walk(context.TODO(), func(evt event.Event) error {
+//		// process evt
+//		token = query.ResumeToken(evt)
+//		// persist token
+//		return nil
+//	}, str, errs)
+//
+// A token contains the SortKey of the event, i.e. the values of every field
+// that a query can be sorted by and the event id. The resumed query returns
+// the events that are sorted after these values, so the token must be used
+// with the same sortings as the query that returned the event. The referenced
+// event itself is not looked up, so a token stays valid if the event is
+// deleted from the store.
+func ResumeToken[D any](evt event.Of[D]) []byte {
+	key := event.SortKeyOf(evt)
+
+	token := make([]byte, resumeTokenSize, resumeTokenSize+len(key.AggregateName))
+	token[0] = resumeTokenVersion
+	copy(token[1:17], key.ID[:])
+	binary.BigEndian.PutUint64(token[17:25], uint64(key.Time.UnixNano()))
+	copy(token[25:41], key.AggregateID[:])
+	binary.BigEndian.PutUint64(token[41:49], uint64(key.AggregateVersion))
+
+	return append(token, key.AggregateName...)
+}
+
+// ParseResumeToken returns the SortKey of the event that the given token was
+// created for by ResumeToken. A nil or empty token returns a zero SortKey.
+func ParseResumeToken(token []byte) (event.SortKey, error) {
+	if len(token) == 0 {
+		return event.SortKey{}, nil
+	}
+
+	if token[0] != resumeTokenVersion {
+		return event.SortKey{}, fmt.Errorf("%w: unknown version %d", ErrInvalidResumeToken, token[0])
+	}
+
+	if len(token) < resumeTokenSize {
+		return event.SortKey{}, fmt.Errorf("%w: token has %d bytes; need at least %d", ErrInvalidResumeToken, len(token), resumeTokenSize)
+	}
+
+	id, _ := uuid.FromBytes(token[1:17])
+	aggregateID, _ := uuid.FromBytes(token[25:41])
+
+	if id == uuid.Nil {
+		return event.SortKey{}, fmt.Errorf("%w: nil event id", ErrInvalidResumeToken)
+	}
+
+	return event.SortKey{
+		ID:               id,
+		Time:             stdtime.Unix(0, int64(binary.BigEndian.Uint64(token[17:25]))),
+		AggregateName:    string(token[resumeTokenSize:]),
+		AggregateID:      aggregateID,
+		AggregateVersion: int(int64(binary.BigEndian.Uint64(token[41:49]))),
+	}, nil
+}
+
+// Resume returns an Option that continues a query after the event that the
+// given token was created for by ResumeToken. Resume is a shorthand for
+// ResumeAfter with the SortKey of the token. A nil or empty token does not
+// limit the query, so that the first run of a consumer queries all events.
+// Resume returns an error that unwraps to ErrInvalidResumeToken if the token
+// is invalid, e.g. because it was truncated in persistent storage.
+func Resume(token []byte) (Option, error) {
+	key, err := ParseResumeToken(token)
+	if err != nil {
+		return nil, err
+	}
+	return ResumeAfter(key), nil
+}
@@ -0,0 +1,102 @@
+package query
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/event/test"
+)
+
+func TestResumeToken(t *testing.T) {
+	evt := event.New("foo", test.FooEventData{}, event.Aggregate(uuid.New(), "bar", 3))
+
+	token := ResumeToken[test.FooEventData](evt)
+
+	key, err := ParseResumeToken(token)
+	if err != nil {
+		t.Fatalf("ParseResumeToken() failed with %q", err)
+	}
+
+	want := event.SortKeyOf[test.FooEventData](evt)
+	if key.ID != want.ID || !key.Time.Equal(want.Time) ||
+		key.AggregateName != want.AggregateName || key.AggregateID != want.AggregateID ||
+		key.AggregateVersion != want.AggregateVersion {
+		t.Fatalf("ParseResumeToken() should return %v; got %v", want, key)
+	}
+
+	resume, err := Resume(token)
+	if err != nil {
+		t.Fatalf("Resume() failed with %q", err)
+	}
+
+	q := New(resume)
+	if q.ResumeAfter().ID != evt.ID() {
+		t.Fatalf("ResumeAfter().ID should return %s; got %s", evt.ID(), q.ResumeAfter().ID)
+	}
+}
+
+func TestResume_empty(t *testing.T) {
+	resume, err := Resume(nil)
+	if err != nil {
+		t.Fatalf("Resume() failed with %q", err)
+	}
+
+	q := New(resume)
+	if !q.ResumeAfter().IsZero() {
+		t.Fatalf("ResumeAfter() should return a zero SortKey for an empty token; got %v", q.ResumeAfter())
+	}
+}
+
+func TestResume_test(t *testing.T) {
+	now := time.Now()
+	events := []event.Event{
+		event.New[any]("foo", test.FooEventData{}, event.Time(now)),
+		event.New[any]("foo", test.FooEventData{}, event.Time(now)),
+		event.New[any]("foo", test.FooEventData{}, event.Time(now.Add(time.Second))),
+		event.New[any]("foo", test.FooEventData{}, event.Time(now.Add(-time.Second))),
+	}
+
+	sorted := event.SortFunc(events, func(a, b event.Event) bool {
+		return event.SortKeyOf(a).Compare(event.SortKeyOf(b), event.SortOptions{Sort: event.SortTime, Dir: event.SortAsc}) < 0
+	})
+
+	for i, evt := range sorted {
+		resume, err := Resume(ResumeToken(evt))
+		if err != nil {
+			t.Fatalf("Resume() failed with %q", err)
+		}
+
+		result := Apply(New(resume), sorted...)
+		test.AssertEqualEvents(t, sorted[i+1:], result)
+	}
+}
+
+func TestParseResumeToken_invalid(t *testing.T) {
+	id := uuid.New()
+
+	tokens := map[string][]byte{
+		"unknown version": append([]byte{2}, make([]byte, resumeTokenSize)...),
+		"truncated":       append([]byte{resumeTokenVersion}, id[:]...),
+		"nil id":          append([]byte{resumeTokenVersion}, make([]byte, resumeTokenSize)...),
+	}
+
+	for name, token := range tokens {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseResumeToken(token); !errors.Is(err, ErrInvalidResumeToken) {
+				t.Fatalf("ParseResumeToken() should fail with %q; got %v", ErrInvalidResumeToken, err)
+			}
+
+			opt, err := Resume(token)
+			if !errors.Is(err, ErrInvalidResumeToken) {
+				t.Fatalf("Resume() should fail with %q; got %v", ErrInvalidResumeToken, err)
+			}
+
+			if opt != nil {
+				t.Fatalf("Resume() should return a nil Option for an invalid token")
+			}
+		})
+	}
+}
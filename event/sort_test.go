@@ -138,3 +138,23 @@ func TestSortMulti(t *testing.T) {
 		events[3], events[4], events[5],
 	}, sorted)
 }
+
+func TestSortFunc(t *testing.T) {
+	events := []event.Event{
+		event.New[any]("foo", test.FooEventData{A: "c"}),
+		event.New[any]("foo", test.FooEventData{A: "a"}),
+		event.New[any]("bar", test.FooEventData{A: "b"}),
+		event.New[any]("baz", test.FooEventData{A: "a"}),
+	}
+
+	sorted := event.SortFunc(events, func(a, b event.Event) bool {
+		return a.Data().(test.FooEventData).A < b.Data().(test.FooEventData).A
+	})
+
+	// events with equal data keep their original order
+	test.AssertEqualEvents(t, []event.Event{events[1], events[3], events[2], events[0]}, sorted)
+
+	if events[0].Name() != "foo" || events[0].Data().(test.FooEventData).A != "c" {
+		t.Errorf("SortFunc() should not modify the provided events")
+	}
+}
@@ -1,15 +1,34 @@
 package event
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
+	stdtime "time"
 
 	"github.com/google/uuid"
 	"github.com/modernice/goes/event/query/time"
 	"github.com/modernice/goes/event/query/version"
 )
 
+var (
+	// ErrCursorNotFound is returned by an event store when the event referenced
+	// by the AfterID of a Query does not exist in the store.
+	ErrCursorNotFound = errors.New("cursor event not found")
+
+	// ErrCursorWithSortings is returned by an event store when a Query has both
+	// an AfterID and sortings or a ResumeAfter key. A cursor only marks a
+	// position within the order of insertion, so it cannot be combined with a
+	// different order.
+	ErrCursorWithSortings = errors.New("cursor cannot be combined with sortings")
+
+	// ErrEmptyQuery is returned by DeleteQuery if the provided Query has no
+	// filters and would therefore delete every event in the store.
+	ErrEmptyQuery = errors.New("query has no filters")
+)
+
 const (
 	// SortTime sorts events by time.
 	SortTime = Sorting(iota)
@@ -33,6 +52,10 @@ type Store interface {
 	// Insert inserts events into the store.
 	Insert(context.Context, ...Event) error
 
+	// InsertIdempotent inserts events into the store, skipping events whose id
+	// already exists in the store, and returns the number of inserted events.
+	InsertIdempotent(context.Context, ...Event) (int, error)
+
 	// Find fetches the given event from the store.
 	Find(context.Context, uuid.UUID) (Event, error)
 
@@ -49,8 +72,56 @@ type Store interface {
 	//	// handle err
 	Query(context.Context, Query) (<-chan Event, <-chan error, error)
 
+	// Count returns the number of events that match the given Query. Count
+	// ignores the sortings of the Query.
+	Count(context.Context, Query) (int, error)
+
 	// Delete deletes events from the store.
 	Delete(context.Context, ...Event) error
+
+	// DeleteQuery deletes all events that match the given Query in a single
+	// operation and returns the number of deleted events. DeleteQuery ignores
+	// the sortings of the Query. To prevent the accidental deletion of every
+	// event in the store, the Query must have at least one filter, otherwise
+	// DeleteQuery returns ErrEmptyQuery.
+	//
+	//	var store event.Store
+	//	deleted, err := store.DeleteQuery(context.TODO(), query.New(
+	//		query.Aggregate("user", userID),
+	//	))
+	DeleteQuery(context.Context, Query) (int, error)
+
+	// Subscribe subscribes to events that are inserted into the store after
+	// the subscription was made, and returns two channels – one for the
+	// inserted events and one for any asynchronous errors that occur during
+	// the subscription. If event names are provided, only events with one of
+	// the names are received; otherwise every inserted event is received.
+	// Events are received in the order they were inserted. When the provided
+	// context is canceled, the returned channels are closed.
+	//
+	//	var store event.Store
+	//	events, errs, err := store.Subscribe(context.TODO(), "foo", "bar")
+	//	// handle err
+	//	err := streams.Walk(context.TODO(), func(evt event.Event) {
+	//		log.Println(fmt.Sprintf("Inserted event: %s", evt.Name()))
+	//	}, events, errs)
+	//	// handle err
+	Subscribe(ctx context.Context, names ...string) (<-chan Event, <-chan error, error)
+}
+
+// An AtomicInserter is a Store that can insert events atomically. Either all
+// of the events that are passed to InsertAtomic are inserted, or none of them,
+// even if the events belong to different aggregates. Use an AtomicInserter to
+// update multiple aggregates in a single step, like in a saga:
+//
+//	var store event.Store
+//	if atomic, ok := store.(event.AtomicInserter); ok {
+//		err := atomic.InsertAtomic(context.TODO(), orderEvents...)
+//	}
+//
+// The in-memory store and the MongoDB store implement AtomicInserter.
+type AtomicInserter interface {
+	InsertAtomic(context.Context, ...Event) error
 }
 
 // A Query can be used to query events from an event store. Each of the query's
@@ -62,6 +133,11 @@ type Query interface {
 	// Names returns the event names to query for.
 	Names() []string
 
+	// ExcludedNames returns the event names to exclude from the result.
+	// Exclusions are applied after the Names filter, so an event whose name
+	// is both included and excluded is not part of the result.
+	ExcludedNames() []string
+
 	// IDs returns the event ids to query for.
 	IDs() []uuid.UUID
 
@@ -71,6 +147,10 @@ type Query interface {
 	// AggregateNames returns the aggregate names to query for.
 	AggregateNames() []string
 
+	// ExcludedAggregateNames returns the aggregate names to exclude from the
+	// result. Exclusions are applied after the AggregateNames filter.
+	ExcludedAggregateNames() []string
+
 	// AggregateIDs returns the aggregate ids to query for.
 	AggregateIDs() []uuid.UUID
 
@@ -97,6 +177,55 @@ type Query interface {
 	// Sorting returns the sorting options for the query. Events are sorted as
 	// they would be by calling SortMulti().
 	Sortings() []SortOptions
+
+	// AfterID returns the id of the event after which the result of the query
+	// begins, or uuid.Nil if the result is not limited by a cursor. When
+	// non-nil, only events that were inserted into the store after the
+	// referenced event are returned, in the order of insertion. If the
+	// referenced event does not exist, the store returns an error that unwraps
+	// to ErrCursorNotFound. A cursor cannot be combined with sortings; Query
+	// returns ErrCursorWithSortings for such queries. Each store documents how
+	// it determines the order of insertion. The cursor is not considered by
+	// Test().
+	AfterID() uuid.UUID
+
+	// ResumeAfter returns the SortKey of the event after which the sorted
+	// result of the query begins, or a zero SortKey if the result is not
+	// limited by it. When non-zero, only events that are sorted after the key
+	// by the sortings of the query are returned (see ResumeSortings). Events
+	// that are equal for all sortings are ordered by their ids, so that the
+	// result of the query is the exact continuation of a previous result that
+	// ended at the key, even if the event of the key has since been deleted.
+	ResumeAfter() SortKey
+}
+
+// HasFilter returns whether the Query q has at least one filter that limits
+// its result. Sortings are not considered filters.
+func HasFilter(q Query) bool {
+	if q == nil {
+		return false
+	}
+
+	if len(q.Names()) > 0 || len(q.ExcludedNames()) > 0 || len(q.IDs()) > 0 ||
+		len(q.AggregateNames()) > 0 || len(q.ExcludedAggregateNames()) > 0 ||
+		len(q.AggregateIDs()) > 0 || len(q.Aggregates()) > 0 || q.AfterID() != uuid.Nil ||
+		!q.ResumeAfter().IsZero() {
+		return true
+	}
+
+	if times := q.Times(); times != nil {
+		if len(times.Exact()) > 0 || len(times.Ranges()) > 0 || !times.Min().IsZero() || !times.Max().IsZero() {
+			return true
+		}
+	}
+
+	if versions := q.AggregateVersions(); versions != nil {
+		if len(versions.Exact()) > 0 || len(versions.Ranges()) > 0 || len(versions.Min()) > 0 || len(versions.Max()) > 0 {
+			return true
+		}
+	}
+
+	return false
 }
 
 // AggregateRef is a reference to a specific aggregate, identified by its name
@@ -119,6 +248,7 @@ type Sorting int
 type SortDirection int
 
 // CompareSorting compares a and b based on the given sorting and returns
+//
 //	-1 if a < b
 //	0 is a == b
 //	1 if a > b
@@ -148,7 +278,76 @@ func CompareSorting[A, B any](s Sorting, a Of[A], b Of[B]) (cmp int8) {
 	return
 }
 
+// A SortKey is the position of an event within the sorted result of a query.
+// It contains the values of the event for every Sorting, and the event id,
+// which orders events that are equal for all sortings of a query.
+type SortKey struct {
+	ID               uuid.UUID
+	Time             stdtime.Time
+	AggregateName    string
+	AggregateID      uuid.UUID
+	AggregateVersion int
+}
+
+// SortKeyOf returns the SortKey of the given event.
+func SortKeyOf[D any](evt Of[D]) SortKey {
+	id, name, v := evt.Aggregate()
+	return SortKey{
+		ID:               evt.ID(),
+		Time:             evt.Time(),
+		AggregateName:    name,
+		AggregateID:      id,
+		AggregateVersion: v,
+	}
+}
+
+// IsZero returns whether key has a nil-UUID as its event id.
+func (key SortKey) IsZero() bool {
+	return key.ID == uuid.Nil
+}
+
+// Compare compares key and other based on the given sortings and returns
+//
+//	-1 if key is sorted before other
+//	0 if key == other
+//	1 if key is sorted after other
+//
+// Keys that are equal for all sortings are compared by their event ids.
+func (key SortKey) Compare(other SortKey, sorts ...SortOptions) int8 {
+	for _, opts := range sorts {
+		var cmp int8
+		switch opts.Sort {
+		case SortTime:
+			cmp = boolToCmp(key.Time.Before(other.Time), key.Time.Equal(other.Time))
+		case SortAggregateName:
+			cmp = boolToCmp(key.AggregateName < other.AggregateName, key.AggregateName == other.AggregateName)
+		case SortAggregateID:
+			cmp = int8(bytes.Compare(key.AggregateID[:], other.AggregateID[:]))
+		case SortAggregateVersion:
+			cmp = boolToCmp(key.AggregateVersion < other.AggregateVersion, key.AggregateVersion == other.AggregateVersion)
+		}
+		if cmp != 0 {
+			if opts.Dir == SortDesc {
+				return -cmp
+			}
+			return cmp
+		}
+	}
+	return int8(bytes.Compare(key.ID[:], other.ID[:]))
+}
+
+// ResumeSortings returns the sortings that order the result of a query with a
+// ResumeAfter key: the sortings of q, or ascending time if q has no sortings.
+// Event stores sort such a result by these sortings and then by event id.
+func ResumeSortings(q Query) []SortOptions {
+	if sortings := q.Sortings(); len(sortings) > 0 {
+		return sortings
+	}
+	return []SortOptions{{Sort: SortTime, Dir: SortAsc}}
+}
+
 // Compare compares a and b based on the given sorting and returns
+//
 //	-1 if a < b
 //	0 is a == b
 //	1 if a > b
@@ -196,6 +395,7 @@ func (ref AggregateRef) Split() (uuid.UUID, string) {
 }
 
 // String returns the string representation of the aggregate:
+//
 //	"NAME(ID)"
 func (ref AggregateRef) String() string {
 	return fmt.Sprintf("%s(%s)", ref.Name, ref.ID)
@@ -0,0 +1,76 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/helper/streams"
+)
+
+// ExpectEvents drains the event stream and reports an error to t if the
+// received events don't match the wanted events in the same order. Events are
+// compared using event.Equal. The error message lists each mismatching
+// position together with the wanted and received event. If ctx is canceled
+// before the stream is closed, an error is reported as well.
+//
+//	str, errs, err := store.Query(ctx, query.New())
+//	// handle err
+//	test.ExpectEvents(t, ctx, str, foo, bar)
+func ExpectEvents(t testing.TB, ctx context.Context, str <-chan event.Event, want ...event.Event) {
+	t.Helper()
+	ExpectEventsWith()(t, ctx, str, want...)
+}
+
+// ExpectEventsWith returns a function that works like ExpectEvents, but
+// compares the events using event.EqualWith and the provided options. Use
+// event.IgnoreID and event.IgnoreTime to compare events that were created
+// independently of the expected events:
+//
+//	expect := test.ExpectEventsWith(event.IgnoreID(), event.IgnoreTime())
+//	expect(t, ctx, str, foo, bar)
+func ExpectEventsWith(opts ...event.EqualOption) func(testing.TB, context.Context, <-chan event.Event, ...event.Event) {
+	equal := event.EqualWith(opts...)
+	return func(t testing.TB, ctx context.Context, str <-chan event.Event, want ...event.Event) {
+		t.Helper()
+
+		got, err := streams.Drain(ctx, str)
+		if err != nil {
+			t.Errorf("drain event stream: %v", err)
+			return
+		}
+
+		var msg strings.Builder
+		n := len(want)
+		if len(got) > n {
+			n = len(got)
+		}
+		for i := 0; i < n; i++ {
+			var w, g event.Event
+			if i < len(want) {
+				w = want[i]
+			}
+			if i < len(got) {
+				g = got[i]
+			}
+			if w != nil && g != nil && equal(w, g) {
+				continue
+			}
+			fmt.Fprintf(&msg, "\n[%d]\n\twant: %s\n\tgot:  %s", i, formatEvent(w), formatEvent(g))
+		}
+
+		if msg.Len() > 0 {
+			t.Errorf("event stream doesn't yield the expected events (want %d, got %d):%s", len(want), len(got), msg.String())
+		}
+	}
+}
+
+func formatEvent(evt event.Event) string {
+	if evt == nil {
+		return "<none>"
+	}
+	id, name, v := evt.Aggregate()
+	return fmt.Sprintf("%q event (id=%s, time=%s, aggregate=%s(%s)@%d) %#v", evt.Name(), evt.ID(), evt.Time(), name, id, v, evt.Data())
+}
@@ -0,0 +1,92 @@
+package test_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/event/test"
+	"github.com/modernice/goes/helper/streams"
+)
+
+// recorder records the errors that are reported by the helpers under test
+// instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestExpectEvents(t *testing.T) {
+	events := []event.Event{
+		event.New("foo", test.FooEventData{A: "foo"}).Any(),
+		event.New("bar", test.BarEventData{A: "bar"}).Any(),
+	}
+
+	test.ExpectEvents(t, context.Background(), streams.New(events), events...)
+}
+
+func TestExpectEvents_mismatch(t *testing.T) {
+	foo := event.New("foo", test.FooEventData{A: "foo"}).Any()
+	bar := event.New("bar", test.BarEventData{A: "bar"}).Any()
+	baz := event.New("baz", test.BazEventData{A: "baz"}).Any()
+
+	var rec recorder
+	test.ExpectEvents(&rec, context.Background(), streams.New([]event.Event{foo, baz, bar}), foo, bar)
+
+	if len(rec.errors) != 1 {
+		t.Fatalf("ExpectEvents() should report %d error; got %d", 1, len(rec.errors))
+	}
+
+	msg := rec.errors[0]
+	t.Logf("failure message: %s", msg)
+
+	for _, want := range []string{
+		"(want 2, got 3)",
+		fmt.Sprintf("[1]\n\twant: %q event (id=%s", "bar", bar.ID()),
+		fmt.Sprintf("\tgot:  %q event (id=%s", "baz", baz.ID()),
+		fmt.Sprintf("[2]\n\twant: <none>\n\tgot:  %q event (id=%s", "bar", bar.ID()),
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("failure message should contain %q", want)
+		}
+	}
+
+	if strings.Contains(msg, "[0]") {
+		t.Errorf("failure message should not contain matching events")
+	}
+}
+
+func TestExpectEventsWith(t *testing.T) {
+	want := event.New("foo", test.FooEventData{A: "foo"}).Any()
+	got := event.New("foo", test.FooEventData{A: "foo"}).Any()
+
+	var rec recorder
+	test.ExpectEvents(&rec, context.Background(), streams.New([]event.Event{got}), want)
+
+	if len(rec.errors) != 1 {
+		t.Fatalf("ExpectEvents() should report an error for events with different ids and times")
+	}
+
+	expect := test.ExpectEventsWith(event.IgnoreID(), event.IgnoreTime())
+	expect(t, context.Background(), streams.New([]event.Event{got}), want)
+}
+
+func TestExpectEvents_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var rec recorder
+	test.ExpectEvents(&rec, ctx, make(chan event.Event))
+
+	if len(rec.errors) != 1 {
+		t.Fatalf("ExpectEvents() should report an error if ctx is canceled before the stream is closed")
+	}
+}
@@ -0,0 +1,57 @@
+package event
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	validatorsMux sync.RWMutex
+	validators    = make(map[string]func(any) error)
+)
+
+// RegisterValidator registers a validator for the data of events with the
+// given name. NewValid calls the validator of an event before it returns the
+// event. A validator that is registered under the same name as a previously
+// registered validator replaces it. Passing a nil validator removes the
+// validator of the given event name.
+//
+//	event.RegisterValidator("user.registered", func(data any) error {
+//		if data.(UserRegistered).Email == "" {
+//			return errors.New("missing email")
+//		}
+//		return nil
+//	})
+func RegisterValidator(name string, validate func(data any) error) {
+	validatorsMux.Lock()
+	defer validatorsMux.Unlock()
+
+	if validate == nil {
+		delete(validators, name)
+		return
+	}
+
+	validators[name] = validate
+}
+
+// NewValid creates an event like New does, and validates the event data using
+// the validator that is registered for the event name. If the validator
+// returns an error, NewValid returns that error. Events without a registered
+// validator are always valid.
+func NewValid[D any](name string, data D, opts ...Option) (Evt[D], error) {
+	evt := New(name, data, opts...)
+
+	validatorsMux.RLock()
+	validate, ok := validators[name]
+	validatorsMux.RUnlock()
+
+	if !ok {
+		return evt, nil
+	}
+
+	if err := validate(evt.Data()); err != nil {
+		return evt, fmt.Errorf("validate %q event: %w", name, err)
+	}
+
+	return evt, nil
+}
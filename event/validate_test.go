@@ -0,0 +1,49 @@
+package event_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/event/test"
+)
+
+func TestNewValid(t *testing.T) {
+	mockError := errors.New("mock error")
+
+	event.RegisterValidator("goes.event.validate.foo", func(data any) error {
+		if data.(test.FooEventData).A == "" {
+			return mockError
+		}
+		return nil
+	})
+	defer event.RegisterValidator("goes.event.validate.foo", nil)
+
+	if _, err := event.NewValid("goes.event.validate.foo", test.FooEventData{}); !errors.Is(err, mockError) {
+		t.Fatalf("NewValid() should fail with %q; got %v", mockError, err)
+	}
+
+	evt, err := event.NewValid("goes.event.validate.foo", test.FooEventData{A: "foo"})
+	if err != nil {
+		t.Fatalf("NewValid() failed with %q", err)
+	}
+
+	if evt.Data().A != "foo" {
+		t.Fatalf("event data should be %v; got %v", test.FooEventData{A: "foo"}, evt.Data())
+	}
+}
+
+func TestNewValid_withoutValidator(t *testing.T) {
+	if _, err := event.NewValid("goes.event.validate.bar", test.FooEventData{}); err != nil {
+		t.Fatalf("NewValid() should not fail for events without a validator; got %q", err)
+	}
+}
+
+func TestRegisterValidator_nil(t *testing.T) {
+	event.RegisterValidator("goes.event.validate.baz", func(any) error { return errors.New("invalid") })
+	event.RegisterValidator("goes.event.validate.baz", nil)
+
+	if _, err := event.NewValid("goes.event.validate.baz", test.FooEventData{}); err != nil {
+		t.Fatalf("NewValid() should not fail after the validator was removed; got %q", err)
+	}
+}
@@ -0,0 +1,83 @@
+package streams
+
+import (
+	"context"
+	"time"
+)
+
+// Batch returns a channel that receives the elements from the input channel in
+// batches of up to `size` elements. A batch is sent when it contains `size`
+// elements or when `maxWait` has elapsed since the first element of the batch
+// was received, whatever happens first. When the input channel is closed, the
+// pending partial batch is sent and the returned channel is closed. When ctx is
+// canceled, the returned channel is closed without sending the pending batch.
+//
+// If size < 1, batches are only sent after maxWait has elapsed or when the
+// input channel is closed. If maxWait <= 0, partial batches are only sent when
+// the input channel is closed.
+func Batch[T any](ctx context.Context, in <-chan T, size int, maxWait time.Duration) <-chan []T {
+	out := make(chan []T)
+
+	go func() {
+		defer close(out)
+
+		var (
+			batch []T
+			timer *time.Timer
+			timeC <-chan time.Time
+		)
+
+		stopTimer := func() {
+			if timer != nil {
+				timer.Stop()
+				timer, timeC = nil, nil
+			}
+		}
+		defer stopTimer()
+
+		flush := func() bool {
+			stopTimer()
+			if len(batch) == 0 {
+				return true
+			}
+			select {
+			case <-ctx.Done():
+				return false
+			case out <- batch:
+				batch = nil
+				return true
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timeC:
+				if !flush() {
+					return
+				}
+			case el, ok := <-in:
+				if !ok {
+					flush()
+					return
+				}
+
+				batch = append(batch, el)
+
+				if len(batch) == 1 && maxWait > 0 {
+					timer = time.NewTimer(maxWait)
+					timeC = timer.C
+				}
+
+				if size > 0 && len(batch) >= size {
+					if !flush() {
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,25 @@
+package streams_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modernice/goes/helper/streams"
+)
+
+func ExamplePipe() {
+	ctx := context.Background()
+
+	batches := streams.From(ctx, streams.New([]int{1, 2, 3, 4, 5, 6, 7, 8})).
+		Filter(func(v int) bool { return v%2 == 0 }).
+		Map(func(v int) int { return v * 10 }).
+		Batch(2, time.Minute)
+
+	for batch := range batches {
+		fmt.Println(batch)
+	}
+	// Output:
+	// [20 40]
+	// [60 80]
+}
@@ -13,16 +13,46 @@ import (
 //
 // Multiple calls to stop have no effect.
 func FanIn[T any](in ...<-chan T) (_ <-chan T, stop func()) {
+	return fanIn(in, func(_ int, v T) T { return v })
+}
+
+// Tagged is a value that was received by FanInTagged, together with the index
+// of the input channel that provided the value.
+type Tagged[T any] struct {
+	Source int
+	Value  T
+}
+
+// FanInTagged returns a single receive-only channel from multiple receive-only
+// channels, like FanIn does. Each received value is tagged with the index of
+// the input channel that provided it, which helps to find out which of the
+// input channels misbehaves:
+//
+//	out, stop := streams.FanInTagged(errs...)
+//	defer stop()
+//	for err := range out {
+//		log.Printf("input channel %d: %v", err.Source, err.Value)
+//	}
+//
+// The returned channel is closed when the returned stop function is called or
+// every input channel is closed. Multiple calls to stop have no effect.
+func FanInTagged[T any](in ...<-chan T) (_ <-chan Tagged[T], stop func()) {
+	return fanIn(in, func(source int, v T) Tagged[T] {
+		return Tagged[T]{Source: source, Value: v}
+	})
+}
+
+func fanIn[T, U any](in []<-chan T, wrap func(int, T) U) (_ <-chan U, stop func()) {
 	stopped := make(chan struct{})
 	var once sync.Once
 	stop = func() { once.Do(func() { close(stopped) }) }
 
-	out := make(chan T)
+	out := make(chan U)
 
 	var wg sync.WaitGroup
 	wg.Add(len(in))
-	for _, in := range in {
-		go func(in <-chan T) {
+	for i, in := range in {
+		go func(i int, in <-chan T) {
 			defer wg.Done()
 			for {
 				select {
@@ -35,11 +65,11 @@ func FanIn[T any](in ...<-chan T) (_ <-chan T, stop func()) {
 					select {
 					case <-stopped:
 						return
-					case out <- v:
+					case out <- wrap(i, v):
 					}
 				}
 			}
-		}(in)
+		}(i, in)
 	}
 
 	go func() {
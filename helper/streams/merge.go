@@ -0,0 +1,79 @@
+package streams
+
+import "context"
+
+// MergeSorted merges multiple sorted input channels into a single sorted
+// channel. Each input channel must provide its elements in the order defined
+// by less. MergeSorted waits until every open input channel has provided its
+// next element and then sends the smallest of these elements, so the returned
+// channel receives the elements of all input channels in sorted order. Elements
+// that are equal are sent in the order of their input channels.
+//
+// When every input channel is closed, the returned channel is closed. When ctx
+// is canceled, the returned channel is closed without receiving the remaining
+// elements.
+//
+//	// merge per-aggregate event streams that are sorted by time
+//	out := streams.MergeSorted(ctx, func(a, b event.Event) bool {
+//		return a.Time().Before(b.Time())
+//	}, fooEvents, barEvents)
+func MergeSorted[T any](ctx context.Context, less func(a, b T) bool, in ...<-chan T) <-chan T {
+	out := make(chan T)
+
+	go func() {
+		defer close(out)
+
+		heads := make([]T, len(in))
+		hasHead := make([]bool, len(in))
+		open := make([]<-chan T, len(in))
+		copy(open, in)
+
+		next := func(i int) bool {
+			select {
+			case <-ctx.Done():
+				return false
+			case el, ok := <-open[i]:
+				if !ok {
+					open[i] = nil
+					return true
+				}
+				heads[i], hasHead[i] = el, true
+				return true
+			}
+		}
+
+		for i := range open {
+			if !next(i) {
+				return
+			}
+		}
+
+		for {
+			smallest := -1
+			for i, ok := range hasHead {
+				if ok && (smallest < 0 || less(heads[i], heads[smallest])) {
+					smallest = i
+				}
+			}
+
+			if smallest < 0 {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case out <- heads[smallest]:
+			}
+
+			var zero T
+			heads[smallest], hasHead[smallest] = zero, false
+
+			if !next(smallest) {
+				return
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,72 @@
+package streams
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Pipe composes the stream operators of this package into a pipeline that
+// reads from left to right, instead of nesting the operator calls. Every stage
+// of a Pipe uses the Context that was passed to From, so the whole pipeline is
+// stopped when the Context is canceled.
+//
+//	batches := streams.From(ctx, events).
+//		Filter(func(evt event.Event) bool { return evt.Name() == "foo" }).
+//		Map(redact).
+//		Batch(100, time.Second)
+//
+// Because methods cannot have type parameters, Map cannot change the element
+// type of a Pipe. Use the standalone Map function with From to change the
+// element type within a pipeline:
+//
+//	names := streams.From(ctx, streams.Map(ctx, events, event.Event.Name)).
+//		Filter(...).
+//		Channel()
+type Pipe[T any] struct {
+	ctx context.Context
+	in  <-chan T
+}
+
+// From returns a Pipe that reads from the given channel.
+func From[T any](ctx context.Context, in <-chan T) Pipe[T] {
+	return Pipe[T]{ctx: ctx, in: in}
+}
+
+// Map adds a stage that maps the elements of the pipeline. See Map.
+func (p Pipe[T]) Map(mapper func(T) T) Pipe[T] {
+	return From(p.ctx, Map(p.ctx, p.in, mapper))
+}
+
+// Filter adds a stage that filters the elements of the pipeline. See
+// FilterContext.
+func (p Pipe[T]) Filter(filters ...func(T) bool) Pipe[T] {
+	return From(p.ctx, FilterContext(p.ctx, p.in, filters...))
+}
+
+// Before adds a stage that inserts the elements returned by fn before each
+// element of the pipeline. See BeforeContext.
+func (p Pipe[T]) Before(fn func(T) []T) Pipe[T] {
+	return From(p.ctx, BeforeContext(p.ctx, p.in, fn))
+}
+
+// Throttle adds a stage that limits the rate of the pipeline. See Throttle.
+func (p Pipe[T]) Throttle(r rate.Limit) Pipe[T] {
+	return From(p.ctx, Throttle(p.ctx, p.in, r))
+}
+
+// Batch ends the pipeline with a stage that batches its elements. See Batch.
+func (p Pipe[T]) Batch(size int, maxWait time.Duration) <-chan []T {
+	return Batch(p.ctx, p.in, size, maxWait)
+}
+
+// Channel returns the output channel of the pipeline.
+func (p Pipe[T]) Channel() <-chan T {
+	return p.in
+}
+
+// Drain drains the output channel of the pipeline. See Drain.
+func (p Pipe[T]) Drain(errs ...<-chan error) ([]T, error) {
+	return Drain(p.ctx, p.in, errs...)
+}
@@ -2,8 +2,12 @@ package streams
 
 import (
 	"context"
+	"errors"
 )
 
+// errStop is used to stop Walk early.
+var errStop = errors.New("stop")
+
 // New returns a channel that is filled with the given values. The channel is
 // closed after all elements have been pushed into the channel.
 func New[T any](in []T) <-chan T {
@@ -32,20 +36,83 @@ func Drain[T any](ctx context.Context, in <-chan T, errs ...<-chan error) ([]T,
 	return out, err
 }
 
+// DrainN drains up to n elements from the given channel and returns them.
+// DrainN returns when n elements have been received or when the input channel
+// is closed, whatever happens first. If n <= 0, DrainN behaves like Drain.
+//
+// Like Drain, DrainN accepts optional error channels which will cause DrainN
+// to fail on any error, and returns the already drained elements together with
+// the error or ctx.Err().
+func DrainN[T any](ctx context.Context, n int, in <-chan T, errs ...<-chan error) ([]T, error) {
+	if n <= 0 {
+		return Drain(ctx, in, errs...)
+	}
+
+	out := make([]T, 0, n)
+	err := Walk(ctx, func(v T) error {
+		out = append(out, v)
+		if len(out) >= n {
+			return errStop
+		}
+		return nil
+	}, in, errs...)
+	if errors.Is(err, errStop) {
+		err = nil
+	}
+
+	return out, err
+}
+
+// GroupBy drains the given channel like Drain and groups the received elements
+// by the key that is returned by the provided key function. Within a group,
+// the elements keep the order in which they were received.
+//
+// Like Drain, GroupBy accepts optional error channels which will cause GroupBy
+// to fail on any error, and returns the already grouped elements together with
+// the error or ctx.Err().
+//
+// Example:
+//
+//	var events <-chan event.Event
+//	grouped, err := GroupBy(context.TODO(), events, func(evt event.Event) uuid.UUID {
+//		return pick.AggregateID(evt)
+//	})
+//	// handle err
+//	for id, events := range grouped {
+//		log.Printf("Aggregate %s has %d events.", id, len(events))
+//	}
+func GroupBy[T any, K comparable](ctx context.Context, in <-chan T, key func(T) K, errs ...<-chan error) (map[K][]T, error) {
+	out := make(map[K][]T)
+	err := Walk(ctx, func(v T) error {
+		k := key(v)
+		out[k] = append(out[k], v)
+		return nil
+	}, in, errs...)
+	return out, err
+}
+
 // Walk receives from the given channel until it and and all provided error
 // channels are closed, ctx is closed or any of the provided error channels
 // receives an error. For every element e that is received from the input
 // channel, walkFn(e) is called. Should ctx be canceled before the channels are
 // closed, ctx.Err() is returned. Should an error be received from one of the
-// error channels, that error is returned. Otherwise Walk returns nil.
+// error channels, that error is returned. Should walkFn return an error, Walk
+// stops immediately and returns that error. Otherwise Walk returns nil.
+//
+// Walk is the streaming counterpart of Drain (which is implemented using Walk)
+// and does not collect the received elements, so it can be used to process
+// streams that do not fit into memory. Before Walk returns, it stops the
+// goroutines that receive from the error channels. Walk does not wait for the
+// input channel or the error channels to be closed after it stopped.
 //
 // Example:
 //
 //	var bus event.Bus
 //	in, errs, err := bus.Subscribe(context.TODO(), "foo", "bar", "baz")
 //	// handle err
-//	err := Walk(context.TODO(), func(e event) {
+//	err := Walk(context.TODO(), func(e event.Event) error {
 //		log.Println(fmt.Sprintf("Received %q event: %v", e.Name(), e))
+//		return nil
 //	}, in, errs)
 //	// handle err
 func Walk[T any](
@@ -124,17 +191,39 @@ func ForEach[T any](
 // functions are called for every element. If any of the filters returns false
 // for an element, that element is not pushed into the returned channel.
 func Filter[T any](in <-chan T, filters ...func(T) bool) <-chan T {
+	return FilterContext(context.Background(), in, filters...)
+}
+
+// FilterContext returns a new channel with the same type as the input channel
+// and fills it with the elements from the input channel. The returned channel
+// is closed when the input channel is closed, or when ctx is canceled. The
+// provided filter functions are called for every element. If any of the
+// filters returns false for an element, that element is not pushed into the
+// returned channel.
+func FilterContext[T any](ctx context.Context, in <-chan T, filters ...func(T) bool) <-chan T {
 	out := make(chan T)
 	go func() {
 		defer close(out)
 	L:
-		for el := range in {
-			for _, f := range filters {
-				if !f(el) {
-					continue L
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case el, ok := <-in:
+				if !ok {
+					return
+				}
+				for _, f := range filters {
+					if !f(el) {
+						continue L
+					}
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case out <- el:
 				}
 			}
-			out <- el
 		}
 	}()
 	return out
@@ -173,6 +262,52 @@ func Map[To, From any](ctx context.Context, in <-chan From, mapper func(From) To
 	return out
 }
 
+// MapErr maps the elements from the provided `in` channel using the provided
+// fallible `mapper`. Mapped values are sent to the returned value channel and
+// errors returned by `mapper` are sent to the returned error channel. An error
+// does not stop the mapping of the remaining elements. Both returned channels
+// are closed when the input channel is closed or ctx is canceled.
+//
+// Both returned channels must be received from to avoid blocking the mapping:
+//
+//	values, errs := streams.MapErr(ctx, in, decode)
+//	err := streams.Walk(ctx, func(v Value) error { ... }, values, errs)
+func MapErr[To, From any](ctx context.Context, in <-chan From, mapper func(From) (To, error)) (<-chan To, <-chan error) {
+	out := make(chan To)
+	errs := make(chan error)
+	go func() {
+		defer close(errs)
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+
+				mapped, err := mapper(v)
+				if err != nil {
+					select {
+					case <-ctx.Done():
+						return
+					case errs <- err:
+					}
+					continue
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case out <- mapped:
+				}
+			}
+		}
+	}()
+	return out, errs
+}
+
 // Before returns a new channel that is filled with the elements from the input
 // channel. Before sending an element into the returned channel, fn(el) is
 // called. The values returned by fn are first sent into the returned channel,
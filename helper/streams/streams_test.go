@@ -2,12 +2,16 @@ package streams_test
 
 import (
 	"context"
+	"errors"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/modernice/goes/event"
 	"github.com/modernice/goes/event/test"
 	"github.com/modernice/goes/helper/streams"
+	"golang.org/x/time/rate"
 )
 
 func TestBefore(t *testing.T) {
@@ -41,3 +45,499 @@ func TestBefore(t *testing.T) {
 		t.Fatalf("stream returned wrong events\n%s", cmp.Diff(want, events))
 	}
 }
+
+func TestFanInTagged(t *testing.T) {
+	in := make([]chan int, 3)
+	recv := make([]<-chan int, len(in))
+	for i := range in {
+		in[i] = make(chan int)
+		recv[i] = in[i]
+	}
+
+	out, stop := streams.FanInTagged(recv...)
+	defer stop()
+
+	for i := range in {
+		go func(i int) {
+			defer close(in[i])
+			for v := 0; v < 3; v++ {
+				in[i] <- i*10 + v
+			}
+		}(i)
+	}
+
+	var count int
+	for tagged := range out {
+		count++
+		if want := tagged.Value / 10; tagged.Source != want {
+			t.Errorf("value %d should be tagged with source %d; got %d", tagged.Value, want, tagged.Source)
+		}
+	}
+
+	if count != 9 {
+		t.Fatalf("FanInTagged() should return %d values; got %d", 9, count)
+	}
+}
+
+func TestFanInTagged_stop(t *testing.T) {
+	in := make(chan int)
+	out, stop := streams.FanInTagged[int](in)
+
+	stop()
+	stop()
+
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("channel should be closed after stop() was called")
+	case _, ok := <-out:
+		if ok {
+			t.Fatalf("channel should be closed after stop() was called")
+		}
+	}
+}
+
+func TestMergeSorted(t *testing.T) {
+	a := streams.New([]int{1, 4, 7, 10})
+	b := streams.New([]int{2, 5, 8})
+	c := streams.New([]int{0, 3, 6, 9, 11, 12})
+
+	out := streams.MergeSorted(context.Background(), func(a, b int) bool { return a < b }, a, b, c)
+
+	result, err := streams.Drain(context.Background(), out)
+	if err != nil {
+		t.Fatalf("Drain() failed with %q", err)
+	}
+
+	want := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+	if !cmp.Equal(want, result) {
+		t.Fatalf("MergeSorted() should return %v; got %v", want, result)
+	}
+}
+
+func TestMergeSorted_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	out := streams.MergeSorted(ctx, func(a, b int) bool { return a < b }, make(chan int))
+
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("channel should be closed after ctx was canceled")
+	case _, ok := <-out:
+		if ok {
+			t.Fatalf("channel should be closed after ctx was canceled")
+		}
+	}
+}
+
+func TestMapErr(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := streams.New([]string{"1", "foo", "2", "bar", "3"})
+
+	values, errs := streams.MapErr(ctx, in, strconv.Atoi)
+
+	var result []int
+	var errCount int
+	streams.ForEach(ctx, func(v int) {
+		result = append(result, v)
+	}, func(err error) {
+		var numError *strconv.NumError
+		if !errors.As(err, &numError) {
+			t.Fatalf("error should be a %T; got %T", numError, err)
+		}
+		errCount++
+	}, values, errs)
+
+	want := []int{1, 2, 3}
+	if !cmp.Equal(want, result) {
+		t.Fatalf("MapErr() returned wrong values\n%s", cmp.Diff(want, result))
+	}
+
+	if errCount != 2 {
+		t.Fatalf("MapErr() should return %d errors; got %d", 2, errCount)
+	}
+}
+
+func TestMapErr_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	in := make(chan int)
+	values, errs := streams.MapErr(ctx, in, func(v int) (int, error) { return v, nil })
+
+	cancel()
+
+	if _, err := streams.Drain(context.Background(), values, errs); err != nil {
+		t.Fatalf("Drain() failed with %q", err)
+	}
+}
+
+func TestFilterContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := streams.New([]int{1, 2, 3, 4, 5, 6})
+
+	filtered := streams.FilterContext(ctx, in, func(v int) bool { return v%2 == 0 })
+
+	result, err := streams.Drain(ctx, filtered)
+	if err != nil {
+		t.Fatalf("Drain() failed with %q", err)
+	}
+
+	want := []int{2, 4, 6}
+	if !cmp.Equal(want, result) {
+		t.Fatalf("FilterContext() returned wrong values\n%s", cmp.Diff(want, result))
+	}
+}
+
+func TestFilterContext_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	filtered := streams.FilterContext(ctx, make(chan int), func(int) bool { return true })
+
+	cancel()
+
+	if _, err := streams.Drain(context.Background(), filtered); err != nil {
+		t.Fatalf("Drain() failed with %q", err)
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	in := streams.New([]string{"foo", "bar", "baz", "foobar", "barbaz", "f"})
+
+	grouped, err := streams.GroupBy(context.Background(), in, func(v string) string { return v[:1] })
+	if err != nil {
+		t.Fatalf("GroupBy() failed with %q", err)
+	}
+
+	want := map[string][]string{
+		"f": {"foo", "foobar", "f"},
+		"b": {"bar", "baz", "barbaz"},
+	}
+	if !cmp.Equal(want, grouped) {
+		t.Fatalf("GroupBy() returned wrong groups\n%s", cmp.Diff(want, grouped))
+	}
+}
+
+func TestGroupBy_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := streams.GroupBy(ctx, make(chan string), func(v string) string { return v })
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("GroupBy() should fail with %q; got %q", context.Canceled, err)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	in := streams.New([]int{1, 2, 3})
+
+	var walked []int
+	if err := streams.Walk(context.Background(), func(v int) error {
+		walked = append(walked, v)
+		return nil
+	}, in); err != nil {
+		t.Fatalf("Walk() failed with %q", err)
+	}
+
+	want := []int{1, 2, 3}
+	if !cmp.Equal(want, walked) {
+		t.Fatalf("Walk() walked wrong values\n%s", cmp.Diff(want, walked))
+	}
+}
+
+func TestWalk_walkFnError(t *testing.T) {
+	mockError := errors.New("mock error")
+
+	// the input channel is never closed
+	in := make(chan int, 3)
+	in <- 1
+	in <- 2
+	in <- 3
+
+	var walked []int
+	err := streams.Walk(context.Background(), func(v int) error {
+		walked = append(walked, v)
+		if v == 2 {
+			return mockError
+		}
+		return nil
+	}, in, make(chan error))
+
+	if !errors.Is(err, mockError) {
+		t.Fatalf("Walk() should fail with %q; got %q", mockError, err)
+	}
+
+	want := []int{1, 2}
+	if !cmp.Equal(want, walked) {
+		t.Fatalf("Walk() should stop after the first error\n%s", cmp.Diff(want, walked))
+	}
+}
+
+func TestWalk_streamError(t *testing.T) {
+	mockError := errors.New("mock error")
+
+	errs := make(chan error, 1)
+	errs <- mockError
+
+	err := streams.Walk(context.Background(), func(int) error { return nil }, make(chan int), errs)
+	if !errors.Is(err, mockError) {
+		t.Fatalf("Walk() should fail with %q; got %q", mockError, err)
+	}
+}
+
+func TestWalk_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := streams.Walk(ctx, func(int) error { return nil }, make(chan int), make(chan error))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Walk() should fail with %q; got %q", context.Canceled, err)
+	}
+}
+
+func TestTee(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	in := streams.New([]int{1, 2, 3, 4, 5})
+	outs := streams.Tee(ctx, in, 3)
+
+	if len(outs) != 3 {
+		t.Fatalf("Tee() should return %d channels; got %d", 3, len(outs))
+	}
+
+	results := make([]chan []int, len(outs))
+	for i, out := range outs {
+		results[i] = make(chan []int, 1)
+		go func(out <-chan int, result chan<- []int) {
+			values, err := streams.Drain(ctx, out)
+			if err != nil {
+				t.Errorf("Drain() failed with %q", err)
+			}
+			result <- values
+		}(out, results[i])
+	}
+
+	want := []int{1, 2, 3, 4, 5}
+	for i, result := range results {
+		if got := <-result; !cmp.Equal(want, got) {
+			t.Errorf("output #%d received wrong values\n%s", i, cmp.Diff(want, got))
+		}
+	}
+}
+
+func TestTeeBuffered(t *testing.T) {
+	in := streams.New([]int{1, 2, 3})
+	outs := streams.TeeBuffered(context.Background(), in, 2, 3)
+
+	// the first output can be drained completely without reading from the
+	// second output
+	values, err := streams.Drain(context.Background(), outs[0])
+	if err != nil {
+		t.Fatalf("Drain() failed with %q", err)
+	}
+
+	want := []int{1, 2, 3}
+	if !cmp.Equal(want, values) {
+		t.Fatalf("TeeBuffered() returned wrong values\n%s", cmp.Diff(want, values))
+	}
+
+	values, err = streams.Drain(context.Background(), outs[1])
+	if err != nil {
+		t.Fatalf("Drain() failed with %q", err)
+	}
+
+	if !cmp.Equal(want, values) {
+		t.Fatalf("TeeBuffered() returned wrong values\n%s", cmp.Diff(want, values))
+	}
+}
+
+func TestTee_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	outs := streams.Tee(ctx, make(chan int), 2)
+
+	cancel()
+
+	for _, out := range outs {
+		if _, err := streams.Drain(context.Background(), out); err != nil {
+			t.Fatalf("Drain() failed with %q", err)
+		}
+	}
+}
+
+func TestBatch_size(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := streams.New([]int{1, 2, 3, 4, 5, 6, 7})
+
+	batches, err := streams.Drain(ctx, streams.Batch(ctx, in, 3, time.Minute))
+	if err != nil {
+		t.Fatalf("Drain() failed with %q", err)
+	}
+
+	want := [][]int{{1, 2, 3}, {4, 5, 6}, {7}}
+	if !cmp.Equal(want, batches) {
+		t.Fatalf("Batch() returned wrong batches\n%s", cmp.Diff(want, batches))
+	}
+}
+
+func TestBatch_maxWait(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan int)
+	batches := streams.Batch(ctx, in, 10, 50*time.Millisecond)
+
+	in <- 1
+	in <- 2
+
+	start := time.Now()
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("Batch() should send a partial batch after maxWait")
+	case batch := <-batches:
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Fatalf("partial batch should be sent after ~%s; took %s", 50*time.Millisecond, elapsed)
+		}
+		if want := []int{1, 2}; !cmp.Equal(want, batch) {
+			t.Fatalf("Batch() returned wrong batch\n%s", cmp.Diff(want, batch))
+		}
+	}
+
+	in <- 3
+	close(in)
+
+	rest, err := streams.Drain(ctx, batches)
+	if err != nil {
+		t.Fatalf("Drain() failed with %q", err)
+	}
+
+	if want := [][]int{{3}}; !cmp.Equal(want, rest) {
+		t.Fatalf("Batch() returned wrong batches\n%s", cmp.Diff(want, rest))
+	}
+}
+
+func TestBatch_flushOnClose(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan int, 2)
+	in <- 1
+	in <- 2
+	close(in)
+
+	batches, err := streams.Drain(ctx, streams.Batch(ctx, in, 10, time.Minute))
+	if err != nil {
+		t.Fatalf("Drain() failed with %q", err)
+	}
+
+	want := [][]int{{1, 2}}
+	if !cmp.Equal(want, batches) {
+		t.Fatalf("Batch() returned wrong batches\n%s", cmp.Diff(want, batches))
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	in := make(chan int)
+	go func() {
+		defer close(in)
+		for i := 0; i < 5; i++ {
+			in <- i
+		}
+	}()
+
+	start := time.Now()
+	out := streams.Throttle(context.Background(), in, rate.Limit(50))
+
+	var got []int
+	for v := range out {
+		got = append(got, v)
+	}
+
+	if want := []int{0, 1, 2, 3, 4}; !cmp.Equal(want, got) {
+		t.Fatalf("Throttle() should return %v; got %v", want, got)
+	}
+
+	// The first element is released immediately, the remaining 4 elements at
+	// 50 elements per second.
+	if want := 70 * time.Millisecond; time.Since(start) < want {
+		t.Fatalf("Throttle() should take at least %v; took %v", want, time.Since(start))
+	}
+}
+
+func TestThrottle_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	in := make(chan int, 2)
+	in <- 1
+	in <- 2
+
+	out := streams.Throttle(ctx, in, rate.Every(time.Hour))
+
+	if v := <-out; v != 1 {
+		t.Fatalf("first element should be %d; got %d", 1, v)
+	}
+
+	cancel()
+
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("channel should be closed after ctx was canceled")
+	case _, ok := <-out:
+		if ok {
+			t.Fatalf("channel should be closed after ctx was canceled")
+		}
+	}
+}
+
+func TestDrainN(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan int)
+	go func() {
+		for i := 1; ; i++ {
+			select {
+			case <-ctx.Done():
+				return
+			case in <- i:
+			}
+		}
+	}()
+
+	result, err := streams.DrainN(ctx, 3, in)
+	if err != nil {
+		t.Fatalf("DrainN() failed with %q", err)
+	}
+
+	if want := []int{1, 2, 3}; !cmp.Equal(want, result) {
+		t.Fatalf("DrainN() returned wrong values\n%s", cmp.Diff(want, result))
+	}
+}
+
+func TestDrainN_closed(t *testing.T) {
+	result, err := streams.DrainN(context.Background(), 5, streams.New([]int{1, 2}))
+	if err != nil {
+		t.Fatalf("DrainN() failed with %q", err)
+	}
+
+	if want := []int{1, 2}; !cmp.Equal(want, result) {
+		t.Fatalf("DrainN() returned wrong values\n%s", cmp.Diff(want, result))
+	}
+}
+
+func TestDrainN_unbounded(t *testing.T) {
+	result, err := streams.DrainN(context.Background(), 0, streams.New([]int{1, 2, 3}))
+	if err != nil {
+		t.Fatalf("DrainN() failed with %q", err)
+	}
+
+	if want := []int{1, 2, 3}; !cmp.Equal(want, result) {
+		t.Fatalf("DrainN() returned wrong values\n%s", cmp.Diff(want, result))
+	}
+}
@@ -0,0 +1,61 @@
+package streams
+
+import "context"
+
+// Tee returns n channels that each receive every element of the input
+// channel. Tee is the counterpart of FanIn and can be used to let multiple
+// independent consumers read the same stream.
+//
+// Every element is sent to the outputs one after the other, so Tee blocks on
+// the slowest consumer; use TeeBuffered to give consumers some leeway. Every
+// output channel must be drained until it is closed or ctx is canceled,
+// otherwise the other outputs stop receiving elements. The returned channels
+// are closed when the input channel is closed or ctx is canceled.
+//
+// If n <= 0, Tee returns nil.
+func Tee[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+	return TeeBuffered(ctx, in, n, 0)
+}
+
+// TeeBuffered does the same as Tee, but the returned channels have a buffer of
+// the given size.
+func TeeBuffered[T any](ctx context.Context, in <-chan T, n, size int) []<-chan T {
+	if n <= 0 {
+		return nil
+	}
+
+	outs := make([]chan T, n)
+	result := make([]<-chan T, n)
+	for i := range outs {
+		outs[i] = make(chan T, size)
+		result[i] = outs[i]
+	}
+
+	go func() {
+		defer func() {
+			for _, out := range outs {
+				close(out)
+			}
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				for _, out := range outs {
+					select {
+					case <-ctx.Done():
+						return
+					case out <- v:
+					}
+				}
+			}
+		}
+	}()
+
+	return result
+}
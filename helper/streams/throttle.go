@@ -0,0 +1,47 @@
+package streams
+
+import (
+	"context"
+
+	"golang.org/x/time/rate"
+)
+
+// Throttle returns a channel that receives the elements from the input channel
+// at a rate of at most r elements per second. Because the returned channel is
+// unbuffered, the producer of the input channel is blocked while the stream is
+// throttled. When the input channel is closed, the returned channel is closed.
+// When ctx is canceled, the returned channel is closed without receiving the
+// remaining elements. A rate of rate.Inf disables throttling.
+//
+//	var events <-chan event.Event
+//	throttled := streams.Throttle(ctx, events, 50) // at most 50 events per second
+func Throttle[T any](ctx context.Context, in <-chan T, r rate.Limit) <-chan T {
+	out := make(chan T)
+	limiter := rate.NewLimiter(r, 1)
+
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case el, ok := <-in:
+				if !ok {
+					return
+				}
+
+				if err := limiter.Wait(ctx); err != nil {
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case out <- el:
+				}
+			}
+		}
+	}()
+
+	return out
+}
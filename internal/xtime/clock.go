@@ -0,0 +1,77 @@
+package xtime
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	clockMux sync.RWMutex
+	clock    Clock = realClock{}
+)
+
+// Clock provides the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return realNow()
+}
+
+// SetClock replaces the package clock and returns a function that restores
+// the previous clock. Use the helpers of the goes/test package to replace the
+// clock for the duration of a test.
+func SetClock(c Clock) (restore func()) {
+	clockMux.Lock()
+	defer clockMux.Unlock()
+	prev := clock
+	clock = c
+	return func() {
+		clockMux.Lock()
+		defer clockMux.Unlock()
+		clock = prev
+	}
+}
+
+// FrozenClock is a Clock that always returns the same Time until it is moved
+// using Set or Add.
+type FrozenClock struct {
+	mux sync.RWMutex
+	now time.Time
+}
+
+// Frozen returns a FrozenClock that is frozen at the given Time.
+func Frozen(at time.Time) *FrozenClock {
+	return &FrozenClock{now: at}
+}
+
+// Now returns the Time the clock is frozen at.
+func (c *FrozenClock) Now() time.Time {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	return c.now
+}
+
+// Set freezes the clock at the given Time.
+func (c *FrozenClock) Set(at time.Time) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.now = at
+}
+
+// Add moves the clock by the given Duration and returns the new Time.
+func (c *FrozenClock) Add(d time.Duration) time.Time {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.now = c.now.Add(d)
+	return c.now
+}
+
+func currentClock() Clock {
+	clockMux.RLock()
+	defer clockMux.RUnlock()
+	return clock
+}
@@ -0,0 +1,39 @@
+package xtime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/modernice/goes/internal/xtime"
+)
+
+func TestSetClock(t *testing.T) {
+	at := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	restore := xtime.SetClock(xtime.Frozen(at))
+
+	if now := xtime.Now(); !now.Equal(at) {
+		t.Fatalf("Now() should return %v; got %v", at, now)
+	}
+
+	restore()
+
+	if now := xtime.Now(); now.Equal(at) {
+		t.Fatalf("the real clock should be restored; got %v", now)
+	}
+}
+
+func TestFrozenClock(t *testing.T) {
+	at := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := xtime.Frozen(at)
+
+	want := at.Add(time.Hour)
+	if got := clock.Add(time.Hour); !got.Equal(want) {
+		t.Fatalf("Add() should return %v; got %v", want, got)
+	}
+
+	clock.Set(at)
+	if now := clock.Now(); !now.Equal(at) {
+		t.Fatalf("Now() should return %v after Set(); got %v", at, now)
+	}
+}
@@ -1,3 +1,8 @@
+// Package xtime provides the clock that is used by goes to timestamp events,
+// snapshots and other time-based values. In production, the clock is always the
+// real clock, which returns the current time with nanosecond precision. Tests
+// can replace the clock using test.SetClock or test.WithFrozenClock from the
+// goes/test package to get deterministic times.
 package xtime
 
 import (
@@ -21,13 +26,20 @@ func init() {
 	}
 }
 
-// Now returns the current Time with nanosecond precision. On machines that
-// natively provide nanosecond precision Now just returns time.Now(). Otherwise
-// Now takes a bit slower path using the monotonic time to calculate the
-// nanoseconds manually.
+// Now returns the current Time of the package clock. Unless the clock has been
+// replaced using SetClock, Now returns the current Time with
+// nanosecond precision.
+func Now() time.Time {
+	return currentClock().Now()
+}
+
+// realNow returns the current Time with nanosecond precision. On machines that
+// natively provide nanosecond precision realNow just returns time.Now().
+// Otherwise realNow takes a bit slower path using the monotonic time to
+// calculate the nanoseconds manually.
 //
 // TODO: Remove as soon as go natively supports nanosecond precision on all machines.
-func Now() time.Time {
+func realNow() time.Time {
 	if supportsNanoseconds {
 		return time.Now()
 	}
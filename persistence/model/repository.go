@@ -6,8 +6,14 @@ import (
 	"fmt"
 )
 
-// ErrNotFound is returned by repositories when a model cannot be found.
-var ErrNotFound = errors.New("model not found")
+var (
+	// ErrNotFound is returned by repositories when a model cannot be found.
+	ErrNotFound = errors.New("model not found")
+
+	// ErrConcurrency is returned by repositories when a model cannot be saved
+	// because it was modified concurrently.
+	ErrConcurrency = errors.New("model was modified concurrently")
+)
 
 type ID interface {
 	comparable
@@ -73,6 +73,48 @@ func (p *Progressor) SetProgress(t time.Time, ids ...uuid.UUID) {
 	}
 }
 
+// LatestEventTime returns the time of the latest applied event, or the zero
+// Time if no event has been applied yet.
+func (p *Progressor) LatestEventTime() time.Time {
+	t, _ := p.Progress()
+	return t
+}
+
+// TrackEvent updates the progress with the given event if the event is not
+// older than the current progress. Projections that apply events manually,
+// without using Apply or ApplyStream, can call TrackEvent from their event
+// handlers to keep track of their progress:
+//
+//	type MyProjection struct {
+//		*projection.Base
+//		*projection.Progressor
+//	}
+//
+//	func (p *MyProjection) onFoo(evt event.Of[FooData]) {
+//		// apply the event ...
+//		p.TrackEvent(evt.Any())
+//	}
+//
+// Jobs only query the events of a ProgressAware projection that happened
+// since the tracked time.
+func (p *Progressor) TrackEvent(evt event.Event) {
+	latest, ids := p.Progress()
+
+	if latest.Equal(evt.Time()) {
+		for _, id := range ids {
+			if id == evt.ID() {
+				return
+			}
+		}
+		p.SetProgress(latest, append(ids, evt.ID())...)
+		return
+	}
+
+	if latest.IsZero() || evt.Time().After(latest) {
+		p.SetProgress(evt.Time(), evt.ID())
+	}
+}
+
 // A Resetter is a projection that can reset its state. projections that
 // implement Resetter can be reset by projection jobs before applying events
 // to the projection. projection jobs reset a projection if the WithReset()
@@ -82,6 +124,20 @@ type Resetter interface {
 	Reset()
 }
 
+// ResetProjection resets a projection to an empty state. The progress of the projection
+// is reset first if it implements ProgressAware. Then, if the projection
+// implements Resetter, its Reset method is called to allow for custom reset
+// logic. Projections that implement neither interface are left untouched.
+func ResetProjection(target any) {
+	if progressor, isProgressor := target.(ProgressAware); isProgressor {
+		progressor.SetProgress(time.Time{})
+	}
+
+	if resetter, isResetter := target.(Resetter); isResetter {
+		resetter.Reset()
+	}
+}
+
 // Guard can be implemented by projections to "guard" the projection from
 // illegal events. If a projection implements Guard, GuardProjection(evt)
 // is called for every event that should be applied to the projection to
@@ -13,6 +13,9 @@ type ApplyOption func(*applyConfig)
 
 type applyConfig struct {
 	ignoreProgress bool
+	noCache        bool
+	reset          bool
+	onResult       []func(Result)
 }
 
 // IgnoreProgress returns an ApplyOption that makes Apply ignore the current
@@ -24,6 +27,35 @@ func IgnoreProgress() ApplyOption {
 	}
 }
 
+// NoCache returns an ApplyOption that makes Job.Apply fetch the events from
+// the event store instead of using the cached result of a previous query. The
+// cache is updated with the fetched events. NoCache has no effect when passed
+// to Apply or ApplyStream.
+func NoCache() ApplyOption {
+	return func(cfg *applyConfig) {
+		cfg.noCache = true
+	}
+}
+
+// FromScratch returns an ApplyOption that makes Replay and Job.Apply reset the
+// projection using ResetProjection before applying events, so that the projection is
+// rebuilt from scratch. FromScratch has no effect when passed to Apply or
+// ApplyStream.
+func FromScratch() ApplyOption {
+	return func(cfg *applyConfig) {
+		cfg.reset = true
+	}
+}
+
+// OnResult returns an ApplyOption that calls fn with the Result of applying
+// events to a projection. OnResult allows to get the Result of Job.Apply, which
+// only returns an error.
+func OnResult(fn func(Result)) ApplyOption {
+	return func(cfg *applyConfig) {
+		cfg.onResult = append(cfg.onResult, fn)
+	}
+}
+
 // Apply applies events to the given projection.
 //
 // If the projection implements Guard, proj.GuardProjection(evt) is called for
@@ -43,13 +75,51 @@ func Apply(proj Target[any], events []event.Event, opts ...ApplyOption) {
 // If the projection implements ProgressAware, the time of the last applied
 // event is applied to the projection by calling proj.SetProgress(evt).
 func ApplyStream(target Target[any], events <-chan event.Event, opts ...ApplyOption) {
+	ApplyStreamResult(target, events, opts...)
+}
+
+// Result is the result of applying events to a projection.
+type Result struct {
+	// Applied is the number of events that were applied to the projection.
+	// Events that were rejected by a Guard or by the progress of the
+	// projection are not counted.
+	Applied int
+
+	// LastEventTime is the latest time of the applied events. Because the
+	// events may not be sorted by time, it is not necessarily the time of the
+	// last applied event. LastEventTime is zero if no event was applied.
+	LastEventTime time.Time
+}
+
+// ApplyResult applies events to the given projection like Apply does, and
+// returns the number of applied events and the latest time of those events.
+// Use the Result to update a high-water mark without scanning the events
+// again.
+func ApplyResult(proj Target[any], events []event.Event, opts ...ApplyOption) Result {
+	return ApplyStreamResult(proj, streams.New(events), opts...)
+}
+
+// ApplyStreamResult applies events to the given projection like ApplyStream
+// does, and returns the number of applied events and the latest time of those
+// events.
+func ApplyStreamResult(target Target[any], events <-chan event.Event, opts ...ApplyOption) Result {
 	cfg := newApplyConfig(opts...)
 
 	progressor, isProgressor := target.(ProgressAware)
 	guard, hasGuard := target.(Guard)
 
+	var result Result
 	var lastEventTime time.Time
 	var lastEvents []uuid.UUID
+
+	// Keep the ids of the previously applied events that have the same time
+	// as events that are applied now.
+	if isProgressor && !cfg.ignoreProgress {
+		var ids []uuid.UUID
+		lastEventTime, ids = progressor.Progress()
+		lastEvents = append(lastEvents, ids...)
+	}
+
 	for evt := range events {
 		if hasGuard && !guard.GuardProjection(evt) {
 			continue
@@ -61,6 +131,11 @@ func ApplyStream(target Target[any], events <-chan event.Event, opts ...ApplyOpt
 
 		target.ApplyEvent(evt)
 
+		result.Applied++
+		if evt.Time().After(result.LastEventTime) {
+			result.LastEventTime = evt.Time()
+		}
+
 		// Avoid unnecessary computations.
 		if !isProgressor {
 			continue
@@ -79,6 +154,12 @@ func ApplyStream(target Target[any], events <-chan event.Event, opts ...ApplyOpt
 	if isProgressor && !lastEventTime.IsZero() {
 		progressor.SetProgress(lastEventTime, lastEvents...)
 	}
+
+	for _, fn := range cfg.onResult {
+		fn(result)
+	}
+
+	return result
 }
 
 func newApplyConfig(opts ...ApplyOption) applyConfig {
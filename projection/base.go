@@ -1,10 +1,31 @@
 package projection
 
-import "github.com/modernice/goes/event"
+import (
+	"sort"
+
+	"github.com/modernice/goes/event"
+)
+
+// eventRegistry is implemented by projections that know which events they
+// handle. *Base implements eventRegistry.
+type eventRegistry interface {
+	RegisteredEvents() []string
+}
+
+// registeredEvents returns the names of the events that the given projection
+// handles, or nil if the projection does not provide them.
+func registeredEvents(target any) []string {
+	if registry, ok := target.(eventRegistry); ok {
+		return registry.RegisteredEvents()
+	}
+	return nil
+}
 
 // Base can be embedded into projections to implement event.Handler.
 type Base struct {
-	appliers map[string]func(event.Event)
+	appliers       map[string]func(event.Event)
+	defaultHandler func(event.Event)
+	guards         []func(event.Event) bool
 }
 
 // New returns a new base for a projection. Use the RegisterHandler function to add
@@ -19,9 +40,60 @@ func (a *Base) RegisterEventHandler(eventName string, handler func(event.Event))
 	a.appliers[eventName] = handler
 }
 
+// RegisterEventHandlerMany registers the handler for all of the given event
+// names. Existing handlers for the given event names are replaced.
+func (a *Base) RegisterEventHandlerMany(handler func(event.Event), names ...string) {
+	for _, name := range names {
+		a.RegisterEventHandler(name, handler)
+	}
+}
+
+// RegisterDefaultHandler registers a handler that is called for events that
+// have no registered handler. Without a default handler, such events are
+// ignored.
+func (a *Base) RegisterDefaultHandler(handler func(event.Event)) {
+	a.defaultHandler = handler
+}
+
+// RegisterGuard registers a guard that is called before an event is applied.
+// If any of the registered guards returns false for an event, the event is not
+// applied at all.
+func (a *Base) RegisterGuard(guard func(event.Event) bool) {
+	a.guards = append(a.guards, guard)
+}
+
+// RegisteredEvents returns the sorted names of the events that have a
+// registered handler. If a default handler is registered, the projection
+// handles all events and RegisteredEvents returns nil. Jobs and Replay use the
+// registered events to query only the events that a projection can handle.
+func (a *Base) RegisteredEvents() []string {
+	if a.defaultHandler != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(a.appliers))
+	for name := range a.appliers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
 // ApplyEvent implements eventApplier.
 func (a *Base) ApplyEvent(evt event.Event) {
+	for _, guard := range a.guards {
+		if !guard(evt) {
+			return
+		}
+	}
+
 	if handler, ok := a.appliers[evt.Name()]; ok {
 		handler(evt)
+		return
+	}
+
+	if a.defaultHandler != nil {
+		a.defaultHandler(evt)
 	}
 }
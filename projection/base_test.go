@@ -0,0 +1,90 @@
+package projection_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/event/test"
+	"github.com/modernice/goes/projection"
+)
+
+func TestBase_RegisterEventHandlerMany(t *testing.T) {
+	base := projection.New()
+
+	var applied []string
+	base.RegisterEventHandler("foo", func(event.Event) { t.Fatalf("replaced handler should not be called") })
+	base.RegisterEventHandlerMany(func(evt event.Event) {
+		applied = append(applied, evt.Name())
+	}, "foo", "bar", "baz")
+
+	base.ApplyEvent(event.New("foo", test.FooEventData{}).Any())
+	base.ApplyEvent(event.New("bar", test.BarEventData{}).Any())
+	base.ApplyEvent(event.New("baz", test.BazEventData{}).Any())
+	base.ApplyEvent(event.New("foobar", test.FoobarEventData{}).Any())
+
+	want := []string{"foo", "bar", "baz"}
+	if !cmp.Equal(want, applied) {
+		t.Fatalf("handler should have been called for %v; was called for %v", want, applied)
+	}
+}
+
+func TestBase_RegisterDefaultHandler(t *testing.T) {
+	base := projection.New()
+
+	var applied, defaulted []string
+	base.RegisterEventHandler("foo", func(evt event.Event) { applied = append(applied, evt.Name()) })
+	base.RegisterDefaultHandler(func(evt event.Event) { defaulted = append(defaulted, evt.Name()) })
+
+	base.ApplyEvent(event.New("foo", test.FooEventData{}).Any())
+	base.ApplyEvent(event.New("bar", test.BarEventData{}).Any())
+	base.ApplyEvent(event.New("baz", test.BazEventData{}).Any())
+
+	if want := []string{"foo"}; !cmp.Equal(want, applied) {
+		t.Fatalf("handler should have been called for %v; was called for %v", want, applied)
+	}
+
+	if want := []string{"bar", "baz"}; !cmp.Equal(want, defaulted) {
+		t.Fatalf("default handler should have been called for %v; was called for %v", want, defaulted)
+	}
+}
+
+func TestBase_RegisterGuard(t *testing.T) {
+	base := projection.New()
+
+	var applied []string
+	base.RegisterEventHandlerMany(func(evt event.Event) {
+		applied = append(applied, evt.Name())
+	}, "foo", "bar", "baz")
+	base.RegisterDefaultHandler(func(evt event.Event) {
+		t.Fatalf("default handler should not be called for guarded events")
+	})
+
+	base.RegisterGuard(func(evt event.Event) bool { return evt.Name() != "bar" })
+	base.RegisterGuard(func(evt event.Event) bool { return evt.Name() != "foobar" })
+
+	base.ApplyEvent(event.New("foo", test.FooEventData{}).Any())
+	base.ApplyEvent(event.New("bar", test.BarEventData{}).Any())
+	base.ApplyEvent(event.New("baz", test.BazEventData{}).Any())
+	base.ApplyEvent(event.New("foobar", test.FoobarEventData{}).Any())
+
+	if want := []string{"foo", "baz"}; !cmp.Equal(want, applied) {
+		t.Fatalf("handler should have been called for %v; was called for %v", want, applied)
+	}
+}
+
+func TestBase_RegisteredEvents(t *testing.T) {
+	base := projection.New()
+	base.RegisterEventHandlerMany(func(event.Event) {}, "foo", "baz", "bar")
+
+	want := []string{"bar", "baz", "foo"}
+	if names := base.RegisteredEvents(); !cmp.Equal(want, names) {
+		t.Fatalf("RegisteredEvents() should return %v; got %v", want, names)
+	}
+
+	base.RegisterDefaultHandler(func(event.Event) {})
+
+	if names := base.RegisteredEvents(); names != nil {
+		t.Fatalf("RegisteredEvents() should return nil if a default handler is registered; got %v", names)
+	}
+}
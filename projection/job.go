@@ -3,8 +3,12 @@ package projection
 import (
 	"context"
 	"crypto/sha256"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"hash"
+	"log"
+	"sort"
 	"sync"
 	stdtime "time"
 
@@ -58,10 +62,27 @@ type Job interface {
 	//	events, err := streams.Drain(job, str, errs)
 	EventsFor(context.Context, Target[any]) (<-chan event.Event, <-chan error, error)
 
+	// Query returns the event query that is used to fetch the events for the
+	// given projection, including the time constraint for projections that
+	// implement ProgressAware. If the IgnoreProgress option is provided, the
+	// time constraint is omitted. If the query of the job does not filter
+	// events by name and the projection provides a RegisteredEvents() method
+	// (like *Base does), the returned query only includes the registered
+	// events. Query does not fetch any events.
+	//
+	//	var job Job
+	//	var proj projection.Projection
+	//	q := job.Query(proj)
+	//	log.Printf("Names: %v, Times: %v", q.Names(), q.Times())
+	Query(_ Target[any], opts ...ApplyOption) event.Query
+
 	// Aggregates extracts the aggregates of the job's events as aggregate
 	// references. If aggregate names are provided, only references that have
 	// one of the given names are returned. References are deduplicated, so each
-	// of the returned references is unique.
+	// of the returned references is unique. References are returned in the
+	// order in which their first event is returned by the event query, so the
+	// order is only deterministic if the query sorts the events. Use
+	// AggregatesSorted for a deterministic order.
 	//
 	//	var job Job
 	//	str, errs, err := job.Aggregates(job, "foo", "bar", "baz")
@@ -69,6 +90,19 @@ type Job interface {
 	//	events, err := streams.Drain(job, str, errs)
 	Aggregates(_ context.Context, aggregateNames ...string) (<-chan aggregate.Ref, <-chan error, error)
 
+	// AggregatesSorted returns the aggregates that are extracted by Aggregates,
+	// grouped by aggregate name. The groups are sorted by name and the ids
+	// within each group are sorted by their string representation, so the
+	// result does not depend on the order of the job's events.
+	//
+	//	var job Job
+	//	groups, err := job.AggregatesSorted(job, "foo", "bar")
+	//	// handle err
+	//	for _, g := range groups {
+	//		log.Printf("%s: %v", g.Name, g.IDs)
+	//	}
+	AggregatesSorted(_ context.Context, aggregateNames ...string) ([]AggregateIDs, error)
+
 	// Aggregate returns the id of the first aggregate with the given name that
 	// can be extracted from the events of the job. If no event that belongs to
 	// this kind of aggregate can be found, an error that satisfies
@@ -79,6 +113,21 @@ type Job interface {
 	// would be returned by EventsFor(). A job may be applied concurrently to
 	// multiple projections.
 	Apply(context.Context, Target[any], ...ApplyOption) error
+
+	// InvalidateCache clears the query cache of the job. A job caches the
+	// result of each query for its lifetime, so subsequent calls to Events(),
+	// EventsFor() etc. do not query the event store again. Cached results are
+	// copied before they are returned, so the cache cannot be modified through
+	// returned events. Use the NoCache ApplyOption to bypass the cache for a
+	// single call to Apply().
+	InvalidateCache()
+}
+
+// AggregateIDs are the ids of the aggregates with the given name, as returned
+// by Job.AggregatesSorted.
+type AggregateIDs struct {
+	Name string
+	IDs  []uuid.UUID
 }
 
 // JobOption is a Job option.
@@ -96,6 +145,7 @@ type job struct {
 	filter      []event.Query
 	reset       bool
 	cache       *queryCache
+	logger      *log.Logger
 }
 
 // WithFilter returns a JobOption that adds queries as filters to the Job.
@@ -137,6 +187,24 @@ func WithBeforeEvent(fns ...func(context.Context, event.Event) ([]event.Event, e
 	}
 }
 
+// withoutCache returns a JobOption that disables the query cache of the Job,
+// so that the events of large queries are streamed without being kept in
+// memory.
+func withoutCache() JobOption {
+	return func(j *job) {
+		j.cache.disabled = true
+	}
+}
+
+// WithLogger returns a JobOption that makes the Job log debug information,
+// like the time it took to fetch the events of a query, to the given logger.
+// By default, a Job does not log anything.
+func WithLogger(l *log.Logger) JobOption {
+	return func(j *job) {
+		j.logger = l
+	}
+}
+
 // NewJob returns a new projection Job. The Job uses the provided Query to fetch
 // the events from the Store.
 func NewJob(ctx context.Context, store event.Store, q event.Query, opts ...JobOption) Job {
@@ -159,11 +227,23 @@ func (j *job) Events(ctx context.Context, filter ...event.Query) (<-chan event.E
 }
 
 func (j *job) queryEvents(ctx context.Context, q event.Query, filter ...event.Query) (<-chan event.Event, <-chan error, error) {
-	str, errs, err := j.runQuery(ctx, q)
+	return j.fetchEvents(ctx, q, false, filter...)
+}
+
+// fetchEvents queries the events using the given query. If fresh is true, the
+// cache is bypassed and the query result replaces the cached result.
+func (j *job) fetchEvents(ctx context.Context, q event.Query, fresh bool, filter ...event.Query) (<-chan event.Event, <-chan error, error) {
+	start := stdtime.Now()
+
+	str, errs, err := j.runQuery(ctx, q, fresh)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	if j.logger != nil {
+		str = j.logDuration(ctx, str, start)
+	}
+
 	if len(j.beforeEvent) > 0 {
 		str, errs = j.applyBeforeEvent(ctx, str, errs)
 	}
@@ -175,6 +255,26 @@ func (j *job) queryEvents(ctx context.Context, q event.Query, filter ...event.Qu
 	return str, errs, nil
 }
 
+// logDuration logs the time it took to fetch all events from the given stream
+// when the stream is closed.
+func (j *job) logDuration(ctx context.Context, events <-chan event.Event, start stdtime.Time) <-chan event.Event {
+	out := make(chan event.Event)
+	go func() {
+		defer close(out)
+		var count int
+		for evt := range events {
+			select {
+			case <-ctx.Done():
+				return
+			case out <- evt:
+				count++
+			}
+		}
+		j.logger.Printf("[goes/projection.Job] Fetching %d events took %v", count, stdtime.Since(start))
+	}()
+	return out
+}
+
 func (j *job) applyBeforeEvent(ctx context.Context, events <-chan event.Event, errs <-chan error) (<-chan event.Event, <-chan error) {
 	outErrs := make(chan error)
 	fail := func(err error) {
@@ -224,8 +324,24 @@ func (j *job) EventsOf(ctx context.Context, aggregateName ...string) (<-chan eve
 }
 
 func (j *job) EventsFor(ctx context.Context, target Target[any]) (<-chan event.Event, <-chan error, error) {
+	return j.queryEvents(ctx, j.Query(target))
+}
+
+func (j *job) Query(target Target[any], opts ...ApplyOption) event.Query {
 	q := j.query
 
+	// If the job does not filter events by name, only query the events that
+	// the projection can handle.
+	if len(q.Names()) == 0 {
+		if names := registeredEvents(target); len(names) > 0 {
+			q = query.Merge(q, query.New(query.Name(names...)))
+		}
+	}
+
+	if cfg := newApplyConfig(opts...); cfg.ignoreProgress {
+		return q
+	}
+
 	if progressor, isProgressor := target.(ProgressAware); isProgressor {
 		progressTime, _ := progressor.Progress()
 		if !progressTime.IsZero() {
@@ -241,7 +357,7 @@ func (j *job) EventsFor(ctx context.Context, target Target[any]) (<-chan event.E
 		}
 	}
 
-	return j.queryEvents(ctx, q)
+	return q
 }
 
 func (j *job) Aggregates(ctx context.Context, names ...string) (<-chan aggregate.Ref, <-chan error, error) {
@@ -293,6 +409,30 @@ func (j *job) Aggregates(ctx context.Context, names ...string) (<-chan aggregate
 	return out, errs, nil
 }
 
+func (j *job) AggregatesSorted(ctx context.Context, names ...string) ([]AggregateIDs, error) {
+	str, errs, err := j.Aggregates(ctx, names...)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string][]uuid.UUID)
+	if err := streams.Walk(ctx, func(ref aggregate.Ref) error {
+		ids[ref.Name] = append(ids[ref.Name], ref.ID)
+		return nil
+	}, str, errs); err != nil {
+		return nil, err
+	}
+
+	out := make([]AggregateIDs, 0, len(ids))
+	for name, ids := range ids {
+		sort.Slice(ids, func(a, b int) bool { return ids[a].String() < ids[b].String() })
+		out = append(out, AggregateIDs{Name: name, IDs: ids})
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].Name < out[b].Name })
+
+	return out, nil
+}
+
 func (j *job) Aggregate(ctx context.Context, name string) (uuid.UUID, error) {
 	tuples, errs, err := j.Aggregates(ctx, name)
 	if err != nil {
@@ -320,17 +460,11 @@ func (j *job) Aggregate(ctx context.Context, name string) (uuid.UUID, error) {
 }
 
 func (j *job) Apply(ctx context.Context, target Target[any], opts ...ApplyOption) error {
-	if j.reset {
-		if progressor, isProgressor := target.(ProgressAware); isProgressor {
-			progressor.SetProgress(stdtime.Time{})
-		}
-
-		if resetter, isResetter := target.(Resetter); isResetter {
-			resetter.Reset()
-		}
+	if j.reset || newApplyConfig(opts...).reset {
+		ResetProjection(target)
 	}
 
-	events, errs, err := j.EventsFor(ctx, target)
+	events, errs, err := j.fetchEvents(ctx, j.Query(target, opts...), newApplyConfig(opts...).noCache)
 	if err != nil {
 		return fmt.Errorf("fetch events: %w", err)
 	}
@@ -357,13 +491,21 @@ func (j *job) Apply(ctx context.Context, target Target[any], opts ...ApplyOption
 	}
 }
 
-func (j *job) runQuery(ctx context.Context, q event.Query) (<-chan event.Event, <-chan error, error) {
-	return j.cache.run(ctx, q)
+func (j *job) runQuery(ctx context.Context, q event.Query, fresh bool) (<-chan event.Event, <-chan error, error) {
+	return j.cache.run(ctx, q, fresh)
+}
+
+func (j *job) InvalidateCache() {
+	j.cache.clear()
 }
 
 type queryCache struct {
 	store event.Store
 
+	// disabled makes the cache pass every query to the store without caching
+	// the result.
+	disabled bool
+
 	locksMux sync.Mutex
 	locks    map[[32]byte]*sync.Mutex
 
@@ -379,13 +521,22 @@ func newQueryCache(store event.Store) *queryCache {
 	}
 }
 
-func (c *queryCache) run(ctx context.Context, q event.Query) (<-chan event.Event, <-chan error, error) {
+func (c *queryCache) run(ctx context.Context, q event.Query, fresh bool) (<-chan event.Event, <-chan error, error) {
+	if c.disabled {
+		str, errs, err := c.store.Query(ctx, q)
+		if err != nil {
+			return nil, nil, fmt.Errorf("query events: %w", err)
+		}
+		return str, errs, nil
+	}
+
 	hash := hashQuery(q)
 
-	events, ok := c.cached(hash, true)
-	if ok {
-		out, errs := eventStream(ctx, events)
-		return out, errs, nil
+	if !fresh {
+		if events, ok := c.cached(hash, true); ok {
+			out, errs := eventStream(ctx, events)
+			return out, errs, nil
+		}
 	}
 
 	// Prevent the same query from being run multiple times.
@@ -395,9 +546,11 @@ func (c *queryCache) run(ctx context.Context, q event.Query) (<-chan event.Event
 	defer unlock()
 
 	// Check again if the query was cached by another run.
-	if events, ok = c.cached(hash, false); ok {
-		out, errs := eventStream(ctx, events)
-		return out, errs, nil
+	if !fresh {
+		if events, ok := c.cached(hash, false); ok {
+			out, errs := eventStream(ctx, events)
+			return out, errs, nil
+		}
 	}
 
 	str, errs, err := c.store.Query(ctx, q)
@@ -468,16 +621,123 @@ func (c *queryCache) intercept(ctx context.Context, in <-chan event.Event, hash
 	return out
 }
 
+func (c *queryCache) clear() {
+	c.cacheMux.Lock()
+	defer c.cacheMux.Unlock()
+	c.cache = make(map[[32]byte][]event.Event)
+}
+
 func (c *queryCache) update(hash [32]byte, events []event.Event) {
 	c.cacheMux.Lock()
 	c.cache[hash] = events
 	c.cacheMux.Unlock()
 }
 
-// TODO(bounoable): Is this sufficient for avoiding collisions?
-// Alternative: github.com/mitchellh/hashstructure
+// hashQuery returns a deterministic hash of the filters and sortings of the
+// given query. Every value is written with a length prefix so that different
+// queries cannot produce the same input for the hash function.
 func hashQuery(q event.Query) [32]byte {
-	return sha256.Sum256([]byte(fmt.Sprintf("%v", q)))
+	h := queryHasher{Hash: sha256.New()}
+
+	h.strings(q.Names())
+	h.strings(q.ExcludedNames())
+	h.uuids(q.IDs())
+
+	if times := q.Times(); times != nil {
+		h.int(1)
+		h.times(times.Exact())
+		h.int(int64(len(times.Ranges())))
+		for _, r := range times.Ranges() {
+			h.times([]stdtime.Time{r.Start(), r.End()})
+		}
+		h.times([]stdtime.Time{times.Min(), times.Max()})
+	} else {
+		h.int(0)
+	}
+
+	h.strings(q.AggregateNames())
+	h.strings(q.ExcludedAggregateNames())
+	h.uuids(q.AggregateIDs())
+
+	if versions := q.AggregateVersions(); versions != nil {
+		h.int(1)
+		h.ints(versions.Exact())
+		h.int(int64(len(versions.Ranges())))
+		for _, r := range versions.Ranges() {
+			h.ints(r[:])
+		}
+		h.ints(versions.Min())
+		h.ints(versions.Max())
+	} else {
+		h.int(0)
+	}
+
+	h.int(int64(len(q.Aggregates())))
+	for _, ref := range q.Aggregates() {
+		h.strings([]string{ref.Name})
+		h.uuids([]uuid.UUID{ref.ID})
+	}
+
+	h.int(int64(len(q.Sortings())))
+	for _, s := range q.Sortings() {
+		h.int(int64(s.Sort))
+		h.int(int64(s.Dir))
+	}
+
+	h.uuids([]uuid.UUID{q.AfterID()})
+
+	key := q.ResumeAfter()
+	h.uuids([]uuid.UUID{key.ID, key.AggregateID})
+	h.int(key.Time.UnixNano())
+	h.strings([]string{key.AggregateName})
+	h.int(int64(key.AggregateVersion))
+
+	var sum [32]byte
+	copy(sum[:], h.Sum(nil))
+
+	return sum
+}
+
+type queryHasher struct{ hash.Hash }
+
+func (h queryHasher) int(v int64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(v))
+	h.Write(b[:])
+}
+
+func (h queryHasher) ints(v []int) {
+	h.int(int64(len(v)))
+	for _, i := range v {
+		h.int(int64(i))
+	}
+}
+
+func (h queryHasher) strings(v []string) {
+	h.int(int64(len(v)))
+	for _, s := range v {
+		h.int(int64(len(s)))
+		h.Write([]byte(s))
+	}
+}
+
+func (h queryHasher) uuids(v []uuid.UUID) {
+	h.int(int64(len(v)))
+	for _, id := range v {
+		h.Write(id[:])
+	}
+}
+
+func (h queryHasher) times(v []stdtime.Time) {
+	h.int(int64(len(v)))
+	for _, t := range v {
+		if t.IsZero() {
+			h.int(0)
+			continue
+		}
+		h.int(1)
+		h.int(t.UnixNano())
+	}
 }
 
 func eventStream(ctx context.Context, events []event.Event) (<-chan event.Event, <-chan error) {
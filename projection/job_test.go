@@ -1,9 +1,12 @@
 package projection_test
 
 import (
+	"bytes"
 	"context"
 	"log"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -156,6 +159,93 @@ func TestJob_EventsFor_Progressor(t *testing.T) {
 	test.AssertEqualEventsUnsorted(t, events, storeEvents[1:])
 }
 
+func TestJob_EventsFor_TrackEvent(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	storeEvents := []event.Event{
+		event.New[any]("foo", test.FooEventData{}, event.Time(now.Add(-time.Minute))),
+		event.New[any]("foo", test.FooEventData{}, event.Time(now)),
+		event.New[any]("foo", test.FooEventData{}, event.Time(now.Add(time.Minute))),
+		event.New[any]("foo", test.FooEventData{}, event.Time(now.Add(time.Hour))),
+	}
+	store, _ := newEventStore(t, storeEvents...)
+
+	target := projectiontest.NewMockProgressor()
+	target.TrackEvent(storeEvents[1])
+	target.TrackEvent(storeEvents[0])
+
+	if latest := target.LatestEventTime(); !latest.Equal(storeEvents[1].Time()) {
+		t.Fatalf("LatestEventTime() should return %v; got %v", storeEvents[1].Time(), latest)
+	}
+
+	job := projection.NewJob(ctx, store, query.New())
+
+	str, errs, err := job.EventsFor(job, target)
+	if err != nil {
+		t.Fatalf("EventsFor failed with %q", err)
+	}
+
+	events, err := streams.Drain(ctx, str, errs)
+	if err != nil {
+		t.Fatalf("drain events: %v", err)
+	}
+
+	test.AssertEqualEventsUnsorted(t, events, storeEvents[1:])
+}
+
+func TestJob_Query_registeredEvents(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newEventStore(t)
+
+	target := projection.New()
+	target.RegisterEventHandlerMany(func(event.Event) {}, "foo", "bar")
+
+	job := projection.NewJob(ctx, store, query.New())
+
+	want := []string{"bar", "foo"}
+	if names := job.Query(target).Names(); !cmp.Equal(want, names) {
+		t.Fatalf("Query() should only include the registered events %v; got %v", want, names)
+	}
+
+	job = projection.NewJob(ctx, store, query.New(query.Name("baz")))
+
+	want = []string{"baz"}
+	if names := job.Query(target).Names(); !cmp.Equal(want, names) {
+		t.Fatalf("Query() should not change the event names of the job %v; got %v", want, names)
+	}
+}
+
+func TestJob_Query(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newEventStore(t)
+	q := query.New(query.Name("foo", "bar", "baz"))
+
+	job := projection.NewJob(ctx, store, q)
+
+	if got := job.Query(projectiontest.NewMockProjection()); !reflect.DeepEqual(q, got) {
+		t.Fatalf("Query() should return %v; got %v", q, got)
+	}
+
+	target := projectiontest.NewMockProgressor()
+	now := time.Now()
+	target.SetProgress(now)
+
+	got := job.Query(target)
+
+	if names := got.Names(); !cmp.Equal(q.Names(), names) {
+		t.Fatalf("Query() should return a query for %v events; got %v", q.Names(), names)
+	}
+
+	if min := got.Times().Min(); !min.Equal(now) {
+		t.Fatalf("Query() should return a query for events since %v; got %v", now, min)
+	}
+
+	if got := job.Query(target, projection.IgnoreProgress()); !reflect.DeepEqual(q, got) {
+		t.Fatalf("Query() should return %v when ignoring progress; got %v", q, got)
+	}
+}
+
 func TestJob_Aggregates(t *testing.T) {
 	ctx := context.Background()
 	now := time.Now()
@@ -190,6 +280,56 @@ func TestJob_Aggregates(t *testing.T) {
 	}
 }
 
+func TestJob_AggregatesSorted(t *testing.T) {
+	ctx := context.Background()
+
+	fooIDs := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	barIDs := []uuid.UUID{uuid.New(), uuid.New()}
+
+	var storeEvents []event.Event
+	for i := len(fooIDs) - 1; i >= 0; i-- {
+		storeEvents = append(storeEvents, event.New[any]("foo", test.FooEventData{}, event.Aggregate(fooIDs[i], "foo-agg", 1)))
+		if i < len(barIDs) {
+			storeEvents = append(storeEvents, event.New[any]("foo", test.FooEventData{}, event.Aggregate(barIDs[i], "bar-agg", 1)))
+		}
+	}
+	// Add a second event of the first aggregate to test deduplication.
+	storeEvents = append(storeEvents, event.New[any]("foo", test.FooEventData{}, event.Aggregate(fooIDs[0], "foo-agg", 2)))
+
+	store, _ := newEventStore(t, storeEvents...)
+
+	job := projection.NewJob(ctx, store, query.New())
+
+	groups, err := job.AggregatesSorted(job)
+	if err != nil {
+		t.Fatalf("AggregatesSorted failed with %q", err)
+	}
+
+	sortIDs := func(ids []uuid.UUID) []uuid.UUID {
+		sorted := append([]uuid.UUID(nil), ids...)
+		sort.Slice(sorted, func(a, b int) bool { return sorted[a].String() < sorted[b].String() })
+		return sorted
+	}
+
+	want := []projection.AggregateIDs{
+		{Name: "bar-agg", IDs: sortIDs(barIDs)},
+		{Name: "foo-agg", IDs: sortIDs(fooIDs)},
+	}
+
+	if !reflect.DeepEqual(want, groups) {
+		t.Fatalf("AggregatesSorted returned wrong aggregates. want=%v got=%v", want, groups)
+	}
+
+	groups, err = job.AggregatesSorted(job, "foo-agg")
+	if err != nil {
+		t.Fatalf("AggregatesSorted failed with %q", err)
+	}
+
+	if !reflect.DeepEqual(want[1:], groups) {
+		t.Fatalf("AggregatesSorted returned wrong aggregates. want=%v got=%v", want[1:], groups)
+	}
+}
+
 func TestJob_Aggregates_customAggregateQuery(t *testing.T) {
 	ctx := context.Background()
 	now := time.Now()
@@ -397,6 +537,130 @@ func TestJob_Events_cache(t *testing.T) {
 	}
 }
 
+func TestJob_InvalidateCache(t *testing.T) {
+	ctx := context.Background()
+	store, storeEvents := newEventStore(t)
+
+	job := projection.NewJob(ctx, store, query.New())
+
+	str, errs, err := job.Events(job)
+	if err != nil {
+		t.Fatalf("Events failed with %q", err)
+	}
+
+	if _, err := streams.Drain(ctx, str, errs); err != nil {
+		t.Fatalf("drain events: %v", err)
+	}
+
+	newEvent := event.New[any]("foo", test.FooEventData{})
+	if err := store.Insert(ctx, newEvent); err != nil {
+		t.Fatalf("insert event: %v", err)
+	}
+
+	if str, errs, err = job.Events(job); err != nil {
+		t.Fatalf("Events failed with %q", err)
+	}
+
+	events, err := streams.Drain(ctx, str, errs)
+	if err != nil {
+		t.Fatalf("drain events: %v", err)
+	}
+
+	test.AssertEqualEventsUnsorted(t, storeEvents, events)
+
+	job.InvalidateCache()
+
+	if str, errs, err = job.Events(job); err != nil {
+		t.Fatalf("Events failed with %q", err)
+	}
+
+	if events, err = streams.Drain(ctx, str, errs); err != nil {
+		t.Fatalf("drain events: %v", err)
+	}
+
+	test.AssertEqualEventsUnsorted(t, append(storeEvents, newEvent), events)
+}
+
+func TestJob_Apply_NoCache(t *testing.T) {
+	ctx := context.Background()
+	store, storeEvents := newEventStore(t)
+
+	job := projection.NewJob(ctx, store, query.New())
+
+	if err := job.Apply(job, projectiontest.NewMockProjection()); err != nil {
+		t.Fatalf("Apply failed with %q", err)
+	}
+
+	newEvent := event.New[any]("foo", test.FooEventData{})
+	if err := store.Insert(ctx, newEvent); err != nil {
+		t.Fatalf("insert event: %v", err)
+	}
+
+	proj := projectiontest.NewMockProjection()
+	if err := job.Apply(job, proj, projection.NoCache()); err != nil {
+		t.Fatalf("Apply failed with %q", err)
+	}
+
+	want := append(storeEvents, newEvent)
+	test.AssertEqualEventsUnsorted(t, want, proj.AppliedEvents)
+
+	// the cache should have been updated
+	str, errs, err := job.Events(job)
+	if err != nil {
+		t.Fatalf("Events failed with %q", err)
+	}
+
+	events, err := streams.Drain(ctx, str, errs)
+	if err != nil {
+		t.Fatalf("drain events: %v", err)
+	}
+
+	test.AssertEqualEventsUnsorted(t, want, events)
+}
+
+func TestJob_cache_queryKey(t *testing.T) {
+	ctx := context.Background()
+	fooID, barID := uuid.New(), uuid.New()
+	store, _ := newEventStore(t,
+		event.New[any]("foo", test.FooEventData{}, event.Aggregate(fooID, "foo", 1)),
+		event.New[any]("bar", test.BarEventData{}, event.Aggregate(barID, "bar", 1)),
+	)
+
+	// both queries have the same string representation
+	fooQuery := stringerQuery{query.New(query.Name("foo"))}
+	barQuery := stringerQuery{query.New(query.Name("bar"))}
+
+	job := projection.NewJob(ctx, store, fooQuery, projection.WithAggregateQuery(barQuery))
+
+	str, errs, err := job.Events(job)
+	if err != nil {
+		t.Fatalf("Events failed with %q", err)
+	}
+
+	if _, err := streams.Drain(ctx, str, errs); err != nil {
+		t.Fatalf("drain events: %v", err)
+	}
+
+	refStr, errs, err := job.Aggregates(job)
+	if err != nil {
+		t.Fatalf("Aggregates failed with %q", err)
+	}
+
+	refs, err := streams.Drain(ctx, refStr, errs)
+	if err != nil {
+		t.Fatalf("drain aggregates: %v", err)
+	}
+
+	want := []aggregate.Ref{{Name: "bar", ID: barID}}
+	if !cmp.Equal(want, refs) {
+		t.Fatalf("Aggregates should return %v; got %v", want, refs)
+	}
+}
+
+type stringerQuery struct{ event.Query }
+
+func (stringerQuery) String() string { return "query" }
+
 func TestWithFilter(t *testing.T) {
 	ctx := context.Background()
 	now := time.Now()
@@ -428,6 +692,27 @@ func TestWithFilter(t *testing.T) {
 	test.AssertEqualEvents(t, events, []event.Event{storeEvents[0], storeEvents[4], storeEvents[5]})
 }
 
+func TestWithLogger(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newEventStore(t)
+
+	var buf bytes.Buffer
+	job := projection.NewJob(ctx, store, query.New(), projection.WithLogger(log.New(&buf, "", 0)))
+
+	str, errs, err := job.Events(job)
+	if err != nil {
+		t.Fatalf("Events failed with %q", err)
+	}
+
+	if _, err := streams.Drain(ctx, str, errs); err != nil {
+		t.Fatalf("drain events: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "Fetching 3 events took") {
+		t.Fatalf("job should log the query duration; logged %q", buf.String())
+	}
+}
+
 func TestWithReset(t *testing.T) {
 	ctx := context.Background()
 	now := time.Now()
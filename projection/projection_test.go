@@ -76,6 +76,28 @@ func TestApply_ProgressAware_multipleEventsWithSameTime(t *testing.T) {
 	}
 }
 
+func TestApply_ProgressAware_sameTimeAcrossCalls(t *testing.T) {
+	proj := projectiontest.NewMockProgressor()
+
+	evtTime := time.Now().Add(time.Minute)
+	first := event.New("foo", test.FooEventData{}, event.Time(evtTime)).Any()
+	second := event.New("bar", test.FooEventData{}, event.Time(evtTime)).Any()
+
+	projection.Apply(proj, []event.Event{first})
+	projection.Apply(proj, []event.Event{first, second})
+
+	if len(proj.AppliedEvents) != 2 {
+		t.Fatalf("%d events should have been applied; got %d", 2, len(proj.AppliedEvents))
+	}
+
+	_, ids := proj.Progress()
+	wantIDs := []uuid.UUID{first.ID(), second.ID()}
+
+	if !cmp.Equal(ids, wantIDs) {
+		t.Fatalf("Progress() should return the ids of all applied events with the last time %v; got %v", wantIDs, ids)
+	}
+}
+
 func TestApply_ProgressorAware_IgnoreProgress(t *testing.T) {
 	now := time.Now()
 	proj := projectiontest.NewMockProgressor()
@@ -119,3 +141,112 @@ func TestApply_Guard(t *testing.T) {
 
 	proj.ExpectApplied(t, events[:2]...)
 }
+
+func TestApplyResult(t *testing.T) {
+	guard := projection.QueryGuard(query.New(query.Name("foo", "bar")))
+	proj := projectiontest.NewMockGuardedProjection(guard)
+
+	now := time.Now()
+	events := []event.Event{
+		event.New[any]("foo", test.FooEventData{}, event.Time(now.Add(time.Minute))),
+		event.New[any]("bar", test.FooEventData{}, event.Time(now)),
+		event.New[any]("baz", test.FooEventData{}, event.Time(now.Add(time.Hour))),
+	}
+
+	result := projection.ApplyResult(proj, events)
+
+	if result.Applied != 2 {
+		t.Fatalf("Applied should be %d; got %d", 2, result.Applied)
+	}
+
+	if !result.LastEventTime.Equal(events[0].Time()) {
+		t.Fatalf("LastEventTime should be %v; got %v", events[0].Time(), result.LastEventTime)
+	}
+
+	proj.ExpectApplied(t, events[:2]...)
+}
+
+func TestApplyResult_noEvents(t *testing.T) {
+	proj := projectiontest.NewMockProjection()
+
+	result := projection.ApplyResult(proj, nil)
+
+	if result.Applied != 0 {
+		t.Fatalf("Applied should be %d; got %d", 0, result.Applied)
+	}
+
+	if !result.LastEventTime.IsZero() {
+		t.Fatalf("LastEventTime should be zero; got %v", result.LastEventTime)
+	}
+}
+
+func TestOnResult(t *testing.T) {
+	proj := projectiontest.NewMockProjection()
+
+	now := time.Now()
+	events := []event.Event{
+		event.New[any]("foo", test.FooEventData{}, event.Time(now)),
+		event.New[any]("foo", test.FooEventData{}, event.Time(now.Add(time.Minute))),
+	}
+
+	var result projection.Result
+	projection.Apply(proj, events, projection.OnResult(func(r projection.Result) { result = r }))
+
+	if result.Applied != 2 {
+		t.Fatalf("Applied should be %d; got %d", 2, result.Applied)
+	}
+
+	if !result.LastEventTime.Equal(events[1].Time()) {
+		t.Fatalf("LastEventTime should be %v; got %v", events[1].Time(), result.LastEventTime)
+	}
+}
+
+func TestProgressor_TrackEvent(t *testing.T) {
+	p := projection.NewProgressor()
+
+	if latest := p.LatestEventTime(); !latest.IsZero() {
+		t.Fatalf("LatestEventTime() should return the zero Time; got %v", latest)
+	}
+
+	now := time.Now()
+	events := []event.Event{
+		event.New[any]("foo", test.FooEventData{}, event.Time(now)),
+		event.New[any]("foo", test.FooEventData{}, event.Time(now)),
+		event.New[any]("foo", test.FooEventData{}, event.Time(now.Add(-time.Minute))),
+	}
+
+	for _, evt := range events {
+		p.TrackEvent(evt)
+	}
+	p.TrackEvent(events[0])
+
+	latest, ids := p.Progress()
+	if !latest.Equal(now) {
+		t.Fatalf("Progress() should return %v as the last time; got %v", now, latest)
+	}
+
+	wantIDs := []uuid.UUID{events[0].ID(), events[1].ID()}
+	if !cmp.Equal(wantIDs, ids) {
+		t.Fatalf("Progress() should return the ids of the latest events %v; got %v", wantIDs, ids)
+	}
+}
+
+func TestResetProjection(t *testing.T) {
+	proj := projectiontest.NewMockResetProjection(8)
+	proj.SetProgress(time.Now(), uuid.New())
+
+	projection.ResetProjection(proj)
+
+	if proj.Foo != 0 {
+		t.Fatalf("Foo should be %d after reset; got %d", 0, proj.Foo)
+	}
+
+	progress, ids := proj.Progress()
+	if !progress.IsZero() {
+		t.Fatalf("progress should be zero after reset; got %v", progress)
+	}
+
+	if len(ids) != 0 {
+		t.Fatalf("last event ids should be empty after reset; got %v", ids)
+	}
+}
@@ -0,0 +1,34 @@
+package projection
+
+import (
+	"context"
+
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/event/query"
+)
+
+// Replay applies the events of an event store to a projection. Use Replay to
+// rebuild a projection from scratch without creating a Job:
+//
+//	var store event.Store
+//	var p projection.Target[any]
+//	err := projection.Replay(context.TODO(), store, p)
+//
+// Replay applies the events like Job.Apply does, without caching them. The
+// events are queried sorted by time and aggregate version and applied to the
+// projection using ApplyStream. If the projection provides a
+// RegisteredEvents() []string method that returns at least one event name,
+// only those events are queried.
+//
+// If the projection implements ProgressAware, only the events that happened
+// since the current progress of the projection are replayed, which allows for
+// partial replays. Pass IgnoreProgress() to replay all events, or FromScratch()
+// to reset the projection before replaying all events.
+func Replay(ctx context.Context, store event.Store, target Target[any], opts ...ApplyOption) error {
+	q := query.New(query.SortByMulti(
+		event.SortOptions{Sort: event.SortTime, Dir: event.SortAsc},
+		event.SortOptions{Sort: event.SortAggregateVersion, Dir: event.SortAsc},
+	))
+
+	return NewJob(ctx, store, q, withoutCache()).Apply(ctx, target, append(opts, NoCache())...)
+}
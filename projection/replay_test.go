@@ -0,0 +1,125 @@
+package projection_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/goes/event/test"
+	"github.com/modernice/goes/projection"
+)
+
+type counterProjection struct {
+	*projection.Base
+	*projection.Progressor
+
+	count int
+}
+
+func newCounterProjection() *counterProjection {
+	p := &counterProjection{
+		Base:       projection.New(),
+		Progressor: projection.NewProgressor(),
+	}
+	event.ApplyWith(p, p.increment, "foo")
+	return p
+}
+
+func (p *counterProjection) Reset() {
+	p.count = 0
+}
+
+func (p *counterProjection) increment(event.Of[test.FooEventData]) {
+	p.count++
+}
+
+func TestReplay(t *testing.T) {
+	now := time.Now()
+	events := []event.Event{
+		event.New("foo", test.FooEventData{}, event.Time(now.Add(3*time.Second))).Any(),
+		event.New("bar", test.BarEventData{}, event.Time(now.Add(time.Second))).Any(),
+		event.New("foo", test.FooEventData{}, event.Time(now)).Any(),
+		event.New("foo", test.FooEventData{}, event.Time(now.Add(2*time.Second))).Any(),
+	}
+	store := eventstore.New(events...)
+
+	p := newCounterProjection()
+
+	if err := projection.Replay(context.Background(), store, p); err != nil {
+		t.Fatalf("Replay() failed with %q", err)
+	}
+
+	if p.count != 3 {
+		t.Fatalf("projection should have counted %d events; got %d", 3, p.count)
+	}
+
+	progress, _ := p.Progress()
+	if !progress.Equal(events[0].Time()) {
+		t.Fatalf("progress should be %v; got %v", events[0].Time(), progress)
+	}
+}
+
+func TestReplay_partial(t *testing.T) {
+	now := time.Now()
+	events := []event.Event{
+		event.New("foo", test.FooEventData{}, event.Time(now)).Any(),
+		event.New("foo", test.FooEventData{}, event.Time(now.Add(time.Second))).Any(),
+		event.New("foo", test.FooEventData{}, event.Time(now.Add(2*time.Second))).Any(),
+	}
+	store := eventstore.New(events...)
+
+	p := newCounterProjection()
+	p.SetProgress(events[1].Time(), events[1].ID())
+
+	if err := projection.Replay(context.Background(), store, p); err != nil {
+		t.Fatalf("Replay() failed with %q", err)
+	}
+
+	if p.count != 1 {
+		t.Fatalf("projection should have counted %d event; got %d", 1, p.count)
+	}
+}
+
+func TestReplay_IgnoreProgress(t *testing.T) {
+	now := time.Now()
+	events := []event.Event{
+		event.New("foo", test.FooEventData{}, event.Time(now)).Any(),
+		event.New("foo", test.FooEventData{}, event.Time(now.Add(time.Second))).Any(),
+	}
+	store := eventstore.New(events...)
+
+	p := newCounterProjection()
+	p.SetProgress(events[1].Time(), events[1].ID())
+
+	if err := projection.Replay(context.Background(), store, p, projection.IgnoreProgress()); err != nil {
+		t.Fatalf("Replay() failed with %q", err)
+	}
+
+	if p.count != 2 {
+		t.Fatalf("projection should have counted %d events; got %d", 2, p.count)
+	}
+}
+
+func TestReplay_FromScratch(t *testing.T) {
+	now := time.Now()
+	events := []event.Event{
+		event.New("foo", test.FooEventData{}, event.Time(now)).Any(),
+		event.New("foo", test.FooEventData{}, event.Time(now.Add(time.Second))).Any(),
+	}
+	store := eventstore.New(events...)
+
+	p := newCounterProjection()
+	if err := projection.Replay(context.Background(), store, p); err != nil {
+		t.Fatalf("Replay() failed with %q", err)
+	}
+
+	if err := projection.Replay(context.Background(), store, p, projection.FromScratch()); err != nil {
+		t.Fatalf("Replay() failed with %q", err)
+	}
+
+	if p.count != 2 {
+		t.Fatalf("projection should have counted %d events after the rebuild; got %d", 2, p.count)
+	}
+}
@@ -15,7 +15,22 @@ type schedule struct {
 	eventNames []string
 
 	triggersMux sync.RWMutex
-	triggers    []chan projection.Trigger
+	triggers    []chan trigger
+}
+
+// trigger is a projection.Trigger that is sent to a subscription of a
+// schedule. If done is non-nil, it is called with the result of the Job that
+// was created by the trigger.
+type trigger struct {
+	projection.Trigger
+	done func(error)
+}
+
+// scheduledJob is a Job that is applied by a subscription of a schedule. If
+// done is non-nil, it is called with the result of the apply function.
+type scheduledJob struct {
+	job  projection.Job
+	done func(error)
 }
 
 func newSchedule(store event.Store, eventNames []string) *schedule {
@@ -78,27 +93,81 @@ func newSchedule(store event.Store, eventNames []string) *schedule {
 //
 //	schedule.Trigger(context.TODO())
 func (schedule *schedule) Trigger(ctx context.Context, opts ...projection.TriggerOption) error {
-	schedule.triggersMux.RLock()
-	triggers := make([]chan projection.Trigger, len(schedule.triggers))
-	copy(triggers, schedule.triggers)
-	schedule.triggersMux.RUnlock()
-
-	for _, triggers := range triggers {
+	for _, triggers := range schedule.subscriptionTriggers() {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case triggers <- schedule.newTrigger(opts...):
+		case triggers <- trigger{Trigger: schedule.newTrigger(opts...)}:
 		}
 	}
 
 	return nil
 }
 
-func (schedule *schedule) newTriggers() <-chan projection.Trigger {
+// Refresh manually triggers the schedule like Trigger does, but additionally
+// reports when the created Jobs have been applied. Use Refresh to run a
+// projection on demand, for example from an admin endpoint, and wait for the
+// projection to be up-to-date:
+//
+//	errs, err := schedule.Refresh(context.TODO())
+//	// handle err
+//	for err := range errs {
+//		log.Printf("Projection failed: %v", err)
+//	}
+//
+// A Job is created for every subscription of the schedule. The returned
+// channel receives the errors returned by the apply functions of the
+// subscriptions and is closed after every Job has been applied, or its
+// subscription has been canceled. The errors are also sent to the error
+// channels of the subscriptions, just like for any other Job. The Jobs are
+// applied in line with the other Jobs of a subscription, so Refresh does not
+// interfere with continuous or periodic Jobs.
+//
+// If ctx is canceled before every subscription has accepted the trigger,
+// Refresh returns ctx.Err().
+func (schedule *schedule) Refresh(ctx context.Context, opts ...projection.TriggerOption) (<-chan error, error) {
+	subs := schedule.subscriptionTriggers()
+
+	out := make(chan error, len(subs))
+	var wg sync.WaitGroup
+	wg.Add(len(subs))
+	done := func(err error) {
+		defer wg.Done()
+		if err != nil {
+			out <- err
+		}
+	}
+
+	for i, triggers := range subs {
+		select {
+		case <-ctx.Done():
+			wg.Add(i - len(subs))
+			return nil, ctx.Err()
+		case triggers <- trigger{Trigger: schedule.newTrigger(opts...), done: done}:
+		}
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out, nil
+}
+
+func (schedule *schedule) subscriptionTriggers() []chan trigger {
+	schedule.triggersMux.RLock()
+	defer schedule.triggersMux.RUnlock()
+	triggers := make([]chan trigger, len(schedule.triggers))
+	copy(triggers, schedule.triggers)
+	return triggers
+}
+
+func (schedule *schedule) newTriggers() <-chan trigger {
 	schedule.triggersMux.Lock()
 	defer schedule.triggersMux.Unlock()
 
-	triggers := make(chan projection.Trigger)
+	triggers := make(chan trigger)
 	schedule.triggers = append(schedule.triggers, triggers)
 
 	return triggers
@@ -112,7 +181,7 @@ func (schedule *schedule) newTrigger(opts ...projection.TriggerOption) projectio
 	return t
 }
 
-func (schedule *schedule) removeTriggers(triggers <-chan projection.Trigger) {
+func (schedule *schedule) removeTriggers(triggers <-chan trigger) {
 	schedule.triggersMux.Lock()
 	defer schedule.triggersMux.Unlock()
 	for i, striggers := range schedule.triggers {
@@ -126,8 +195,8 @@ func (schedule *schedule) removeTriggers(triggers <-chan projection.Trigger) {
 func (schedule *schedule) handleTriggers(
 	ctx context.Context,
 	sub projection.Subscription,
-	triggers <-chan projection.Trigger,
-	jobs chan<- projection.Job,
+	triggers <-chan trigger,
+	jobs chan<- scheduledJob,
 	out chan<- error,
 	wg *sync.WaitGroup,
 ) {
@@ -144,8 +213,14 @@ func (schedule *schedule) handleTriggers(
 			}
 			select {
 			case <-ctx.Done():
+				if trigger.done != nil {
+					trigger.done(ctx.Err())
+				}
 				return
-			case jobs <- schedule.newJob(ctx, sub, schedule.store, q, trigger.JobOptions()...):
+			case jobs <- scheduledJob{
+				job:  schedule.newJob(ctx, sub, schedule.store, q, trigger.JobOptions()...),
+				done: trigger.done,
+			}:
 			}
 		}
 	}
@@ -154,14 +229,18 @@ func (schedule *schedule) handleTriggers(
 func (schedule *schedule) applyJobs(
 	ctx context.Context,
 	apply func(projection.Job) error,
-	jobs <-chan projection.Job,
+	jobs <-chan scheduledJob,
 	out chan<- error,
 	done chan struct{},
 ) {
 	defer close(done)
 	defer close(out)
 	for job := range jobs {
-		if err := apply(job); err != nil {
+		err := apply(job.job)
+		if job.done != nil {
+			job.done(err)
+		}
+		if err != nil {
 			select {
 			case <-ctx.Done():
 				return
@@ -174,7 +253,7 @@ func (schedule *schedule) applyJobs(
 func (schedule *schedule) applyStartupJob(
 	ctx context.Context,
 	sub projection.Subscription,
-	jobs chan<- projection.Job,
+	jobs chan<- scheduledJob,
 	apply func(projection.Job) error,
 ) error {
 	if sub.Startup == nil {
@@ -52,7 +52,15 @@ type ContinuousOption func(*Continuous)
 // Debounce returns a ContinuousOption that debounces projection Jobs by the
 // given Duration. When multiple events are published within the given Duration,
 // only 1 projection Job for all events will be created instead of 1 Job per
-// Event.
+// Event. Every received event restarts the debounce timer, so the Job is
+// created after no event has been received for the given Duration. The Job
+// contains all events that were received since the previous Job was created.
+//
+// To avoid that a continuous stream of events defers the Job indefinitely, a
+// Job is also created when the debounce cap (see DebounceCap) has passed since
+// the first event after the previous Job. The Job then contains the events that
+// were received up to that point, and the next event starts a new debounce
+// window.
 //
 //	var bus event.Bus
 //	var store event.Store
@@ -155,7 +163,7 @@ func (schedule *Continuous) Subscribe(ctx context.Context, apply func(projection
 	}
 
 	out := make(chan error)
-	jobs := make(chan projection.Job)
+	jobs := make(chan scheduledJob)
 	triggers := schedule.newTriggers()
 	done := make(chan struct{})
 
@@ -190,7 +198,7 @@ func (schedule *Continuous) handleEvents(
 	sub projection.Subscription,
 	events <-chan event.Event,
 	errs <-chan error,
-	jobs chan<- projection.Job,
+	jobs chan<- scheduledJob,
 	out chan<- error,
 	wg *sync.WaitGroup,
 ) {
@@ -206,14 +214,9 @@ func (schedule *Continuous) handleEvents(
 	var mux sync.Mutex
 	var buf []event.Event
 	var debounce, debounceCap *time.Timer
-	var jobCreated bool
 
+	// clearDebounce must be called with mux locked.
 	clearDebounce := func() {
-		mux.Lock()
-		defer mux.Unlock()
-
-		jobCreated = false
-
 		if debounce != nil {
 			debounce.Stop()
 			debounce = nil
@@ -225,15 +228,21 @@ func (schedule *Continuous) handleEvents(
 		}
 	}
 
-	defer clearDebounce()
+	defer func() {
+		mux.Lock()
+		defer mux.Unlock()
+		clearDebounce()
+	}()
 
 	createJob := func() {
-		defer clearDebounce()
-
 		mux.Lock()
 		defer mux.Unlock()
 
-		if jobCreated {
+		clearDebounce()
+
+		// The debounce timer and the cap timer may both fire for the same
+		// events. The job is only created by the first of them.
+		if len(buf) == 0 {
 			return
 		}
 
@@ -249,29 +258,33 @@ func (schedule *Continuous) handleEvents(
 
 		select {
 		case <-ctx.Done():
-		case jobs <- job:
+		case jobs <- scheduledJob{job: job}:
 		}
 
 		buf = buf[:0]
-		jobCreated = true
 	}
 
 	addEvent := func(evt event.Event) {
-		clearDebounce()
+		mux.Lock()
 
 		buf = append(buf, evt)
 
 		if schedule.debounce <= 0 {
+			mux.Unlock()
 			createJob()
 			return
 		}
 
-		mux.Lock()
 		defer mux.Unlock()
 
+		if debounce != nil {
+			debounce.Stop()
+		}
 		debounce = time.AfterFunc(schedule.debounce, createJob)
 
-		if cap := schedule.computeDebounceCap(); cap > 0 {
+		// The cap is measured from the first event of a burst, so it is only
+		// started if there is no running cap timer.
+		if cap := schedule.computeDebounceCap(); cap > 0 && debounceCap == nil {
 			debounceCap = time.AfterFunc(cap, createJob)
 		}
 	}
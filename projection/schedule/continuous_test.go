@@ -2,6 +2,7 @@ package schedule_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -186,6 +187,9 @@ func TestDebounceCap(t *testing.T) {
 		for _, evt := range events {
 			time.Sleep(50 * time.Millisecond)
 			if err := bus.Publish(ctx, evt); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
 				panic(fmt.Errorf("publish event: %v", err))
 			}
 		}
@@ -210,6 +214,141 @@ func TestDebounceCap(t *testing.T) {
 	}
 }
 
+func TestDebounce_coalesce(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := eventbus.New()
+	store := eventstore.New()
+
+	schedule := schedule.Continuously(bus, store, []string{"foo"}, schedule.Debounce(200*time.Millisecond))
+
+	jobEvents := make(chan []event.Event, 4)
+
+	errs, err := schedule.Subscribe(ctx, func(job projection.Job) error {
+		str, errs, err := job.Events(job)
+		if err != nil {
+			return err
+		}
+		events, err := streams.Drain(job, str, errs)
+		if err != nil {
+			return err
+		}
+		jobEvents <- events
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Subscribe failed with %q", err)
+	}
+
+	events := []event.Event{
+		event.New[any]("foo", test.FooEventData{A: "1"}),
+		event.New[any]("foo", test.FooEventData{A: "2"}),
+		event.New[any]("foo", test.FooEventData{A: "3"}),
+	}
+
+	for _, evt := range events {
+		if err := bus.Publish(ctx, evt); err != nil {
+			t.Fatalf("publish event: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	select {
+	case <-time.After(3 * time.Second):
+		t.Fatalf("timed out")
+	case err := <-errs:
+		t.Fatal(err)
+	case got := <-jobEvents:
+		test.AssertEqualEventsUnsorted(t, events, got)
+	}
+
+	select {
+	case got := <-jobEvents:
+		t.Fatalf("only 1 Job should be created; got another Job with %d events", len(got))
+	case <-time.After(300 * time.Millisecond):
+	}
+}
+
+func TestDebounceCap_burst(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := eventbus.New()
+	store := eventstore.New()
+
+	schedule := schedule.Continuously(
+		bus,
+		store,
+		[]string{"foo"},
+		schedule.Debounce(100*time.Millisecond),
+		schedule.DebounceCap(300*time.Millisecond),
+	)
+
+	jobEvents := make(chan []event.Event, 10)
+
+	errs, err := schedule.Subscribe(ctx, func(job projection.Job) error {
+		str, errs, err := job.Events(job)
+		if err != nil {
+			return err
+		}
+		events, err := streams.Drain(job, str, errs)
+		if err != nil {
+			return err
+		}
+		jobEvents <- events
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Subscribe failed with %q", err)
+	}
+
+	// Publish an event every 20ms for 1s, so that the debounce timer never
+	// expires during the burst.
+	published := make(chan int, 1)
+	go func() {
+		var n int
+		for start := time.Now(); time.Since(start) < time.Second; n++ {
+			if err := bus.Publish(ctx, event.New[any]("foo", test.FooEventData{})); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				panic(fmt.Errorf("publish event: %v", err))
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		published <- n
+	}()
+
+	var jobs, received int
+	timeout := time.After(3 * time.Second)
+	var total = -1
+	for total < 0 || received < total {
+		select {
+		case <-timeout:
+			t.Fatalf("timed out. received %d/%d events in %d Jobs", received, total, jobs)
+		case err := <-errs:
+			t.Fatal(err)
+		case n := <-published:
+			total = n
+		case events := <-jobEvents:
+			if total < 0 && len(events) < 2 {
+				t.Fatalf("Jobs created by the debounce cap should contain multiple events; got %d", len(events))
+			}
+			jobs++
+			received += len(events)
+		}
+	}
+
+	if jobs < 3 {
+		t.Fatalf("DebounceCap() should trigger Jobs during the burst; got %d Jobs", jobs)
+	}
+
+	if received != total {
+		t.Fatalf("Jobs should contain all %d published events exactly once; got %d", total, received)
+	}
+}
+
 func TestContinuous_Subscribe_Progressor(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -330,6 +469,83 @@ func TestContinuous_Trigger(t *testing.T) {
 	proj.ExpectApplied(t, storeEvents[:3]...)
 }
 
+func TestContinuous_Refresh(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	bus := eventbus.New()
+	store := eventstore.New()
+
+	storeEvents := []event.Event{
+		event.New[any]("foo", test.FooEventData{}),
+		event.New[any]("bar", test.FooEventData{}),
+		event.New[any]("baz", test.FooEventData{}),
+		event.New[any]("foobar", test.FooEventData{}),
+	}
+
+	if err := store.Insert(ctx, storeEvents...); err != nil {
+		t.Fatalf("insert events: %v", err)
+	}
+
+	schedule := schedule.Continuously(bus, store, []string{"foo", "bar", "baz"})
+
+	proj := projectiontest.NewMockProjection()
+
+	if _, err := schedule.Subscribe(ctx, func(job projection.Job) error {
+		return job.Apply(job, proj)
+	}); err != nil {
+		t.Fatalf("Subscribe failed with %q", err)
+	}
+
+	errs, err := schedule.Refresh(ctx)
+	if err != nil {
+		t.Fatalf("Refresh failed with %q", err)
+	}
+
+	if err := streams.Walk(ctx, func(err error) error { return err }, errs); err != nil {
+		t.Fatalf("Refresh should not report an error; got %q", err)
+	}
+
+	proj.ExpectApplied(t, storeEvents[:3]...)
+}
+
+func TestContinuous_Refresh_error(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	bus := eventbus.New()
+	store := eventstore.New()
+
+	schedule := schedule.Continuously(bus, store, []string{"foo"})
+
+	mockError := errors.New("mock error")
+
+	subErrs, err := schedule.Subscribe(ctx, func(projection.Job) error {
+		return mockError
+	})
+	if err != nil {
+		t.Fatalf("Subscribe failed with %q", err)
+	}
+	go func() {
+		for range subErrs {
+		}
+	}()
+
+	errs, err := schedule.Refresh(ctx)
+	if err != nil {
+		t.Fatalf("Refresh failed with %q", err)
+	}
+
+	var got []error
+	for err := range errs {
+		got = append(got, err)
+	}
+
+	if len(got) != 1 || !errors.Is(got[0], mockError) {
+		t.Fatalf("Refresh should report %q; got %v", mockError, got)
+	}
+}
+
 func TestContinuous_Trigger_Filter(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -4,10 +4,11 @@ import (
 	"context"
 	"fmt"
 	"sync"
-	"time"
+	stdtime "time"
 
 	"github.com/modernice/goes/event"
 	"github.com/modernice/goes/event/query"
+	"github.com/modernice/goes/event/query/time"
 	"github.com/modernice/goes/projection"
 )
 
@@ -16,13 +17,34 @@ import (
 type Periodic struct {
 	*schedule
 
-	interval time.Duration
+	interval stdtime.Duration
+}
+
+// progress keeps track of the time of the latest applied event of a
+// progressive subscription. While a Job that was created by the ticker is
+// pending, further ticks are skipped because their queries would not yet
+// include the progress of the pending Job.
+type progress struct {
+	mux     sync.Mutex
+	latest  stdtime.Time
+	pending bool
+}
+
+// tickJob is a projection Job that was created by the ticker of a progressive
+// subscription. It records the earliest progress of the projections that the
+// Job was applied to.
+type tickJob struct {
+	projection.Job
+
+	mux     sync.Mutex
+	latest  stdtime.Time
+	applied bool
 }
 
 // Periodically returns a Periodic schedule that, when subscribed to, creates a
 // projection Job every interval Duration and passes that Job to every
 // subscriber of the schedule.
-func Periodically(store event.Store, interval time.Duration, eventNames []string) *Periodic {
+func Periodically(store event.Store, interval stdtime.Duration, eventNames []string) *Periodic {
 	return &Periodic{
 		schedule: newSchedule(store, eventNames),
 		interval: interval,
@@ -59,13 +81,39 @@ func Periodically(store event.Store, interval time.Duration, eventNames []string
 //
 // When the schedule is triggered by calling schedule.Trigger, a projection Job
 // will be created and passed to apply.
+//
+// By default, every tick creates a Job that queries all configured events from
+// the beginning of time. When subscribing with the projection.Progressive
+// option, the schedule only queries the events that happened after the latest
+// event of the previously applied Job:
+//
+//	s.Subscribe(context.TODO(), apply, projection.Progressive())
+//
+// The progress is taken from the projections that the Job is applied to using
+// Job.Apply: the progress of a projection that implements
+// projection.ProgressAware, or the time of the latest applied event for other
+// projections. If the Job is applied to multiple projections, the earliest
+// progress is used. Like Job.Apply, the schedule queries the events from one
+// nanosecond before the progress, because multiple events may have the same
+// time. ProgressAware projections skip events that were already applied;
+// other projections may receive the events at the time of the progress again.
+//
+// Only Jobs created by the ticker are progressive. Jobs created by Trigger or
+// on startup use their own query. Ticks that occur while a progressive Job is
+// still being applied are skipped.
 func (schedule *Periodic) Subscribe(ctx context.Context, apply func(projection.Job) error, opts ...projection.SubscribeOption) (<-chan error, error) {
 	cfg := projection.NewSubscription(opts...)
 
-	ticker := time.NewTicker(schedule.interval)
+	var prog *progress
+	if cfg.Progressive {
+		prog = &progress{}
+		apply = prog.track(apply)
+	}
+
+	ticker := stdtime.NewTicker(schedule.interval)
 
 	out := make(chan error)
-	jobs := make(chan projection.Job)
+	jobs := make(chan scheduledJob)
 	triggers := schedule.newTriggers()
 	done := make(chan struct{})
 
@@ -84,7 +132,7 @@ func (schedule *Periodic) Subscribe(ctx context.Context, apply func(projection.J
 	var wg sync.WaitGroup
 	wg.Add(2)
 
-	go schedule.handleTicker(ctx, cfg, ticker, jobs, out, &wg)
+	go schedule.handleTicker(ctx, cfg, prog, ticker, jobs, out, &wg)
 	go schedule.handleTriggers(ctx, cfg, triggers, jobs, out, &wg)
 	go schedule.applyJobs(ctx, apply, jobs, out, done)
 
@@ -99,8 +147,9 @@ func (schedule *Periodic) Subscribe(ctx context.Context, apply func(projection.J
 func (schedule *Periodic) handleTicker(
 	ctx context.Context,
 	sub projection.Subscription,
-	ticker *time.Ticker,
-	jobs chan<- projection.Job,
+	prog *progress,
+	ticker *stdtime.Ticker,
+	jobs chan<- scheduledJob,
 	out chan<- error,
 	wg *sync.WaitGroup,
 ) {
@@ -110,21 +159,136 @@ func (schedule *Periodic) handleTicker(
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			job := schedule.newJob(
-				ctx,
-				sub,
-				schedule.store,
-				query.New(
-					query.Name(schedule.eventNames...),
-					query.SortByTime(),
-				),
-			)
+			if prog != nil && !prog.start() {
+				continue
+			}
+
+			job := schedule.newJob(ctx, sub, schedule.store, schedule.buildQuery(prog))
+			if prog != nil {
+				job = &tickJob{Job: job}
+			}
 
 			select {
 			case <-ctx.Done():
 				return
-			case jobs <- job:
+			case jobs <- scheduledJob{job: job}:
 			}
 		}
 	}
 }
+
+// buildQuery returns the event query for a Job that is created by the ticker.
+// If prog is non-nil, only events after the latest applied event are queried.
+func (schedule *Periodic) buildQuery(prog *progress) event.Query {
+	opts := []query.Option{
+		query.Name(schedule.eventNames...),
+		query.SortByTime(),
+	}
+
+	if prog != nil {
+		if latest := prog.get(); !latest.IsZero() {
+			// Subtract a nanosecond for the same reason as projection.Job does:
+			// events that have the same time as the progress but were not yet
+			// applied must not be excluded.
+			opts = append(opts, query.Time(time.After(latest.Add(-stdtime.Nanosecond))))
+		}
+	}
+
+	return query.New(opts...)
+}
+
+// track wraps the given apply function. When a Job that was created by the
+// ticker is applied successfully, the progress of the projections that the Job
+// was applied to is stored.
+func (p *progress) track(apply func(projection.Job) error) func(projection.Job) error {
+	return func(job projection.Job) error {
+		tj, ok := job.(*tickJob)
+		if !ok {
+			return apply(job)
+		}
+
+		defer p.finish()
+
+		if err := apply(tj); err != nil {
+			return err
+		}
+
+		if latest, ok := tj.progress(); ok {
+			p.update(latest)
+		}
+
+		return nil
+	}
+}
+
+// Apply applies the Job to target and records the progress of target.
+func (j *tickJob) Apply(ctx context.Context, target projection.Target[any], opts ...projection.ApplyOption) error {
+	var result projection.Result
+	opts = append(opts[:len(opts):len(opts)], projection.OnResult(func(r projection.Result) { result = r }))
+
+	if err := j.Job.Apply(ctx, target, opts...); err != nil {
+		return err
+	}
+
+	if progressor, ok := target.(projection.ProgressAware); ok {
+		latest, _ := progressor.Progress()
+		j.record(latest)
+		return nil
+	}
+
+	// Projections that did not apply any event do not hold back the progress.
+	if result.Applied > 0 {
+		j.record(result.LastEventTime)
+	}
+
+	return nil
+}
+
+// record records the progress of a projection the Job was applied to. The
+// earliest progress of all projections is kept.
+func (j *tickJob) record(latest stdtime.Time) {
+	j.mux.Lock()
+	defer j.mux.Unlock()
+	if !j.applied || latest.Before(j.latest) {
+		j.latest = latest
+	}
+	j.applied = true
+}
+
+// progress returns the recorded progress, or false if the Job was not applied
+// to any projection.
+func (j *tickJob) progress() (stdtime.Time, bool) {
+	j.mux.Lock()
+	defer j.mux.Unlock()
+	return j.latest, j.applied
+}
+
+func (p *progress) start() bool {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if p.pending {
+		return false
+	}
+	p.pending = true
+	return true
+}
+
+func (p *progress) finish() {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.pending = false
+}
+
+func (p *progress) get() stdtime.Time {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	return p.latest
+}
+
+func (p *progress) update(t stdtime.Time) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if t.After(p.latest) {
+		p.latest = t
+	}
+}
@@ -143,3 +143,153 @@ L:
 		t.Fatalf("projection job should return aggregate %q", name)
 	}
 }
+
+func TestPeriodic_Subscribe_Progressive(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := eventstore.New()
+
+	now := time.Now()
+	events := []event.Event{
+		event.New[any]("foo", test.FooEventData{}, event.Time(now)),
+		event.New[any]("bar", test.FooEventData{}, event.Time(now.Add(time.Millisecond))),
+		event.New[any]("foobar", test.FooEventData{}, event.Time(now.Add(2*time.Millisecond))),
+	}
+
+	if err := store.Insert(ctx, events...); err != nil {
+		t.Fatalf("insert events: %v", err)
+	}
+
+	sch := schedule.Periodically(store, 20*time.Millisecond, []string{"foo", "bar"})
+
+	proj := projectiontest.NewMockProgressor()
+	applied := make(chan []event.Event)
+
+	errs, err := sch.Subscribe(ctx, func(job projection.Job) error {
+		before := len(proj.AppliedEvents)
+		if err := job.Apply(job, proj); err != nil {
+			return err
+		}
+		select {
+		case <-job.Done():
+		case applied <- proj.AppliedEvents[before:]:
+		}
+		return nil
+	}, projection.Progressive())
+	if err != nil {
+		t.Fatalf("Subscribe() failed with %q", err)
+	}
+
+	receive := func() []event.Event {
+		select {
+		case <-time.After(time.Second):
+			t.Fatalf("timed out")
+		case err := <-errs:
+			t.Fatal(err)
+		case events := <-applied:
+			return events
+		}
+		return nil
+	}
+
+	if events := receive(); len(events) != 2 {
+		t.Fatalf("first Job should apply %d events; got %d", 2, len(events))
+	}
+
+	if events := receive(); len(events) != 0 {
+		t.Fatalf("second Job should apply %d events; got %d", 0, len(events))
+	}
+
+	// An event that has the same time as the progress but is inserted later
+	// must not be skipped.
+	late := event.New[any]("foo", test.FooEventData{}, event.Time(now.Add(time.Millisecond)))
+	if err := store.Insert(ctx, late); err != nil {
+		t.Fatalf("insert event: %v", err)
+	}
+
+	var got []event.Event
+	for len(got) == 0 {
+		got = receive()
+	}
+
+	if len(got) != 1 || got[0].ID() != late.ID() {
+		t.Fatalf("Job should only apply the late event; got %v", got)
+	}
+
+	evt := event.New[any]("foo", test.FooEventData{}, event.Time(now.Add(3*time.Millisecond)))
+	if err := store.Insert(ctx, evt); err != nil {
+		t.Fatalf("insert event: %v", err)
+	}
+
+	got = nil
+	for len(got) == 0 {
+		got = receive()
+	}
+
+	if len(got) != 1 || got[0].ID() != evt.ID() {
+		t.Fatalf("Job should only apply the new event; got %v", got)
+	}
+}
+
+func TestPeriodic_Subscribe_Progressive_notProgressAware(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := eventstore.New()
+
+	now := time.Now()
+	if err := store.Insert(ctx, event.New[any]("foo", test.FooEventData{}, event.Time(now))); err != nil {
+		t.Fatalf("insert events: %v", err)
+	}
+
+	sch := schedule.Periodically(store, 20*time.Millisecond, []string{"foo"})
+
+	queried := make(chan []event.Event)
+
+	errs, err := sch.Subscribe(ctx, func(job projection.Job) error {
+		proj := projectiontest.NewMockProjection()
+		if err := job.Apply(job, proj); err != nil {
+			return err
+		}
+		select {
+		case <-job.Done():
+		case queried <- proj.AppliedEvents:
+		}
+		return nil
+	}, projection.Progressive())
+	if err != nil {
+		t.Fatalf("Subscribe() failed with %q", err)
+	}
+
+	receive := func() []event.Event {
+		select {
+		case <-time.After(time.Second):
+			t.Fatalf("timed out")
+		case err := <-errs:
+			t.Fatal(err)
+		case events := <-queried:
+			return events
+		}
+		return nil
+	}
+
+	if events := receive(); len(events) != 1 {
+		t.Fatalf("first Job should apply %d event; got %d", 1, len(events))
+	}
+
+	evt := event.New[any]("foo", test.FooEventData{}, event.Time(now.Add(time.Millisecond)))
+	if err := store.Insert(ctx, evt); err != nil {
+		t.Fatalf("insert event: %v", err)
+	}
+
+	var got []event.Event
+	for len(got) < 2 {
+		got = receive()
+	}
+
+	// The event at the time of the progress is queried again.
+	if len(got) != 2 || got[1].ID() != evt.ID() {
+		t.Fatalf("Job should apply the event at the time of the progress and the new event; got %v", got)
+	}
+}
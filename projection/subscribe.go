@@ -17,6 +17,10 @@ type Subscription struct {
 	// BeforeEvent are the "before"-interceptors for the event streams created
 	// by a job's `EventsFor()` and `Apply()` methods.
 	BeforeEvent []func(context.Context, event.Event) ([]event.Event, error)
+
+	// If true, periodic schedules only query the events that happened after
+	// the events of the previously applied job.
+	Progressive bool
 }
 
 // Startup returns a SubscribeOption that triggers an initial projection run
@@ -52,6 +56,28 @@ func BeforeEvent[Data any](fn func(context.Context, event.Of[Data]) ([]event.Eve
 	}
 }
 
+// Progressive returns a SubscribeOption that makes a periodic projection
+// schedule progressive. Instead of querying all events on every tick, a
+// progressive schedule remembers the progress of each successfully applied job
+// and only queries the events that happened after that time on the next tick.
+// This turns a periodic projection into an efficient catch-up loop.
+//
+// The progress of a projection is the time of its Progress() if it implements
+// ProgressAware, or the time of the latest event that was applied to it
+// otherwise. If a job is applied to multiple projections, the earliest
+// progress of these projections is remembered, so that no projection misses
+// events. Projections that did not apply any event of a job do not hold back
+// the progress.
+//
+// Events are expected to be inserted in chronological order. An event that is
+// inserted into the event store after a newer event has already been applied
+// is skipped by subsequent ticks.
+func Progressive() SubscribeOption {
+	return func(s *Subscription) {
+		s.Progressive = true
+	}
+}
+
 // NewSubscription creates a Subscription using the provided options.
 func NewSubscription(opts ...SubscribeOption) Subscription {
 	var sub Subscription
@@ -0,0 +1,44 @@
+package test
+
+import (
+	"time"
+
+	"github.com/modernice/goes/internal/xtime"
+)
+
+// Clock provides the current time. goes uses a Clock to timestamp events,
+// snapshots and other time-based values. In production, goes always uses the
+// real clock.
+type Clock = xtime.Clock
+
+// FrozenClock is a Clock that always returns the same Time until it is moved
+// using Set or Add.
+type FrozenClock = xtime.FrozenClock
+
+// Cleaner registers cleanup functions. *testing.T and *testing.B implement
+// Cleaner.
+type Cleaner interface {
+	Cleanup(func())
+}
+
+// SetClock replaces the Clock of goes for the duration of the test t. The
+// previous Clock is restored when t and its subtests have completed. Tests
+// that call SetClock must not run in parallel with other tests that depend on
+// the Clock.
+func SetClock(t Cleaner, c Clock) {
+	t.Cleanup(xtime.SetClock(c))
+}
+
+// WithFrozenClock replaces the Clock of goes with a FrozenClock that is frozen
+// at the given Time, for the duration of the test t. Use the returned
+// FrozenClock to move the clock:
+//
+//	clock := test.WithFrozenClock(t, time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+//	a := event.New("foo", ...) // a.Time() == 2022-01-01
+//	clock.Add(time.Hour)
+//	b := event.New("foo", ...) // b.Time() == 2022-01-01 01:00
+func WithFrozenClock(t Cleaner, at time.Time) *FrozenClock {
+	c := xtime.Frozen(at)
+	SetClock(t, c)
+	return c
+}
@@ -0,0 +1,50 @@
+package test_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/modernice/goes/internal/xtime"
+	"github.com/modernice/goes/test"
+)
+
+func TestWithFrozenClock(t *testing.T) {
+	at := time.Date(2022, 1, 1, 12, 0, 0, 123456789, time.UTC)
+
+	t.Run("frozen", func(t *testing.T) {
+		clock := test.WithFrozenClock(t, at)
+
+		if now := xtime.Now(); !now.Equal(at) {
+			t.Fatalf("Now() should return %v; got %v", at, now)
+		}
+
+		want := at.Add(time.Hour)
+		if got := clock.Add(time.Hour); !got.Equal(want) {
+			t.Fatalf("Add() should return %v; got %v", want, got)
+		}
+
+		if now := xtime.Now(); !now.Equal(want) {
+			t.Fatalf("Now() should return %v after Add(); got %v", want, now)
+		}
+	})
+
+	if now := xtime.Now(); now.Equal(at) || now.Equal(at.Add(time.Hour)) {
+		t.Fatalf("the real clock should be restored after the test; got %v", now)
+	}
+}
+
+func TestSetClock(t *testing.T) {
+	at := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("set", func(t *testing.T) {
+		test.SetClock(t, xtime.Frozen(at))
+
+		if now := xtime.Now(); !now.Equal(at) {
+			t.Fatalf("Now() should return %v; got %v", at, now)
+		}
+	})
+
+	if now := xtime.Now(); now.Equal(at) {
+		t.Fatalf("the real clock should be restored after the test; got %v", now)
+	}
+}